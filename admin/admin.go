@@ -0,0 +1,394 @@
+// Package admin provides an HTTP API to operate a running cron.Cron.
+//
+// The handler serves JSON on the following routes:
+//
+//	GET    /entries              list entries, filtered by the tag, namespace,
+//	                             status, next_after, next_before, offset and
+//	                             limit query parameters, and sorted by the
+//	                             sort and order ones
+//	POST   /entries              add a job (a cron.JobConfig document)
+//	GET    /entries/{id}         inspect an entry
+//	PUT    /entries/{id}         replace the job of an entry (a cron.JobConfig
+//	                             document), if it is at the revision of the
+//	                             If-Match header
+//	DELETE /entries/{id}         remove an entry
+//	POST   /entries/{id}/pause   pause an entry
+//	POST   /entries/{id}/resume  resume a paused entry
+//	POST   /entries/{id}/enable  enable an entry disabled or quarantined
+//	POST   /entries/{id}/run     run an entry now
+//	GET    /entries/{id}/history latest runs of an entry
+//	GET    /stats                run statistics of every entry
+//	GET    /scheduler            statistics of the scheduler
+//	GET    /quarantine           entries disabled for failing or panicking
+//	GET    /events               stream of scheduler events (Server-Sent Events)
+//	GET    /openapi.json         the OpenAPI document of the API
+//
+// The {id} segment is path escaped, so that ids containing "/" can be
+// addressed as %2F. Package client provides a Go client for these routes.
+//
+// Mount it under a prefix with http.StripPrefix, and protect it with
+// RequireAuth since it can add and remove arbitrary jobs.
+//
+// The entries are served with their revision as ETag, so that concurrent
+// updates are detected: a PUT whose If-Match header is not the current
+// revision fails with 412 Precondition Failed.
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ringtail/go-cron"
+)
+
+// Entry is the JSON representation of a cron entry.
+type Entry struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name,omitempty"`
+	Spec    string            `json:"spec,omitempty"`
+	Type    string            `json:"type,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+	Paused  bool              `json:"paused"`
+	Status  string            `json:"status"`
+	Breaker string            `json:"breaker,omitempty"`
+	Prev    time.Time         `json:"prev"`
+	Next    time.Time         `json:"next"`
+	Stats   cron.EntryStats   `json:"stats"`
+	// Revision is the revision the entry was added or last replaced at.
+	Revision uint64 `json:"revision"`
+}
+
+type handler struct {
+	cron *cron.Cron
+}
+
+// NewHandler returns an http.Handler serving the admin API of c.
+func NewHandler(c *cron.Cron) http.Handler {
+	return &handler{c}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case path == "entries":
+		switch r.Method {
+		case http.MethodGet:
+			h.list(w, r)
+		case http.MethodPost:
+			h.add(w, r)
+		default:
+			methodNotAllowed(w, "GET, POST")
+		}
+	case strings.HasPrefix(path, "entries/"):
+		escaped := strings.TrimPrefix(strings.Trim(r.URL.EscapedPath(), "/"), "entries/")
+		action := ""
+		if i := strings.LastIndexByte(escaped, '/'); i >= 0 {
+			escaped, action = escaped[:i], escaped[i+1:]
+		}
+		id, err := url.PathUnescape(escaped)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid entry id")
+			return
+		}
+		h.entry(w, r, id, action)
+	case path == "stats":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		h.stats(w, r)
+	case path == "scheduler":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		writeJSON(w, http.StatusOK, h.cron.SchedulerStats())
+	case path == "quarantine":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		writeJSON(w, http.StatusOK, h.cron.Quarantined())
+	case path == "events":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		h.events(w, r)
+	case path == "openapi.json":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(OpenAPI))
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *handler) entry(w http.ResponseWriter, r *http.Request, id, action string) {
+	e, ok := h.cron.Entry(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "entry "+id+" not found")
+		return
+	}
+
+	var err error
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", etag(e.Revision))
+			writeJSON(w, http.StatusOK, h.entryJSON(e))
+		case http.MethodPut:
+			h.update(w, r, id)
+		case http.MethodDelete:
+			if err := h.cron.Remove(id); err != nil {
+				writeError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w, "GET, PUT, DELETE")
+		}
+		return
+	case "history":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, "GET")
+			return
+		}
+		writeJSON(w, http.StatusOK, h.cron.History(id))
+		return
+	case "pause":
+		err = post(w, r, func() error { return h.cron.Pause(id) })
+	case "resume":
+		err = post(w, r, func() error { return h.cron.Resume(id) })
+	case "enable":
+		err = post(w, r, func() error { return h.cron.Enable(id) })
+	case "run":
+		err = post(w, r, func() error { return h.cron.RunNow(id) })
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if errors.Is(err, cron.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, cron.ErrObserver) {
+		writeError(w, http.StatusConflict, err.Error())
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// post runs an action for a POST request and acknowledges it.
+func post(w http.ResponseWriter, r *http.Request, action func() error) error {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, "POST")
+		return nil
+	}
+	if err := action(); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if len(q) == 0 {
+		writeJSON(w, http.StatusOK, Entries(h.cron))
+		return
+	}
+	filter, err := entryFilter(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	entries, total, err := h.cron.EntriesFiltered(filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, EntryOf(h.cron, e))
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeJSON(w, http.StatusOK, out)
+}
+
+// entryFilter parses the query parameters of GET /entries.
+func entryFilter(q url.Values) (cron.EntryFilter, error) {
+	filter := cron.EntryFilter{
+		Tag:       q.Get("tag"),
+		Namespace: q.Get("namespace"),
+		Status:    cron.EntryStatus(q.Get("status")),
+		Sort:      cron.EntrySort(q.Get("sort")),
+	}
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, fmt.Errorf("invalid order %q", q.Get("order"))
+	}
+	for name, t := range map[string]*time.Time{"next_after": &filter.NextAfter, "next_before": &filter.NextBefore} {
+		if v := q.Get(name); v != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, err)
+			}
+		}
+	}
+	for name, n := range map[string]*int{"offset": &filter.Offset, "limit": &filter.Limit} {
+		if v := q.Get(name); v != "" {
+			var err error
+			if *n, err = strconv.Atoi(v); err != nil || *n < 0 {
+				return filter, fmt.Errorf("invalid %s %q", name, v)
+			}
+		}
+	}
+	return filter, nil
+}
+
+func (h *handler) add(w http.ResponseWriter, r *http.Request) {
+	var jc cron.JobConfig
+	if err := json.NewDecoder(r.Body).Decode(&jc); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job: "+err.Error())
+		return
+	}
+	if err := h.cron.AddJobConfig(jc); errors.Is(err, cron.ErrDuplicateJob) {
+		writeError(w, http.StatusConflict, "entry "+jc.Name+" already exists")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	e, ok := h.cron.Entry(jc.Name)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "entry "+jc.Name+" vanished")
+		return
+	}
+	w.Header().Set("ETag", etag(e.Revision))
+	writeJSON(w, http.StatusCreated, h.entryJSON(e))
+}
+
+// update replaces the job of the entry with the given id by the job config
+// of the request, if the entry is at the revision of its If-Match header.
+func (h *handler) update(w http.ResponseWriter, r *http.Request, id string) {
+	var jc cron.JobConfig
+	if err := json.NewDecoder(r.Body).Decode(&jc); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job: "+err.Error())
+		return
+	}
+	if jc.Name == "" {
+		jc.Name = id
+	} else if jc.Name != id {
+		writeError(w, http.StatusBadRequest, "job "+jc.Name+" can not replace entry "+id)
+		return
+	}
+	var revision uint64
+	if match := r.Header.Get("If-Match"); match != "" {
+		var err error
+		revision, err = strconv.ParseUint(strings.Trim(match, `"`), 10, 64)
+		if err != nil || revision == 0 {
+			writeError(w, http.StatusBadRequest, "invalid If-Match")
+			return
+		}
+	}
+	if _, err := h.cron.UpdateJob(jc, revision); errors.Is(err, cron.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	} else if errors.Is(err, cron.ErrConflict) {
+		writeError(w, http.StatusPreconditionFailed, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	e, ok := h.cron.Entry(id)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "entry "+id+" vanished")
+		return
+	}
+	w.Header().Set("ETag", etag(e.Revision))
+	writeJSON(w, http.StatusOK, h.entryJSON(e))
+}
+
+// etag returns the ETag of an entry at the given revision.
+func etag(revision uint64) string {
+	return strconv.Quote(strconv.FormatUint(revision, 10))
+}
+
+func (h *handler) stats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Stats(h.cron))
+}
+
+func (h *handler) entryJSON(e *cron.Entry) Entry {
+	return EntryOf(h.cron, e)
+}
+
+// Entries returns the entries of c as served by GET /entries.
+func Entries(c *cron.Cron) []Entry {
+	entries := c.Entries()
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, EntryOf(c, e))
+	}
+	return out
+}
+
+// Stats returns the run statistics of every entry of c by ID, as served by
+// GET /stats.
+func Stats(c *cron.Cron) map[string]cron.EntryStats {
+	stats := make(map[string]cron.EntryStats)
+	for _, e := range c.Entries() {
+		stats[e.ID] = c.Stats(e.ID)
+	}
+	return stats
+}
+
+// EntryOf returns the JSON representation of the entry e of c.
+func EntryOf(c *cron.Cron, e *cron.Entry) Entry {
+	out := Entry{
+		ID:      e.ID,
+		Name:    e.Name,
+		Spec:    e.Spec,
+		Tags:    e.Tags,
+		Paused:  e.Paused,
+		Status:  string(e.Status),
+		Breaker: string(e.Breaker),
+		Prev:    e.Prev,
+		Next:    e.Next,
+		Stats:   c.Stats(e.ID),
+
+		Revision: e.Revision,
+	}
+	if dj, ok := e.Job.(cron.DescribedJob); ok {
+		out.Type, out.Params = dj.JobType(), dj.Params()
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
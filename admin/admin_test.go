@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+)
+
+type noopJob struct {
+	id  string
+	ran chan struct{}
+}
+
+func (j *noopJob) ID() string                { return j.id }
+func (j *noopJob) JobType() string           { return "noop" }
+func (j *noopJob) Params() map[string]string { return nil }
+
+func (j *noopJob) Run() (string, error) {
+	if j.ran != nil {
+		j.ran <- struct{}{}
+	}
+	return "ok", nil
+}
+
+var ran = make(chan struct{}, 10)
+
+func init() {
+	cron.RegisterJobType("noop", func(id string, params map[string]string) (cron.Job, error) {
+		return &noopJob{id, ran}, nil
+	})
+}
+
+func newTestServer(t *testing.T) (*cron.Cron, *httptest.Server) {
+	c := cron.New()
+	c.AddResultHandler(func(*cron.JobResult) {})
+	c.Start()
+	srv := httptest.NewServer(NewHandler(c))
+	t.Cleanup(func() {
+		srv.Close()
+		c.Stop()
+	})
+	return c, srv
+}
+
+func do(t *testing.T, method, url, body string, expected int, out interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != expected {
+		t.Fatalf("%s %s: expected status %d, got %d", method, url, expected, resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEntriesCRUD(t *testing.T) {
+	c, srv := newTestServer(t)
+
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "bogus", "type": "noop"}`, http.StatusBadRequest, nil)
+
+	var created Entry
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "@hourly", "type": "noop"}`, http.StatusCreated, &created)
+	if created.ID != "job" || created.Type != "noop" || created.Next.IsZero() {
+		t.Errorf("unexpected created entry %+v", created)
+	}
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "@hourly", "type": "noop"}`, http.StatusConflict, nil)
+
+	var list []Entry
+	do(t, "GET", srv.URL+"/entries", "", http.StatusOK, &list)
+	if len(list) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(list))
+	}
+
+	do(t, "POST", srv.URL+"/entries/job/pause", "", http.StatusAccepted, nil)
+	var inspected Entry
+	do(t, "GET", srv.URL+"/entries/job", "", http.StatusOK, &inspected)
+	if !inspected.Paused {
+		t.Error("expected entry to be paused")
+	}
+	do(t, "POST", srv.URL+"/entries/job/resume", "", http.StatusAccepted, nil)
+
+	do(t, "DELETE", srv.URL+"/entries/job", "", http.StatusNoContent, nil)
+	do(t, "GET", srv.URL+"/entries/job", "", http.StatusNotFound, nil)
+	if len(c.Entries()) != 0 {
+		t.Error("expected entry to be removed")
+	}
+}
+
+func TestEscapedEntryID(t *testing.T) {
+	_, srv := newTestServer(t)
+	do(t, "POST", srv.URL+"/entries", `{"name": "db/backup", "spec": "@hourly", "type": "noop"}`, http.StatusCreated, nil)
+
+	var e Entry
+	do(t, "GET", srv.URL+"/entries/db%2Fbackup", "", http.StatusOK, &e)
+	if e.ID != "db/backup" {
+		t.Errorf("expected entry db/backup, got %+v", e)
+	}
+	do(t, "POST", srv.URL+"/entries/db%2Fbackup/pause", "", http.StatusAccepted, nil)
+	do(t, "DELETE", srv.URL+"/entries/db%2Fbackup", "", http.StatusNoContent, nil)
+	do(t, "DELETE", srv.URL+"/entries/db%2Fbackup", "", http.StatusNotFound, nil)
+}
+
+func TestListEntriesFiltered(t *testing.T) {
+	_, srv := newTestServer(t)
+	for _, body := range []string{
+		`{"name": "a", "spec": "@hourly", "type": "noop", "tags": ["db"]}`,
+		`{"name": "b", "spec": "@daily", "type": "noop", "tags": ["db"]}`,
+		`{"name": "c", "spec": "@weekly", "type": "noop"}`,
+	} {
+		do(t, "POST", srv.URL+"/entries", body, http.StatusCreated, nil)
+	}
+
+	resp, err := http.Get(srv.URL + "/entries?tag=db&offset=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var entries []Entry
+	json.NewDecoder(resp.Body).Decode(&entries)
+	if len(entries) != 1 || entries[0].ID != "b" || resp.Header.Get("X-Total-Count") != "2" {
+		t.Errorf("unexpected entries %+v of %s", entries, resp.Header.Get("X-Total-Count"))
+	}
+
+	do(t, "GET", srv.URL+"/entries?sort=name&order=desc", "", http.StatusOK, &entries)
+	if len(entries) != 3 || entries[0].ID != "c" || entries[2].ID != "a" {
+		t.Errorf("expected the entries by name descending, got %+v", entries)
+	}
+
+	do(t, "GET", srv.URL+"/entries?limit=x", "", http.StatusBadRequest, nil)
+	do(t, "GET", srv.URL+"/entries?sort=color", "", http.StatusBadRequest, nil)
+	do(t, "GET", srv.URL+"/entries?order=up", "", http.StatusBadRequest, nil)
+	do(t, "GET", srv.URL+"/entries?next_after=tomorrow", "", http.StatusBadRequest, nil)
+}
+
+func TestUpdateEntry(t *testing.T) {
+	_, srv := newTestServer(t)
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "@hourly", "type": "noop"}`, http.StatusCreated, nil)
+
+	resp, err := http.Get(srv.URL + "/entries/job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	tag := resp.Header.Get("ETag")
+
+	update := func(match, body string, expected int) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest("PUT", srv.URL+"/entries/job", strings.NewReader(body))
+		if match != "" {
+			req.Header.Set("If-Match", match)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Fatalf("If-Match %s: expected status %d, got %d", match, expected, resp.StatusCode)
+		}
+		return resp
+	}
+	resp = update(tag, `{"spec": "@daily", "type": "noop"}`, http.StatusOK)
+	if resp.Header.Get("ETag") == tag {
+		t.Errorf("expected a new ETag, got %s", tag)
+	}
+	update(tag, `{"spec": "@weekly", "type": "noop"}`, http.StatusPreconditionFailed)
+	update("", `{"spec": "@weekly", "type": "noop"}`, http.StatusOK)
+	update("soon", `{"spec": "@weekly", "type": "noop"}`, http.StatusBadRequest)
+	update("", `{"name": "other", "spec": "@weekly", "type": "noop"}`, http.StatusBadRequest)
+
+	var e Entry
+	do(t, "GET", srv.URL+"/entries/job", "", http.StatusOK, &e)
+	if e.Spec != "@weekly" || e.Revision == 0 {
+		t.Errorf("unexpected updated entry %+v", e)
+	}
+}
+
+func TestRunNowAndHistory(t *testing.T) {
+	_, srv := newTestServer(t)
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "@yearly", "type": "noop"}`, http.StatusCreated, nil)
+	do(t, "POST", srv.URL+"/entries/job/run", "", http.StatusAccepted, nil)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected job to run")
+	}
+
+	var history []cron.RunRecord
+	deadline := time.Now().Add(time.Second)
+	for len(history) == 0 && time.Now().Before(deadline) {
+		do(t, "GET", srv.URL+"/entries/job/history", "", http.StatusOK, &history)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(history) != 1 || history[0].Msg != "ok" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	var stats map[string]cron.EntryStats
+	do(t, "GET", srv.URL+"/stats", "", http.StatusOK, &stats)
+	if stats["job"].Runs != 1 {
+		t.Errorf("expected 1 run in stats, got %+v", stats)
+	}
+
+	var sched cron.SchedulerStats
+	do(t, "GET", srv.URL+"/scheduler", "", http.StatusOK, &sched)
+	if sched.Entries != 1 || sched.Dispatches != 1 {
+		t.Errorf("unexpected scheduler stats %+v", sched)
+	}
+
+	var quarantine []cron.QuarantineRecord
+	do(t, "GET", srv.URL+"/quarantine", "", http.StatusOK, &quarantine)
+	if len(quarantine) != 0 {
+		t.Errorf("expected no quarantined entry, got %+v", quarantine)
+	}
+}
+
+func TestUnknownRoutes(t *testing.T) {
+	_, srv := newTestServer(t)
+	do(t, "GET", srv.URL+"/nope", "", http.StatusNotFound, nil)
+	do(t, "PUT", srv.URL+"/entries", "", http.StatusMethodNotAllowed, nil)
+	do(t, "POST", srv.URL+"/entries/missing/run", "", http.StatusNotFound, nil)
+}
+
+func TestOpenAPI(t *testing.T) {
+	_, srv := newTestServer(t)
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	do(t, "GET", srv.URL+"/openapi.json", "", http.StatusOK, &doc)
+	for _, path := range []string{
+		"/entries", "/entries/{id}", "/entries/{id}/pause", "/entries/{id}/resume",
+		"/entries/{id}/run", "/entries/{id}/enable", "/entries/{id}/history", "/stats", "/scheduler", "/quarantine", "/events",
+	} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("path %s is not documented", path)
+		}
+	}
+	if doc.OpenAPI == "" || len(doc.Paths) != 11 {
+		t.Errorf("unexpected document %+v", doc)
+	}
+}
@@ -0,0 +1,396 @@
+package admin
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Role is the set of operations a principal may perform. Each role includes
+// the permissions of the roles below it.
+type Role int
+
+const (
+	// RoleReader may list and inspect entries.
+	RoleReader Role = iota + 1
+	// RoleOperator may also pause, resume and run entries.
+	RoleOperator
+	// RoleAdmin may also add and remove entries.
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{RoleReader: "reader", RoleOperator: "operator", RoleAdmin: "admin"}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// ParseRole returns the role named "reader", "operator" or "admin".
+func ParseRole(name string) (Role, error) {
+	for r, n := range roleNames {
+		if n == name {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown role %s", name)
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// Credentials are presented by a caller.
+type Credentials struct {
+	// Token is a bearer token or an API key.
+	Token string
+	// Certificates is the verified client certificate chain, leaf first.
+	Certificates []*x509.Certificate
+}
+
+// An Authenticator identifies the caller presenting credentials. It returns
+// a nil Principal and a nil error if the credentials are not meant for it,
+// so several authenticators can be combined.
+type Authenticator interface {
+	Authenticate(ctx context.Context, creds Credentials) (*Principal, error)
+}
+
+// ErrUnauthenticated is returned when no authenticator accepts the
+// credentials.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticate tries each authenticator in turn.
+func Authenticate(ctx context.Context, creds Credentials, auths ...Authenticator) (*Principal, error) {
+	for _, a := range auths {
+		p, err := a.Authenticate(ctx, creds)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			return p, nil
+		}
+	}
+	return nil, ErrUnauthenticated
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal of an authenticated request.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// WithPrincipal returns a context carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// RequireAuth wraps an admin handler so requests must be authenticated by
+// one of auths and have the role needed by the route: reading needs
+// RoleReader, pausing, resuming and running entries needs RoleOperator and
+// anything else RoleAdmin.
+//
+// Tokens are read from the "Authorization: Bearer" or "X-API-Key" header and
+// certificates from the verified TLS chain.
+func RequireAuth(h http.Handler, auths ...Authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var creds Credentials
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			creds.Token = strings.TrimSpace(auth[len("Bearer "):])
+		} else {
+			creds.Token = r.Header.Get("X-API-Key")
+		}
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			creds.Certificates = r.TLS.VerifiedChains[0]
+		}
+
+		p, err := Authenticate(r.Context(), creds, auths...)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if need := requiredRole(r); p.Role < need {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("%s requires the %s role", p.Name, need))
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+func requiredRole(r *http.Request) Role {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return RoleReader
+	}
+	path := strings.TrimRight(r.URL.EscapedPath(), "/")
+	if r.Method == http.MethodPost {
+		for _, action := range []string{"/pause", "/resume", "/run"} {
+			if strings.HasSuffix(path, action) {
+				return RoleOperator
+			}
+		}
+	}
+	return RoleAdmin
+}
+
+// APIKeyAuth authenticates callers by API key.
+type APIKeyAuth map[string]Principal
+
+func (a APIKeyAuth) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if creds.Token == "" {
+		return nil, nil
+	}
+	var found *Principal
+	for key, p := range a {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(creds.Token)) == 1 {
+			p := p
+			found = &p
+		}
+	}
+	return found, nil
+}
+
+// CertAuth authenticates callers by the common name of their verified
+// client certificate, mapped to a role. The server must request and verify
+// client certificates, e.g. with tls.RequireAndVerifyClientCert.
+type CertAuth map[string]Role
+
+func (a CertAuth) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if len(creds.Certificates) == 0 {
+		return nil, nil
+	}
+	name := creds.Certificates[0].Subject.CommonName
+	role, ok := a[name]
+	if !ok {
+		return nil, fmt.Errorf("Certificate %s is not authorized", name)
+	}
+	return &Principal{Name: name, Role: role}, nil
+}
+
+// OIDCAuth authenticates callers by OpenID Connect ID tokens (JWTs) signed
+// with RS256 or ES256 by the issuer. The signing keys are discovered from
+// the issuer's /.well-known/openid-configuration.
+//
+// The principal is named after the "sub" claim and gets the highest role
+// listed in RoleClaim.
+type OIDCAuth struct {
+	Issuer   string
+	Audience string
+	// RoleClaim is the claim holding the role names of the caller. It
+	// defaults to "roles".
+	RoleClaim string
+	Client    *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// NewOIDCAuth returns an authenticator of tokens issued by issuer for
+// audience.
+func NewOIDCAuth(issuer, audience string) *OIDCAuth {
+	return &OIDCAuth{Issuer: strings.TrimRight(issuer, "/"), Audience: audience}
+}
+
+func (a *OIDCAuth) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	parts := strings.Split(creds.Token, ".")
+	if len(parts) != 3 {
+		return nil, nil
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token signature: %s", err)
+	}
+	key, err := a.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(header.Alg, key, digest[:], sig) {
+		return nil, fmt.Errorf("Invalid token signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("Invalid token claims: %s", err)
+	}
+	if claims["iss"] != a.Issuer {
+		return nil, fmt.Errorf("Token issued by %v, not %s", claims["iss"], a.Issuer)
+	}
+	if !hasAudience(claims["aud"], a.Audience) {
+		return nil, fmt.Errorf("Token is not meant for %s", a.Audience)
+	}
+	now := float64(time.Now().Unix())
+	if exp, ok := claims["exp"].(float64); !ok || now > exp {
+		return nil, fmt.Errorf("Token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return nil, fmt.Errorf("Token not valid yet")
+	}
+
+	claim := a.RoleClaim
+	if claim == "" {
+		claim = "roles"
+	}
+	p := &Principal{}
+	p.Name, _ = claims["sub"].(string)
+	roles, _ := claims[claim].([]interface{})
+	for _, name := range roles {
+		if s, ok := name.(string); ok {
+			if r, err := ParseRole(s); err == nil && r > p.Role {
+				p.Role = r
+			}
+		}
+	}
+	if p.Role == 0 {
+		return nil, fmt.Errorf("Token of %s grants no role", p.Name)
+	}
+	return p, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func verifySignature(alg string, key crypto.PublicKey, digest, sig []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256" && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(sig) != 64 {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// key returns the signing key with the given id, refreshing the key set at
+// most once a minute when the id is unknown.
+func (a *OIDCAuth) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(a.fetched) < time.Minute {
+		return nil, fmt.Errorf("Unknown signing key %s", kid)
+	}
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch signing keys: %s", err)
+	}
+	a.keys, a.fetched = keys, time.Now()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("Unknown signing key %s", kid)
+}
+
+func (a *OIDCAuth) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := a.get(ctx, a.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := a.get(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range set.Keys {
+		switch {
+		case k.Kty == "RSA":
+			n, err1 := base64.RawURLEncoding.DecodeString(k.N)
+			e, err2 := base64.RawURLEncoding.DecodeString(k.E)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case k.Kty == "EC" && k.Crv == "P-256":
+			x, err1 := base64.RawURLEncoding.DecodeString(k.X)
+			y, err2 := base64.RawURLEncoding.DecodeString(k.Y)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	return keys, nil
+}
+
+func (a *OIDCAuth) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
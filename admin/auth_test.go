@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+)
+
+func TestRequireAuth(t *testing.T) {
+	c := cron.New()
+	h := RequireAuth(NewHandler(c),
+		APIKeyAuth{"r": {Name: "ro", Role: RoleReader}, "o": {Name: "ops", Role: RoleOperator}, "a": {Name: "root", Role: RoleAdmin}},
+		CertAuth{"deployer": RoleAdmin},
+	)
+	c.AddJob("@hourly", &noopJob{id: "job"})
+
+	tests := []struct {
+		method, path, key string
+		cert              string
+		expected          int
+	}{
+		{"GET", "/entries", "", "", http.StatusUnauthorized},
+		{"GET", "/entries", "bogus", "", http.StatusUnauthorized},
+		{"GET", "/entries", "r", "", http.StatusOK},
+		{"POST", "/entries/job/pause", "r", "", http.StatusForbidden},
+		{"POST", "/entries/job/pause", "o", "", http.StatusAccepted},
+		{"DELETE", "/entries/job", "o", "", http.StatusForbidden},
+		{"GET", "/entries", "", "intruder", http.StatusUnauthorized},
+		{"DELETE", "/entries/job", "", "deployer", http.StatusNoContent},
+		{"DELETE", "/entries/job", "a", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		if test.key != "" {
+			req.Header.Set("X-API-Key", test.key)
+		}
+		if test.cert != "" {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: test.cert}}
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != test.expected {
+			t.Errorf("%s %s (key %q, cert %q): expected %d, got %d", test.method, test.path, test.key, test.cert, test.expected, w.Code)
+		}
+	}
+}
+
+func TestOIDCAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issuer string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"jwks_uri": issuer + "/keys"})
+		case "/keys":
+			json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}}})
+		}
+	}))
+	defer srv.Close()
+	issuer = srv.URL
+
+	sign := func(claims map[string]interface{}) string {
+		header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+		payload, _ := json.Marshal(claims)
+		signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(signed))
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	a := NewOIDCAuth(issuer, "cron")
+
+	p, err := a.Authenticate(context.Background(), Credentials{Token: sign(map[string]interface{}{
+		"iss": issuer, "aud": []string{"cron"}, "sub": "alice", "exp": exp, "roles": []string{"reader", "operator"},
+	})})
+	if err != nil || p == nil || p.Name != "alice" || p.Role != RoleOperator {
+		t.Fatalf("Authenticate() = %+v, %v", p, err)
+	}
+
+	for _, claims := range []map[string]interface{}{
+		{"iss": issuer, "aud": "other", "sub": "a", "exp": exp, "roles": []string{"admin"}},
+		{"iss": "https://evil", "aud": "cron", "sub": "a", "exp": exp, "roles": []string{"admin"}},
+		{"iss": issuer, "aud": "cron", "sub": "a", "exp": time.Now().Add(-time.Minute).Unix(), "roles": []string{"admin"}},
+		{"iss": issuer, "aud": "cron", "sub": "a", "exp": exp},
+	} {
+		if p, err := a.Authenticate(context.Background(), Credentials{Token: sign(claims)}); err == nil {
+			t.Errorf("expected %v to be rejected, got %+v", claims, p)
+		}
+	}
+
+	token := sign(map[string]interface{}{"iss": issuer, "aud": "cron", "sub": "a", "exp": exp, "roles": []string{"admin"}})
+	if _, err := a.Authenticate(context.Background(), Credentials{Token: token[:len(token)-4] + "AAAA"}); err == nil {
+		t.Error("expected a forged signature to be rejected")
+	}
+	if p, err := a.Authenticate(context.Background(), Credentials{Token: "api-key"}); p != nil || err != nil {
+		t.Errorf("expected API keys to be ignored, got %+v, %v", p, err)
+	}
+}
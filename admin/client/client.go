@@ -0,0 +1,274 @@
+// Package client is a Go client for the admin API served by package admin.
+// Its methods map one to one to the operations of admin.OpenAPI.
+//
+//	c := client.New("http://localhost:8080")
+//	entries, err := c.ListEntries()
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin"
+)
+
+// Client calls the admin API at BaseURL.
+type Client struct {
+	BaseURL string
+	// HTTPClient sends the requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// New returns a client of the admin API at baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Error is returned when the API answers with an error status.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ListEntries returns the entries, ordered by next activation.
+func (c *Client) ListEntries() ([]admin.Entry, error) {
+	var entries []admin.Entry
+	return entries, c.do("GET", "/entries", nil, &entries)
+}
+
+// ListEntriesFiltered returns the entries matching the filter, in the order
+// of the filter, and the number of matching entries before the offset and
+// limit of the filter.
+func (c *Client) ListEntriesFiltered(f cron.EntryFilter) ([]admin.Entry, int, error) {
+	q := url.Values{}
+	for name, v := range map[string]string{"tag": f.Tag, "namespace": f.Namespace, "status": string(f.Status), "sort": string(f.Sort)} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	if !f.NextAfter.IsZero() {
+		q.Set("next_after", f.NextAfter.Format(time.RFC3339))
+	}
+	if !f.NextBefore.IsZero() {
+		q.Set("next_before", f.NextBefore.Format(time.RFC3339))
+	}
+	if f.Descending {
+		q.Set("order", "desc")
+	}
+	if f.Offset > 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if len(q) == 0 {
+		// Without parameters, the server does not count the entries.
+		entries, err := c.ListEntries()
+		return entries, len(entries), err
+	}
+	var entries []admin.Entry
+	header, err := c.send("GET", "/entries?"+q.Encode(), nil, nil, &entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, _ := strconv.Atoi(header.Get("X-Total-Count"))
+	return entries, total, nil
+}
+
+// AddJob adds a job and returns its entry.
+func (c *Client) AddJob(jc cron.JobConfig) (*admin.Entry, error) {
+	var e admin.Entry
+	if err := c.do("POST", "/entries", jc, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetEntry returns the entry with the given id.
+func (c *Client) GetEntry(id string) (*admin.Entry, error) {
+	var e admin.Entry
+	if err := c.do("GET", entryPath(id, ""), nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// UpdateEntry replaces the job of the entry named by jc and returns the
+// updated entry. If revision is not zero, the update fails with a 412 Error
+// unless the entry is at this revision, e.g. the Revision of the entry as
+// last read, so that concurrent updates do not overwrite each other.
+func (c *Client) UpdateEntry(jc cron.JobConfig, revision uint64) (*admin.Entry, error) {
+	header := make(http.Header)
+	if revision != 0 {
+		header.Set("If-Match", strconv.Quote(strconv.FormatUint(revision, 10)))
+	}
+	var e admin.Entry
+	if _, err := c.send("PUT", entryPath(jc.Name, ""), header, jc, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// RemoveEntry removes the entry with the given id.
+func (c *Client) RemoveEntry(id string) error {
+	return c.do("DELETE", entryPath(id, ""), nil, nil)
+}
+
+// PauseEntry pauses the entry with the given id.
+func (c *Client) PauseEntry(id string) error {
+	return c.do("POST", entryPath(id, "pause"), nil, nil)
+}
+
+// ResumeEntry resumes the paused entry with the given id.
+func (c *Client) ResumeEntry(id string) error {
+	return c.do("POST", entryPath(id, "resume"), nil, nil)
+}
+
+// RunEntry runs the entry with the given id now.
+func (c *Client) RunEntry(id string) error {
+	return c.do("POST", entryPath(id, "run"), nil, nil)
+}
+
+// EntryHistory returns the latest runs of the entry with the given id.
+func (c *Client) EntryHistory(id string) ([]cron.RunRecord, error) {
+	var runs []cron.RunRecord
+	return runs, c.do("GET", entryPath(id, "history"), nil, &runs)
+}
+
+// Stats returns the run statistics of every entry by ID.
+func (c *Client) Stats() (map[string]cron.EntryStats, error) {
+	var stats map[string]cron.EntryStats
+	return stats, c.do("GET", "/stats", nil, &stats)
+}
+
+// SchedulerStats returns the statistics of the scheduler.
+func (c *Client) SchedulerStats() (cron.SchedulerStats, error) {
+	var stats cron.SchedulerStats
+	return stats, c.do("GET", "/scheduler", nil, &stats)
+}
+
+// Events streams scheduler events to fn until ctx is done, fn returns an
+// error or the server closes the stream. An empty entry streams the events of
+// every entry.
+func (c *Client) Events(ctx context.Context, entry string, fn func(cron.Event) error) error {
+	path := "/events"
+	if entry != "" {
+		path += "?entry=" + url.QueryEscape(entry)
+	}
+	req, err := http.NewRequest("GET", c.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return responseError("GET", path, resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var e cron.Event
+		if err := json.Unmarshal([]byte(strings.TrimSpace(line[len("data:"):])), &e); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+func entryPath(id, action string) string {
+	path := "/entries/" + url.PathEscape(id)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	_, err := c.send(method, path, nil, body, out)
+	return err
+}
+
+// send sends a request like do, with the given headers, and returns the
+// headers of the response.
+func (c *Client) send(method, path string, header http.Header, body, out interface{}) (http.Header, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.BaseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, responseError(method, path, resp)
+	}
+	if out != nil {
+		return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+	}
+	return resp.Header, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// responseError decodes the error of a failed request.
+func responseError(method, path string, resp *http.Response) error {
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&apiErr) != nil || apiErr.Error == "" {
+		apiErr.Error = fmt.Sprintf("%s %s: %s", method, path, resp.Status)
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: apiErr.Error}
+}
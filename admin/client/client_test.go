@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin"
+)
+
+type noopJob struct{ id string }
+
+func (j *noopJob) ID() string                { return j.id }
+func (j *noopJob) JobType() string           { return "noop" }
+func (j *noopJob) Params() map[string]string { return nil }
+func (j *noopJob) Run() (string, error)      { return "ok", nil }
+
+func init() {
+	cron.RegisterJobType("noop", func(id string, params map[string]string) (cron.Job, error) {
+		return &noopJob{id}, nil
+	})
+}
+
+func TestClient(t *testing.T) {
+	c := cron.New()
+	srv := httptest.NewServer(admin.NewHandler(c))
+	defer srv.Close()
+	cl := New(srv.URL + "/")
+
+	e, err := cl.AddJob(cron.JobConfig{Name: "a b", Spec: "@hourly", Type: "noop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "a b" || e.Type != "noop" {
+		t.Errorf("added %+v", e)
+	}
+	if _, err := cl.AddJob(cron.JobConfig{Name: "a b", Spec: "@hourly", Type: "noop"}); err == nil || err.(*Error).StatusCode != http.StatusConflict {
+		t.Errorf("err = %v, want conflict", err)
+	}
+
+	if err := cl.PauseEntry("a b"); err != nil {
+		t.Fatal(err)
+	}
+	if e, err := cl.GetEntry("a b"); err != nil || !e.Paused {
+		t.Errorf("GetEntry() = %+v, %v", e, err)
+	}
+	if err := cl.ResumeEntry("a b"); err != nil {
+		t.Fatal(err)
+	}
+	if e, err = cl.UpdateEntry(cron.JobConfig{Name: "a b", Spec: "@daily", Type: "noop"}, e.Revision); err != nil || e.Spec != "@daily" {
+		t.Errorf("UpdateEntry() = %+v, %v", e, err)
+	}
+	if _, err := cl.UpdateEntry(cron.JobConfig{Name: "a b", Spec: "@weekly", Type: "noop"}, 1); err == nil || err.(*Error).StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("err = %v, want precondition failed", err)
+	}
+	entries, err := cl.ListEntries()
+	if err != nil || len(entries) != 1 {
+		t.Errorf("ListEntries() = %v, %v", entries, err)
+	}
+	if entries, total, err := cl.ListEntriesFiltered(cron.EntryFilter{Status: cron.EntryScheduled, Limit: 1}); err != nil || len(entries) != 1 || total != 1 {
+		t.Errorf("ListEntriesFiltered() = %v, %d, %v", entries, total, err)
+	}
+	if _, err := cl.Stats(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := cl.SchedulerStats(); err != nil || s.Entries != 1 {
+		t.Errorf("SchedulerStats() = %+v, %v", s, err)
+	}
+	if runs, err := cl.EntryHistory("a b"); err != nil || len(runs) != 0 {
+		t.Errorf("EntryHistory() = %v, %v", runs, err)
+	}
+
+	if err := cl.RemoveEntry("a b"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = cl.GetEntry("a b")
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "entry a b not found" {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	c := cron.New()
+	c.AddResultHandler(func(*cron.JobResult) {})
+	c.AddJob("@yearly", &noopJob{"job"})
+	c.Start()
+	defer c.Stop()
+	srv := httptest.NewServer(admin.NewHandler(c))
+	defer srv.Close()
+	cl := New(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan cron.Event, 1)
+	done := make(chan error)
+	go func() {
+		done <- cl.Events(ctx, "job", func(e cron.Event) error {
+			if e.Type == cron.EventJobFinished {
+				finished <- e
+			}
+			return nil
+		})
+	}()
+
+	// Run the job until the stream is established and sees it finish.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if err := cl.RunEntry("job"); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case e := <-finished:
+			if e.EntryID != "job" || e.Msg != "ok" {
+				t.Errorf("unexpected event %+v", e)
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Events() = %v", err)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	t.Fatal("expected a job_finished event")
+}
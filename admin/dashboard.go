@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// NewDashboard returns an http.Handler serving a single page UI on top of the
+// admin API mounted at apiBase (e.g. "/cron/api"). The page lists entries
+// with their next and last runs, colors them by status, offers pause, resume
+// and run-now buttons, and shows recent failures.
+func NewDashboard(apiBase string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, "GET, HEAD")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboard.Execute(w, struct{ API string }{apiBase})
+	})
+}
+
+var dashboard = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>cron</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #ddd; }
+tr.ok td:first-child { border-left: 4px solid #2a2; }
+tr.failing td:first-child { border-left: 4px solid #c22; }
+tr.paused td:first-child { border-left: 4px solid #999; }
+tr.idle td:first-child { border-left: 4px solid #ccc; }
+tr.paused { color: #888; }
+.error { color: #c22; }
+button { margin-right: .3em; }
+</style>
+</head>
+<body>
+<h1>Entries</h1>
+<table>
+<thead><tr><th>ID</th><th>Spec</th><th>Next run</th><th>Last run</th><th>Runs</th><th>Failures</th><th></th></tr></thead>
+<tbody id="entries"></tbody>
+</table>
+<h2>Recent failures</h2>
+<table>
+<thead><tr><th>ID</th><th>When</th><th>Error</th></tr></thead>
+<tbody id="failures"></tbody>
+</table>
+<script>
+var api = {{.API}};
+
+function fmt(t) {
+	return !t || t.indexOf("0001-") === 0 ? "-" : new Date(t).toLocaleString();
+}
+
+function cell(row, text, cls) {
+	var td = row.insertCell();
+	td.textContent = text;
+	if (cls) td.className = cls;
+	return td;
+}
+
+function button(td, label, id, action) {
+	var b = document.createElement("button");
+	b.textContent = label;
+	b.onclick = function() {
+		fetch(api + "/entries/" + encodeURIComponent(id) + "/" + action, {method: "POST"}).then(refresh);
+	};
+	td.appendChild(b);
+}
+
+function refresh() {
+	fetch(api + "/entries").then(function(r) { return r.json(); }).then(function(entries) {
+		var body = document.getElementById("entries");
+		var failures = document.getElementById("failures");
+		body.innerHTML = "";
+		failures.innerHTML = "";
+		entries.forEach(function(e) {
+			var row = body.insertRow();
+			row.className = e.paused ? "paused" : e.stats.last_error ? "failing" : e.stats.runs ? "ok" : "idle";
+			cell(row, e.name ? e.name + " (" + e.id + ")" : e.id);
+			cell(row, e.spec || "-");
+			cell(row, fmt(e.next));
+			cell(row, fmt(e.stats.last_run));
+			cell(row, e.stats.runs);
+			cell(row, e.stats.failures);
+			var actions = row.insertCell();
+			button(actions, e.paused ? "Resume" : "Pause", e.id, e.paused ? "resume" : "pause");
+			button(actions, "Run now", e.id, "run");
+		});
+		entries.filter(function(e) { return e.stats.last_error; })
+			.sort(function(a, b) { return a.stats.last_run < b.stats.last_run ? 1 : -1; })
+			.forEach(function(e) {
+				var row = failures.insertRow();
+				cell(row, e.id);
+				cell(row, fmt(e.stats.last_run));
+				cell(row, e.stats.last_error, "error");
+			});
+	});
+}
+
+refresh();
+// Refresh on scheduler events, and fall back to polling without them.
+if (window.EventSource) {
+	new EventSource(api + "/events").onmessage = refresh;
+}
+setInterval(refresh, 30000);
+</script>
+</body>
+</html>
+`))
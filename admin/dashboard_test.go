@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewDashboard("/cron/api").ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `var api = "/cron/api";`) {
+		t.Errorf("expected the API base to be embedded, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	NewDashboard("/api").ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", rec.Code)
+	}
+}
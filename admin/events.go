@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ringtail/go-cron"
+)
+
+// eventBuffer is the number of events buffered per stream.
+const eventBuffer = 64
+
+// keepAlive is the interval of the comments sent on idle streams, so proxies
+// do not close them.
+const keepAlive = 15 * time.Second
+
+// events streams scheduler events as Server-Sent Events. Each message holds
+// a cron.Event in JSON. The "entry" query parameter restricts the stream to
+// the events of one entry.
+func (h *handler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	entry := r.URL.Query().Get("entry")
+	events, cancel := h.cron.SubscribeEvents(eventBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case e := <-events:
+			if entry != "" && e.EntryID != entry {
+				continue
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e cron.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ringtail/go-cron"
+)
+
+func TestEventStream(t *testing.T) {
+	_, srv := newTestServer(t)
+	do(t, "POST", srv.URL+"/entries", `{"name": "job", "spec": "@yearly", "type": "noop"}`, http.StatusCreated, nil)
+	do(t, "POST", srv.URL+"/entries", `{"name": "other", "spec": "@yearly", "type": "noop"}`, http.StatusCreated, nil)
+
+	resp, err := http.Get(srv.URL + "/events?entry=job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", ct)
+	}
+
+	do(t, "POST", srv.URL+"/entries/other/run", "", http.StatusAccepted, nil)
+	<-ran
+	do(t, "POST", srv.URL+"/entries/job/run", "", http.StatusAccepted, nil)
+	<-ran
+
+	r := bufio.NewReader(resp.Body)
+	var events []cron.Event
+	for len(events) < 2 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var e cron.Event
+		if err := json.Unmarshal([]byte(line[len("data: "):]), &e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	if events[0].Type != cron.EventJobStarted || events[1].Type != cron.EventJobFinished ||
+		events[1].EntryID != "job" || events[1].Msg != "ok" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
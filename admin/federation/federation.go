@@ -0,0 +1,367 @@
+// Package federation aggregates several schedulers, in process or remote,
+// behind a single management API, so a fleet of per-region schedulers can be
+// operated from one place.
+//
+//	f := federation.New()
+//	f.AddLocal("local", c)
+//	f.AddRemote("eu-west", client.New("http://eu-west:8080/cron"))
+//	http.Handle("/fleet/", http.StripPrefix("/fleet", f))
+//
+// The handler serves JSON on the following routes:
+//
+//	GET /instances                  the members and whether they answer
+//	GET /entries                    the entries of every member
+//	GET /stats                      the run statistics of every member
+//	GET /events                     merged stream of scheduler events
+//	*   /instances/{name}/{route}   any admin route of one member
+//
+// Aggregated reads are best effort: a member that fails to answer is
+// reported in the "errors" field of the response instead of failing the
+// whole request.
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin"
+	"github.com/ringtail/go-cron/admin/client"
+)
+
+// Entry is an entry of a member.
+type Entry struct {
+	Instance string `json:"instance"`
+	admin.Entry
+}
+
+// Event is a scheduler event of a member.
+type Event struct {
+	Instance string `json:"instance"`
+	cron.Event
+}
+
+// Instance describes a member of the federation.
+type Instance struct {
+	Name    string `json:"name"`
+	Remote  string `json:"remote,omitempty"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+	Entries int    `json:"entries"`
+}
+
+// ReconnectDelay is the delay before the event stream of a remote member is
+// reopened after it ended.
+var ReconnectDelay = 5 * time.Second
+
+// eventBuffer is the number of events buffered per stream.
+const eventBuffer = 64
+
+// member is a scheduler of the federation.
+type member interface {
+	entries() ([]admin.Entry, error)
+	stats() (map[string]cron.EntryStats, error)
+	// events streams the events of the member to fn until ctx is done.
+	events(ctx context.Context, fn func(cron.Event)) error
+	// handler serves the admin API of the member.
+	handler() http.Handler
+	remote() string
+}
+
+// Federation is a set of named schedulers. It is safe for concurrent use.
+type Federation struct {
+	mu      sync.RWMutex
+	names   []string
+	members map[string]member
+}
+
+// New returns an empty federation.
+func New() *Federation {
+	return &Federation{members: make(map[string]member)}
+}
+
+// AddLocal adds the scheduler c, running in this process, under name.
+func (f *Federation) AddLocal(name string, c *cron.Cron) error {
+	return f.add(name, &localMember{c, admin.NewHandler(c)})
+}
+
+// AddRemote adds the scheduler served by the admin API cl talks to under
+// name. Requests to the member go through cl.HTTPClient, which must carry
+// the credentials the remote API requires.
+func (f *Federation) AddRemote(name string, cl *client.Client) error {
+	target, err := url.Parse(cl.BaseURL)
+	if err != nil {
+		return fmt.Errorf("Invalid URL of instance %s: %w", name, err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	if cl.HTTPClient != nil && cl.HTTPClient.Transport != nil {
+		proxy.Transport = cl.HTTPClient.Transport
+	}
+	return f.add(name, &remoteMember{cl, proxy})
+}
+
+func (f *Federation) add(name string, m member) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("Invalid instance name %q", name)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.members[name]; ok {
+		return fmt.Errorf("Instance %s already exists", name)
+	}
+	f.members[name] = m
+	f.names = append(f.names, name)
+	sort.Strings(f.names)
+	return nil
+}
+
+// Remove removes the member with the given name.
+func (f *Federation) Remove(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.members[name]; !ok {
+		return
+	}
+	delete(f.members, name)
+	for i, n := range f.names {
+		if n == name {
+			f.names = append(f.names[:i], f.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// snapshot returns the members by name, in name order.
+func (f *Federation) snapshot() ([]string, []member) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := append([]string(nil), f.names...)
+	members := make([]member, len(names))
+	for i, name := range names {
+		members[i] = f.members[name]
+	}
+	return names, members
+}
+
+// each calls fn for every member concurrently and collects the errors by
+// member name.
+func each(names []string, members []member, fn func(i int, name string, m member) error) map[string]error {
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i, names[i], members[i])
+		}(i)
+	}
+	wg.Wait()
+
+	failed := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			failed[names[i]] = err
+		}
+	}
+	return failed
+}
+
+// Entries returns the entries of every member ordered by next activation,
+// and the errors of the members that failed to answer.
+func (f *Federation) Entries() ([]Entry, map[string]error) {
+	var (
+		mu  sync.Mutex
+		out []Entry
+	)
+	names, members := f.snapshot()
+	errs := each(names, members, func(_ int, name string, m member) error {
+		entries, err := m.entries()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, e := range entries {
+			out = append(out, Entry{name, e})
+		}
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Next.IsZero() != b.Next.IsZero() {
+			return b.Next.IsZero()
+		}
+		if !a.Next.Equal(b.Next) {
+			return a.Next.Before(b.Next)
+		}
+		if a.Instance != b.Instance {
+			return a.Instance < b.Instance
+		}
+		return a.ID < b.ID
+	})
+	return out, errs
+}
+
+// Stats returns the run statistics of every member by member name and entry
+// ID, and the errors of the members that failed to answer.
+func (f *Federation) Stats() (map[string]map[string]cron.EntryStats, map[string]error) {
+	var mu sync.Mutex
+	out := make(map[string]map[string]cron.EntryStats)
+	names, members := f.snapshot()
+	errs := each(names, members, func(_ int, name string, m member) error {
+		stats, err := m.stats()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		out[name] = stats
+		mu.Unlock()
+		return nil
+	})
+	return out, errs
+}
+
+// Instances returns the members and whether they answer.
+func (f *Federation) Instances() []Instance {
+	names, members := f.snapshot()
+	out := make([]Instance, len(names))
+	each(names, members, func(i int, name string, m member) error {
+		out[i] = Instance{Name: name, Remote: m.remote()}
+		entries, err := m.entries()
+		if err != nil {
+			out[i].Error = err.Error()
+			return nil
+		}
+		out[i].Healthy, out[i].Entries = true, len(entries)
+		return nil
+	})
+	return out
+}
+
+// Events streams the events of every member present when it is called to
+// fn until ctx is done or fn returns an error. The streams of remote members
+// are reopened after ReconnectDelay when they end.
+func (f *Federation) Events(ctx context.Context, fn func(Event) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	ch := make(chan Event, eventBuffer)
+	names, members := f.snapshot()
+	for i := range names {
+		wg.Add(1)
+		go func(name string, m member) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				m.events(ctx, func(e cron.Event) {
+					select {
+					case ch <- Event{name, e}:
+					case <-ctx.Done():
+					}
+				})
+				select {
+				case <-time.After(ReconnectDelay):
+				case <-ctx.Done():
+				}
+			}
+		}(names[i], members[i])
+	}
+
+	for {
+		select {
+		case e := <-ch:
+			if err := fn(e); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// localMember is a scheduler running in this process.
+type localMember struct {
+	cron  *cron.Cron
+	admin http.Handler
+}
+
+func (m *localMember) entries() ([]admin.Entry, error) {
+	return admin.Entries(m.cron), nil
+}
+
+func (m *localMember) stats() (map[string]cron.EntryStats, error) {
+	return admin.Stats(m.cron), nil
+}
+
+func (m *localMember) events(ctx context.Context, fn func(cron.Event)) error {
+	events, cancel := m.cron.SubscribeEvents(eventBuffer)
+	defer cancel()
+	for {
+		select {
+		case e := <-events:
+			fn(e)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *localMember) handler() http.Handler { return m.admin }
+func (m *localMember) remote() string        { return "" }
+
+// remoteMember is a scheduler reached through its admin API.
+type remoteMember struct {
+	client *client.Client
+	proxy  *httputil.ReverseProxy
+}
+
+func (m *remoteMember) entries() ([]admin.Entry, error) {
+	return m.client.ListEntries()
+}
+
+func (m *remoteMember) stats() (map[string]cron.EntryStats, error) {
+	return m.client.Stats()
+}
+
+func (m *remoteMember) events(ctx context.Context, fn func(cron.Event)) error {
+	return m.client.Events(ctx, "", func(e cron.Event) error {
+		fn(e)
+		return nil
+	})
+}
+
+func (m *remoteMember) handler() http.Handler { return m.proxy }
+func (m *remoteMember) remote() string        { return m.client.BaseURL }
+
+// errorStrings returns the messages of errs, or nil when there are none.
+func errorStrings(errs map[string]error) map[string]string {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(errs))
+	for name, err := range errs {
+		out[name] = err.Error()
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
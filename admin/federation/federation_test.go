@@ -0,0 +1,208 @@
+package federation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin"
+	"github.com/ringtail/go-cron/admin/client"
+)
+
+type testJob string
+
+func (j testJob) ID() string           { return string(j) }
+func (j testJob) Run() (string, error) { return "ok", nil }
+
+func newScheduler(t *testing.T, ids ...string) *cron.Cron {
+	c := cron.New()
+	for _, id := range ids {
+		if err := c.AddJob("@hourly", testJob(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func newFederation(t *testing.T) (*Federation, *cron.Cron, *cron.Cron) {
+	local, remote := newScheduler(t, "a", "b"), newScheduler(t, "c")
+	srv := httptest.NewServer(admin.NewHandler(remote))
+	t.Cleanup(srv.Close)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	f := New()
+	if err := f.AddLocal("local", local); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddRemote("remote", client.New(srv.URL)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddRemote("down", client.New(down.URL)); err != nil {
+		t.Fatal(err)
+	}
+	return f, local, remote
+}
+
+func TestFederationAggregates(t *testing.T) {
+	f, _, _ := newFederation(t)
+	if err := f.AddLocal("local", cron.New()); err == nil {
+		t.Error("expected an error for a duplicate instance")
+	}
+
+	entries, errs := f.Entries()
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Instance+"/"+e.ID)
+	}
+	if strings.Join(got, " ") != "local/a local/b remote/c" {
+		t.Errorf("entries = %v", got)
+	}
+	if len(errs) != 1 || errs["down"] == nil {
+		t.Errorf("errors = %v, want the down instance", errs)
+	}
+
+	stats, errs := f.Stats()
+	if len(stats) != 2 || len(stats["local"]) != 2 || len(stats["remote"]) != 1 || len(errs) != 1 {
+		t.Errorf("stats = %v, errors = %v", stats, errs)
+	}
+
+	instances := f.Instances()
+	if len(instances) != 3 ||
+		instances[0].Name != "down" || instances[0].Healthy || instances[0].Error == "" ||
+		instances[1].Name != "local" || !instances[1].Healthy || instances[1].Entries != 2 ||
+		instances[2].Name != "remote" || !instances[2].Healthy || instances[2].Remote == "" {
+		t.Errorf("instances = %+v", instances)
+	}
+
+	f.Remove("down")
+	if _, errs := f.Entries(); len(errs) != 0 {
+		t.Errorf("errors = %v after removing the down instance", errs)
+	}
+}
+
+func TestFederationHandler(t *testing.T) {
+	f, local, remote := newFederation(t)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	var entries struct {
+		Entries []Entry           `json:"entries"`
+		Errors  map[string]string `json:"errors"`
+	}
+	get(t, srv.URL+"/entries", http.StatusOK, &entries)
+	if len(entries.Entries) != 3 || entries.Entries[2].Instance != "remote" || entries.Errors["down"] == "" {
+		t.Errorf("GET /entries = %+v", entries)
+	}
+
+	for _, c := range []struct {
+		instance string
+		cron     *cron.Cron
+		id       string
+	}{{"local", local, "a"}, {"remote", remote, "c"}} {
+		resp, err := http.Post(srv.URL+"/instances/"+c.instance+"/entries/"+c.id+"/pause", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("pause %s/%s: status %d", c.instance, c.id, resp.StatusCode)
+		}
+		if e, _ := c.cron.Entry(c.id); !e.Paused {
+			t.Errorf("%s/%s not paused", c.instance, c.id)
+		}
+	}
+
+	var e admin.Entry
+	get(t, srv.URL+"/instances/remote/entries/c", http.StatusOK, &e)
+	if e.ID != "c" || !e.Paused {
+		t.Errorf("GET /instances/remote/entries/c = %+v", e)
+	}
+	for _, c := range []struct {
+		instance string
+		cron     *cron.Cron
+	}{{"local", local}, {"remote", remote}} {
+		c.cron.AddJob("@hourly", testJob("db/backup"))
+		get(t, srv.URL+"/instances/"+c.instance+"/entries/db%2Fbackup", http.StatusOK, &e)
+		if e.ID != "db/backup" {
+			t.Errorf("GET /instances/%s/entries/db%%2Fbackup = %+v", c.instance, e)
+		}
+	}
+	get(t, srv.URL+"/instances/nope/entries", http.StatusNotFound, nil)
+	get(t, srv.URL+"/bogus", http.StatusNotFound, nil)
+}
+
+func TestFederationEvents(t *testing.T) {
+	f, local, remote := newFederation(t)
+	f.Remove("down")
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequest("GET", srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The remote stream opens asynchronously: run until its events show up.
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	seen := make(map[string]bool)
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for !seen["local/a"] || !seen["remote/c"] {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream ended, saw %v", seen)
+			}
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var e Event
+			if err := json.Unmarshal([]byte(line[len("data: "):]), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.Type == cron.EventJobFinished {
+				seen[e.Instance+"/"+e.EntryID] = true
+			}
+		case <-tick.C:
+			local.RunNow("a")
+			remote.RunNow("c")
+		case <-ctx.Done():
+			t.Fatalf("timed out, saw %v", seen)
+		}
+	}
+}
+
+func get(t *testing.T, url string, status int, out interface{}) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		t.Fatalf("GET %s: status %d, want %d", url, resp.StatusCode, status)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
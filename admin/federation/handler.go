@@ -0,0 +1,143 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// keepAlive is the interval of the comments sent on idle event streams.
+const keepAlive = 15 * time.Second
+
+// ServeHTTP serves the management API of the federation.
+func (f *Federation) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if strings.HasPrefix(path, "instances/") {
+		f.forward(w, r, strings.TrimPrefix(strings.Trim(r.URL.EscapedPath(), "/"), "instances/"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	switch path {
+	case "instances":
+		writeJSON(w, http.StatusOK, f.Instances())
+	case "entries":
+		entries, errs := f.Entries()
+		if entries == nil {
+			entries = []Entry{}
+		}
+		writeJSON(w, http.StatusOK, struct {
+			Entries []Entry           `json:"entries"`
+			Errors  map[string]string `json:"errors,omitempty"`
+		}{entries, errorStrings(errs)})
+	case "stats":
+		stats, errs := f.Stats()
+		writeJSON(w, http.StatusOK, struct {
+			Stats  interface{}       `json:"stats"`
+			Errors map[string]string `json:"errors,omitempty"`
+		}{stats, errorStrings(errs)})
+	case "events":
+		f.events(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// forward passes a request to the admin API of one member, with the
+// instance prefix stripped from its path, given escaped.
+func (f *Federation) forward(w http.ResponseWriter, r *http.Request, path string) {
+	name, rest := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		name, rest = path[:i], path[i:]
+	}
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid instance name")
+		return
+	}
+	rest = "/" + strings.TrimPrefix(rest, "/")
+	unescaped, err := url.PathUnescape(rest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	f.mu.RLock()
+	m, ok := f.members[name]
+	f.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "instance "+name+" not found")
+		return
+	}
+
+	r2 := r.Clone(r.Context())
+	r2.URL.Path, r2.URL.RawPath = unescaped, rest
+	m.handler().ServeHTTP(w, r2)
+}
+
+// events streams the events of every member as Server-Sent Events. Each
+// message holds an Event in JSON. The "instance" query parameter restricts
+// the stream to one member.
+func (f *Federation) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	instance := r.URL.Query().Get("instance")
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	events := make(chan Event)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.Events(ctx, func(e Event) error {
+			if instance != "" && e.Instance != instance {
+				return nil
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-events:
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
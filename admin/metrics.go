@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ringtail/go-cron"
+)
+
+// NewMetricsHandler returns an http.Handler serving the scheduler metrics of
+// c in the Prometheus text exposition format.
+func NewMetricsHandler(c *cron.Cron) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeMetrics(w, c)
+	})
+}
+
+// ListenAndServeMetrics serves the metrics of c on addr at /metrics, for
+// services that have no HTTP server of their own.
+func ListenAndServeMetrics(addr string, c *cron.Cron) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", NewMetricsHandler(c))
+	return http.ListenAndServe(addr, mux)
+}
+
+type metric struct {
+	name, help, typ string
+	value           func(e *cron.Entry, s cron.EntryStats) float64
+}
+
+var entryMetrics = []metric{
+	{"cron_entry_runs_total", "Number of runs of the entry.", "counter",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return float64(s.Runs) }},
+	{"cron_entry_failures_total", "Number of failed runs of the entry.", "counter",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return float64(s.Failures) }},
+	{"cron_entry_cpu_seconds_total", "CPU time used by the runs of the entry.", "counter",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return s.CPUTime.Seconds() }},
+	{"cron_entry_last_duration_seconds", "Duration of the last run of the entry.", "gauge",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return s.LastDuration.Seconds() }},
+	{"cron_entry_last_run_timestamp_seconds", "Start time of the last run of the entry.", "gauge",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return timestamp(s.LastRun) }},
+	{"cron_entry_next_run_timestamp_seconds", "Next activation time of the entry.", "gauge",
+		func(e *cron.Entry, s cron.EntryStats) float64 { return timestamp(e.Next) }},
+	{"cron_entry_paused", "Whether the entry is paused.", "gauge",
+		func(e *cron.Entry, s cron.EntryStats) float64 {
+			if e.Paused {
+				return 1
+			}
+			return 0
+		}},
+}
+
+func writeMetrics(w http.ResponseWriter, c *cron.Cron) {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	entries := c.Entries()
+	stats := make([]cron.EntryStats, len(entries))
+	for i, e := range entries {
+		stats[i] = c.Stats(e.Job.ID())
+	}
+
+	fmt.Fprintf(bw, "# HELP cron_entries Number of scheduled entries.\n# TYPE cron_entries gauge\ncron_entries %d\n", len(entries))
+	q := c.ResultQueue()
+	fmt.Fprintf(bw, "# HELP cron_result_queue_depth Number of results queued in memory for the result handler.\n# TYPE cron_result_queue_depth gauge\ncron_result_queue_depth %d\n", q.Queued)
+	fmt.Fprintf(bw, "# HELP cron_result_queue_spilled Number of results spilled to disk for the result handler.\n# TYPE cron_result_queue_spilled gauge\ncron_result_queue_spilled %d\n", q.Spilled)
+	fmt.Fprintf(bw, "# HELP cron_results_dropped_total Number of results dropped by the result queue.\n# TYPE cron_results_dropped_total counter\ncron_results_dropped_total %d\n", q.Dropped)
+	for _, m := range entryMetrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+		for i, e := range entries {
+			fmt.Fprintf(bw, "%s{id=\"%s\"} %g\n", m.name, escapeLabel(e.Job.ID()), m.value(e, stats[i]))
+		}
+	}
+}
+
+func timestamp(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
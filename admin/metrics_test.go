@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ringtail/go-cron"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	c := cron.New()
+	if err := c.AddJobConfig(cron.JobConfig{Name: `say "hi"`, Spec: "@hourly", Type: "noop"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Pause(`say "hi"`)
+
+	rec := httptest.NewRecorder()
+	NewMetricsHandler(c).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"cron_entries 1\n",
+		"cron_result_queue_depth 0\n",
+		"# TYPE cron_results_dropped_total counter\n",
+		"# TYPE cron_entry_runs_total counter\n",
+		`cron_entry_runs_total{id="say \"hi\""} 0` + "\n",
+		`cron_entry_paused{id="say \"hi\""} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package admin
+
+// OpenAPI is the OpenAPI 3 document describing the admin API. It is served
+// by the handler at /openapi.json.
+const OpenAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "go-cron admin API",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/entries": {
+      "get": {
+        "operationId": "listEntries",
+        "summary": "List entries",
+        "parameters": [
+          {"name": "tag", "in": "query", "schema": {"type": "string"}},
+          {"name": "namespace", "in": "query", "schema": {"type": "string"}},
+          {"name": "status", "in": "query", "schema": {"type": "string", "enum": ["scheduled", "paused", "running", "disabled"]}},
+          {"name": "next_after", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "next_before", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["next", "name", "last_duration", "failures", "tag"], "default": "next"}},
+          {"name": "order", "in": "query", "schema": {"type": "string", "enum": ["asc", "desc"], "default": "asc"}},
+          {"name": "offset", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The matching entries, ordered by next activation unless sorted otherwise.",
+            "headers": {"X-Total-Count": {"description": "The number of matching entries, before the offset and limit.", "schema": {"type": "integer"}}},
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Entry"}}}}
+          },
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "addJob",
+        "summary": "Add a job",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/JobConfig"}}}
+        },
+        "responses": {
+          "201": {
+            "description": "The added entry.",
+            "headers": {"ETag": {"$ref": "#/components/headers/ETag"}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Entry"}}}
+          },
+          "400": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getEntry",
+        "summary": "Inspect an entry",
+        "responses": {
+          "200": {
+            "description": "The entry.",
+            "headers": {"ETag": {"$ref": "#/components/headers/ETag"}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Entry"}}}
+          },
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "put": {
+        "operationId": "updateEntry",
+        "summary": "Replace the job of an entry",
+        "parameters": [
+          {"name": "If-Match", "in": "header", "description": "The revision the entry is expected to be at, as served in its ETag.", "schema": {"type": "string"}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/JobConfig"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The updated entry.",
+            "headers": {"ETag": {"$ref": "#/components/headers/ETag"}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Entry"}}}
+          },
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "412": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "removeEntry",
+        "summary": "Remove an entry",
+        "responses": {
+          "204": {"description": "The entry was removed."},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}/pause": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "post": {
+        "operationId": "pauseEntry",
+        "summary": "Pause an entry",
+        "responses": {
+          "202": {"description": "The entry is paused."},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}/resume": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "post": {
+        "operationId": "resumeEntry",
+        "summary": "Resume a paused entry",
+        "responses": {
+          "202": {"description": "The entry is resumed."},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}/enable": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "post": {
+        "operationId": "enableEntry",
+        "summary": "Enable an entry disabled for failing too often",
+        "responses": {
+          "202": {"description": "The entry is enabled."},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}/run": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "post": {
+        "operationId": "runEntry",
+        "summary": "Run an entry now",
+        "responses": {
+          "202": {"description": "The run was started."},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/entries/{id}/history": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "entryHistory",
+        "summary": "Latest runs of an entry",
+        "responses": {
+          "200": {
+            "description": "The latest runs, oldest first.",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/RunRecord"}}}}
+          },
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/events": {
+      "get": {
+        "operationId": "streamEvents",
+        "summary": "Stream scheduler events",
+        "parameters": [{"name": "entry", "in": "query", "schema": {"type": "string"}, "description": "Only stream the events of this entry."}],
+        "responses": {
+          "200": {
+            "description": "Server-Sent Events whose data is an Event in JSON.",
+            "content": {"text/event-stream": {"schema": {"$ref": "#/components/schemas/Event"}}}
+          }
+        }
+      }
+    },
+    "/stats": {
+      "get": {
+        "operationId": "stats",
+        "summary": "Run statistics of every entry",
+        "responses": {
+          "200": {
+            "description": "The statistics by entry ID.",
+            "content": {"application/json": {"schema": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/EntryStats"}}}}
+          }
+        }
+      }
+    },
+    "/scheduler": {
+      "get": {
+        "operationId": "schedulerStats",
+        "summary": "Statistics of the scheduler as a whole",
+        "responses": {
+          "200": {
+            "description": "The scheduler statistics.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SchedulerStats"}}}
+          }
+        }
+      }
+    },
+    "/quarantine": {
+      "get": {
+        "operationId": "quarantine",
+        "summary": "Entries disabled for failing too often or panicking",
+        "responses": {
+          "200": {
+            "description": "The quarantine records, oldest first.",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/QuarantineRecord"}}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Error": {
+        "description": "The request failed.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      }
+    },
+    "headers": {
+      "ETag": {"description": "The revision of the entry, quoted, for the If-Match header of updates.", "schema": {"type": "string"}}
+    },
+    "schemas": {
+      "Entry": {
+        "type": "object",
+        "required": ["id", "paused", "status", "prev", "next", "stats"],
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "spec": {"type": "string"},
+          "type": {"type": "string"},
+          "params": {"type": "object", "additionalProperties": {"type": "string"}},
+          "tags": {"type": "array", "items": {"type": "string"}},
+          "paused": {"type": "boolean"},
+          "status": {"type": "string", "enum": ["scheduled", "paused", "running", "disabled"]},
+          "breaker": {"type": "string", "enum": ["closed", "open", "half_open"]},
+          "prev": {"type": "string", "format": "date-time"},
+          "next": {"type": "string", "format": "date-time"},
+          "stats": {"$ref": "#/components/schemas/EntryStats"},
+          "revision": {"type": "integer", "description": "The revision the entry was added or last replaced at."}
+        }
+      },
+      "JobConfig": {
+        "type": "object",
+        "required": ["name", "spec", "type"],
+        "properties": {
+          "name": {"type": "string"},
+          "spec": {"type": "string"},
+          "type": {"type": "string"},
+          "params": {"type": "object", "additionalProperties": {"type": "string"}},
+          "timeout": {"type": "string", "description": "A Go duration, e.g. 30s."},
+          "retries": {"type": "integer"}
+        }
+      },
+      "EntryStats": {
+        "type": "object",
+        "properties": {
+          "runs": {"type": "integer", "format": "int64"},
+          "failures": {"type": "integer", "format": "int64"},
+          "last_run": {"type": "string", "format": "date-time"},
+          "last_duration": {"type": "integer", "format": "int64", "description": "Nanoseconds."},
+          "last_error": {"type": "string"},
+          "cpu_time": {"type": "integer", "format": "int64", "description": "Nanoseconds used by all the runs."},
+          "last_usage": {"$ref": "#/components/schemas/Usage"},
+          "sla_met": {"type": "integer", "format": "int64"},
+          "sla_missed": {"type": "integer", "format": "int64"},
+          "anomalies": {"type": "integer", "format": "int64"}
+        }
+      },
+      "Usage": {
+        "type": "object",
+        "properties": {
+          "cpu_time": {"type": "integer", "format": "int64", "description": "Nanoseconds."},
+          "memory": {"type": "integer", "format": "int64", "description": "Peak memory in bytes."},
+          "precise": {"type": "boolean", "description": "Whether the usage was measured for the run alone."}
+        }
+      },
+      "SchedulerStats": {
+        "type": "object",
+        "properties": {
+          "started": {"type": "string", "format": "date-time"},
+          "uptime": {"type": "integer", "format": "int64", "description": "Nanoseconds."},
+          "wakeups": {"type": "integer", "format": "int64"},
+          "dispatches": {"type": "integer", "format": "int64"},
+          "entries": {"type": "integer"},
+          "running": {"type": "integer"},
+          "waiting": {"type": "integer", "description": "Runs waiting for a concurrency slot."},
+          "queued": {"type": "integer", "description": "Runs waiting in the dispatch queue."},
+          "results": {
+            "type": "object",
+            "properties": {
+              "queued": {"type": "integer"},
+              "spilled": {"type": "integer"},
+              "dropped": {"type": "integer", "format": "int64"}
+            }
+          },
+          "last_wakeup": {"type": "string", "format": "date-time"},
+          "last_lateness": {"type": "integer", "format": "int64", "description": "Nanoseconds."}
+        }
+      },
+      "QuarantineRecord": {
+        "type": "object",
+        "required": ["id", "time", "reason"],
+        "properties": {
+          "id": {"type": "string"},
+          "time": {"type": "string", "format": "date-time"},
+          "reason": {"type": "string"},
+          "error": {"type": "string"},
+          "stack": {"type": "string"}
+        }
+      },
+      "RunRecord": {
+        "type": "object",
+        "properties": {
+          "start": {"type": "string", "format": "date-time"},
+          "end": {"type": "string", "format": "date-time"},
+          "msg": {"type": "string"},
+          "error": {"type": "string"},
+          "wait": {"type": "integer", "format": "int64", "description": "Nanoseconds."}
+        }
+      },
+      "Event": {
+        "type": "object",
+        "required": ["type", "time"],
+        "properties": {
+          "type": {"type": "string", "enum": ["scheduler_started", "scheduler_stopped", "entry_added", "entry_removed", "entry_paused", "entry_resumed", "entry_completed", "job_started", "job_finished", "job_dry_run", "clock_jumped", "job_skipped", "breaker_opened", "breaker_closed", "entry_disabled", "entry_enabled", "sla_missed", "duration_anomaly", "canary_promoted", "canary_rolled_back", "entry_replaced", "job_preempted", "handler_panicked"]},
+          "entry_id": {"type": "string"},
+          "time": {"type": "string", "format": "date-time"},
+          "msg": {"type": "string"},
+          "error": {"type": "string"}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "error": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
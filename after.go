@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AfterSchedule runs an entry after the runs of another job rather than at
+// times of its own, to chain follow-up work off the job. It is the schedule
+// of the "@after <job id> [delay]" spec, e.g. "@after extract 5m".
+//
+// Until the job runs, the next run of the entry is far in the future. The
+// runs of the job are only noticed while the entry exists. Each of them
+// triggers a run of the entry, but the triggers due by the time the entry
+// runs, e.g. when the job ran again before the delay elapsed, are coalesced
+// into that run.
+type AfterSchedule struct {
+	// JobID is the id of the job whose runs trigger the entry.
+	JobID string
+	// Delay is how long after the end of the run of the job the entry runs.
+	Delay time.Duration
+	// Always triggers the entry after the failed runs of the job too; only
+	// the successful ones do by default.
+	Always bool
+}
+
+// After returns an AfterSchedule running an entry delay after each
+// successful run of the job with the given id.
+func After(id string, delay time.Duration) AfterSchedule {
+	return AfterSchedule{JobID: id, Delay: delay}
+}
+
+// untriggered is the next run of an entry with an AfterSchedule waiting for
+// the job to run.
+var untriggered = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Next returns a time far in the future: the runs of the entry are decided
+// by the runs of the job.
+func (s AfterSchedule) Next(t time.Time) time.Time {
+	return untriggered.In(t.Location())
+}
+
+// String returns the spec of the schedule, ignoring Always.
+func (s AfterSchedule) String() string {
+	if s.Delay == 0 {
+		return "@after " + s.JobID
+	}
+	return "@after " + s.JobID + " " + s.Delay.String()
+}
+
+// parseAfter parses the arguments of an "@after" descriptor.
+func parseAfter(args string) (Schedule, error) {
+	fields := strings.Fields(args)
+	switch len(fields) {
+	case 1:
+		return After(fields[0], 0), nil
+	case 2:
+		delay, err := time.ParseDuration(fields[1])
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("Failed to parse delay %s: %v", fields[1], err)
+		}
+		return After(fields[0], delay), nil
+	}
+	return nil, fmt.Errorf("Expected a job id and an optional delay: @after %s", args)
+}
+
+// nextTrigger returns the first pending run of e after now, dropping the
+// earlier ones.
+func (e *Entry) nextTrigger(now time.Time) time.Time {
+	for len(e.triggers) > 0 && !e.triggers[0].After(now) {
+		e.triggers = e.triggers[1:]
+	}
+	if len(e.triggers) == 0 {
+		return untriggered.In(now.Location())
+	}
+	return e.triggers[0]
+}
+
+// noteChained records that the entries may have to be triggered by the runs
+// of jobs if e has an AfterSchedule.
+func (c *Cron) noteChained(e *Entry) {
+	if _, ok := unlocated(e.Schedule).(AfterSchedule); ok {
+		atomic.StoreInt32(&c.chained, 1)
+	}
+}
+
+// chain triggers the entries with an AfterSchedule on the job with the given
+// id, if there may be any: right away for a run made in the run loop, so
+// that the entries are due when the loop waits again, or through do once
+// the loop is free otherwise.
+func (c *Cron) chain(id string, err error, end time.Time, inLoop bool) {
+	if atomic.LoadInt32(&c.chained) == 0 {
+		return
+	}
+	if inLoop {
+		c.triggerAfter(id, err, end)
+		return
+	}
+	go c.do(func() { c.triggerAfter(id, err, end) })
+}
+
+// triggerAfter schedules the runs of the entries with an AfterSchedule on
+// the job with the given id, which ended a run at end. It must be called
+// through do.
+func (c *Cron) triggerAfter(id string, err error, end time.Time) {
+	for _, e := range c.entries {
+		s, ok := unlocated(e.Schedule).(AfterSchedule)
+		if !ok || s.JobID != id || err != nil && !s.Always {
+			continue
+		}
+		at := end.Add(s.Delay)
+		i := sort.Search(len(e.triggers), func(i int) bool { return e.triggers[i].After(at) })
+		e.triggers = append(e.triggers, time.Time{})
+		copy(e.triggers[i+1:], e.triggers[i:])
+		e.triggers[i] = at
+		if c.running && at.Before(e.Next) {
+			e.Next = at
+			c.requeue(e)
+		}
+	}
+}
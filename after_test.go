@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type chainJob struct {
+	id  string
+	err error
+}
+
+func (j *chainJob) ID() string           { return j.id }
+func (j *chainJob) Run() (string, error) { return "", j.err }
+
+func TestAfterSchedule(t *testing.T) {
+	c := New()
+	results := make(chan *JobResult, 10)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	extract := &chainJob{id: "extract"}
+	c.AddJob("@yearly", extract)
+	c.AddJob("@after extract 50ms", &chainJob{id: "load"})
+	c.Schedule(AfterSchedule{JobID: "extract", Always: true}, &chainJob{id: "alert"})
+	c.Start()
+	defer c.Stop()
+
+	if e, _ := c.Entry("load"); !e.Next.Equal(untriggered) {
+		t.Errorf("expected load to wait for extract, got %v", e.Next)
+	}
+	receive := func() *JobResult {
+		select {
+		case r := <-results:
+			return r
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected a run")
+			return nil
+		}
+	}
+
+	c.RunNow("extract")
+	// The results of extract and alert may be handled in any order.
+	ran := map[string]time.Time{}
+	for i := 0; i < 3; i++ {
+		ran[receive().JobId] = time.Now()
+	}
+	if ran["extract"].IsZero() || ran["load"].IsZero() || ran["alert"].IsZero() || !ran["alert"].Before(ran["load"]) {
+		t.Errorf("expected alert then load to run, got %v", ran)
+	}
+	if e, _ := c.Entry("load"); !e.Next.Equal(untriggered) {
+		t.Errorf("expected load to wait again, got %v", e.Next)
+	}
+
+	// Failed runs only trigger the entries set to always run.
+	extract.err = errors.New("failed")
+	c.RunNow("extract")
+	if ids := receive().JobId + " " + receive().JobId; ids != "extract alert" && ids != "alert extract" {
+		t.Errorf("expected extract and alert to run, got %s", ids)
+	}
+	select {
+	case r := <-results:
+		t.Errorf("expected load not to run, got %s", r.JobId)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	if s := c.Snapshot(); len(s.Entries) != 3 {
+		t.Errorf("expected 3 entries, got %+v", s.Entries)
+	}
+	if e, _ := c.Entry("load"); entrySpec(e) != "@after extract 50ms" {
+		t.Errorf("unexpected spec %q", entrySpec(e))
+	}
+}
+
+func TestAfterScheduleFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	var ran []string
+	c.AddResultHandler(func(r *JobResult) { ran = append(ran, r.JobId) })
+	c.AddJob("@hourly", &chainJob{id: "extract"})
+	c.AddJob("@after extract", &chainJob{id: "load"})
+	c.AddJob("@after extract 10m", &chainJob{id: "report"})
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Hour)
+	if strings.Join(ran, " ") != "extract load" {
+		t.Fatalf("expected extract then load to run, got %v", ran)
+	}
+	clock.Advance(10 * time.Minute)
+	if strings.Join(ran, " ") != "extract load report" {
+		t.Errorf("expected report to run 10m after extract, got %v", ran)
+	}
+}
+
+func TestParseAfter(t *testing.T) {
+	s, err := Parse("@after extract 1m")
+	if err != nil || s != After("extract", time.Minute) {
+		t.Errorf("unexpected schedule %+v, %v", s, err)
+	}
+	if s.(AfterSchedule).String() != "@after extract 1m0s" {
+		t.Errorf("unexpected spec %s", s)
+	}
+	for _, spec := range []string{"@after", "@after ", "@after a b c", "@after a -1s", "@after a soon"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package cron
+
+// WithAlignedIntervals aligns the @every schedules of the entries, and the
+// other ConstantDelaySchedules, on the wall clock, so that "@every 15m" runs
+// at :00, :15, :30 and :45 and "@every 1h" at the top of the hour, whenever
+// the Cron was started. See AlignedDelaySchedule.
+func WithAlignedIntervals() Option {
+	return func(c *Cron) {
+		c.alignEvery = true
+	}
+}
+
+// aligned returns the schedule s aligned on the wall clock if it is a
+// ConstantDelaySchedule.
+func aligned(s Schedule) Schedule {
+	switch s := s.(type) {
+	case ConstantDelaySchedule:
+		return AlignedDelaySchedule(s)
+	case locatedSchedule:
+		s.Schedule = aligned(s.Schedule)
+		return s
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveryAligned(t *testing.T) {
+	tests := []struct {
+		time     string
+		delay    time.Duration
+		expected string
+	}{
+		{"Mon Jul 9 14:07:30 2012", 15 * time.Minute, "Mon Jul 9 14:15 2012"},
+		{"Mon Jul 9 14:15 2012", 15 * time.Minute, "Mon Jul 9 14:30 2012"},
+		{"Mon Jul 9 14:07:30 2012", time.Hour, "Mon Jul 9 15:00 2012"},
+		{"Mon Jul 9 23:50 2012", 15 * time.Minute, "Tue Jul 10 00:00 2012"},
+		{"Mon Jul 9 14:07:30 2012", 6 * time.Hour, "Mon Jul 9 18:00 2012"},
+		// 7 minutes do not divide a day, and are aligned on the epoch.
+		{"Mon Jul 9 14:07:30 2012", 7 * time.Minute, "Mon Jul 9 14:14 2012"},
+	}
+	for _, test := range tests {
+		actual := EveryAligned(test.delay).Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, %v: (expected) %v != %v (actual)", test.time, test.delay, expected, actual)
+		}
+	}
+
+	// Aligned on the midnight of the time zone.
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Date(2012, 7, 9, 14, 7, 30, 0, kolkata)
+	if next := EveryAligned(time.Hour).Next(now); !next.Equal(time.Date(2012, 7, 9, 15, 0, 0, 0, kolkata)) {
+		t.Errorf("expected the top of the hour in Kolkata, got %v", next)
+	}
+}
+
+func TestWithAlignedIntervals(t *testing.T) {
+	clock := NewFakeClock(time.Date(2012, 7, 9, 14, 7, 30, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC), WithAlignedIntervals())
+	c.AddFunc("@every 15m", func() (string, error) { return "", nil })
+	c.Schedule(EveryAligned(time.Hour), &testDescribedJob{"hourly", "x"})
+	c.Start()
+	defer c.Stop()
+
+	for _, e := range c.Entries() {
+		expected := time.Date(2012, 7, 9, 14, 15, 0, 0, time.UTC)
+		if e.Job.ID() == "hourly" {
+			expected = time.Date(2012, 7, 9, 15, 0, 0, 0, time.UTC)
+		}
+		if !e.Next.Equal(expected) {
+			t.Errorf("%s: expected the next run at %v, got %v", e.Job.ID(), expected, e.Next)
+		}
+	}
+	for _, state := range c.Snapshot().Entries {
+		if state.ID == "hourly" && state.Spec != "@every 1h0m0s" {
+			t.Errorf("expected the interval saved as a spec, got %+v", state)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// anomalyMinRuns is the number of previous runs needed for a baseline.
+const anomalyMinRuns = 5
+
+// WithAnomalyDetection flags the runs whose duration deviates from the
+// median duration of the previous runs of their job by more than factor,
+// e.g. 3 for the runs taking more than 3 times the median or less than a
+// third of it. The baseline is the runs kept in the history of the job, and
+// is only used once there are enough of them. Each anomaly is emitted as an
+// EventDurationAnomaly and counted in the stats of the entry.
+func WithAnomalyDetection(factor float64) Option {
+	return func(c *Cron) {
+		if factor > 1 {
+			c.anomalyFactor = factor
+		}
+	}
+}
+
+// checkDuration reports whether a run of the job with the given id lasting
+// d is an anomaly, to be called before the run is recorded.
+func (c *Cron) checkDuration(id string, d time.Duration) {
+	if c.anomalyFactor == 0 {
+		return
+	}
+	median, ok := c.history.medianDuration(id)
+	if !ok || median <= 0 {
+		return
+	}
+	ratio := float64(d) / float64(median)
+	if ratio <= c.anomalyFactor && ratio >= 1/c.anomalyFactor {
+		return
+	}
+	c.history.mu.Lock()
+	if s, ok := c.history.stats[id]; ok {
+		s.Anomalies++
+	}
+	c.history.mu.Unlock()
+	c.emit(Event{
+		Type:    EventDurationAnomaly,
+		EntryID: id,
+		Msg:     fmt.Sprintf("Run took %s, %.1fx the median of %s", d, ratio, median),
+	})
+}
+
+// medianDuration returns the median duration of the recorded runs of the job
+// with the given id, if there are enough of them.
+func (h *runHistory) medianDuration(id string) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs, ok := h.runs[id]
+	if !ok || len(runs.buf) < anomalyMinRuns {
+		return 0, false
+	}
+	ds := make([]time.Duration, len(runs.buf))
+	for i, r := range runs.buf {
+		ds[i] = r.Duration()
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return ds[len(ds)/2], true
+}
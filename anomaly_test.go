@@ -0,0 +1,41 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAnomalyDetection(t *testing.T) {
+	c := New(WithAnomalyDetection(3))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	id, _ := c.AddFunc("@yearly", func() (string, error) { return "", nil })
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(d time.Duration) {
+		c.history.record(id, start, start.Add(d), 0, "", nil, Usage{})
+	}
+
+	for _, d := range []time.Duration{10, 12, 9, 11, 10} {
+		c.checkDuration(id, d*time.Second)
+		run(d * time.Second)
+	}
+	// Within a factor of 3 of the median of 10s, then outside.
+	for _, d := range []time.Duration{25, 4, 31, 3} {
+		c.checkDuration(id, d*time.Second)
+	}
+
+	var msgs []string
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == EventDurationAnomaly {
+			msgs = append(msgs, ev.Msg)
+		}
+	}
+	want := []string{"Run took 31s, 3.1x the median of 10s", "Run took 3s, 0.3x the median of 10s"}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected anomalies %q, got %q", want, msgs)
+	}
+	if s := c.Stats(id); s.Anomalies != 2 {
+		t.Errorf("expected 2 anomalies, got %d", s.Anomalies)
+	}
+}
@@ -0,0 +1,112 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BackfillOption configures a Backfill.
+type BackfillOption func(*backfill)
+
+type backfill struct {
+	concurrency int
+	reverse     bool
+}
+
+// BackfillConcurrency runs up to n runs of the backfill at the same time,
+// instead of one after the other.
+func BackfillConcurrency(n int) BackfillOption {
+	return func(b *backfill) {
+		if n > 0 {
+			b.concurrency = n
+		}
+	}
+}
+
+// BackfillReverse starts the runs of the backfill from the latest
+// occurrence, instead of the earliest.
+func BackfillReverse() BackfillOption {
+	return func(b *backfill) {
+		b.reverse = true
+	}
+}
+
+// Backfill runs the job of the entry with the given id once for every
+// occurrence of its schedule from from, included, to to, excluded, e.g. to
+// make up for the runs of a week the job was broken. ContextJobs get the
+// occurrence from ScheduledTime, and parameter templates as ScheduledTime.
+//
+// The runs are recorded like the scheduled ones, but do not count in the
+// circuit breaker or the failure streak of the entry. Backfill returns once
+// they are all done, with an error if any failed.
+func (c *Cron) Backfill(id string, from, to time.Time, opts ...BackfillOption) error {
+	if c.observer {
+		return ErrObserver
+	}
+	b := backfill{concurrency: 1}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	var (
+		job   Job
+		s     slots
+		datas []*TemplateData
+		err   error
+	)
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		job, s = e.Job, e.slots(c.ctx)
+		start := from.In(c.Location()).Add(-time.Nanosecond)
+		for t := e.Schedule.Next(start); !t.IsZero() && t.Before(to); t = e.Schedule.Next(t) {
+			datas = append(datas, templateData(e, t))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if b.reverse {
+		for i, j := 0, len(datas)-1; i < j; i, j = i+1, j-1 {
+			datas[i], datas[j] = datas[j], datas[i]
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failed   int
+		firstErr error
+		sem      = make(chan struct{}, b.concurrency)
+	)
+	for _, d := range datas {
+		if c.dryRun {
+			c.skipDryRun(job, d.ScheduledTime)
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(d *TemplateData) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := c.runWithRecovery(job, nil, s, d); err != nil {
+				mu.Lock()
+				failed++
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %s", d.ScheduledTime.Format(time.RFC3339), err)
+				}
+				mu.Unlock()
+			}
+		}(d)
+	}
+	wg.Wait()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backfill runs of %s failed, first %s", failed, len(datas), id, firstErr)
+	}
+	return nil
+}
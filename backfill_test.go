@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type scheduledJob struct {
+	mu    sync.Mutex
+	times []string
+}
+
+func (j *scheduledJob) ID() string           { return "daily" }
+func (j *scheduledJob) Run() (string, error) { return "", nil }
+
+func (j *scheduledJob) RunContext(ctx context.Context) (string, error) {
+	t, ok := ScheduledTime(ctx)
+	if !ok {
+		return "", errors.New("no scheduled time")
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.times = append(j.times, t.Format("01-02"))
+	if t.Day() == 2 {
+		return "", errors.New("no data")
+	}
+	return "", nil
+}
+
+func TestBackfill(t *testing.T) {
+	c := New(WithLocation(time.UTC))
+	j := &scheduledJob{}
+	c.AddJob("0 0 0 * * *", j)
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	err := c.Backfill("daily", from, to)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 backfill runs of daily failed, first 2020-01-02T00:00:00Z: no data") {
+		t.Errorf("expected a failed run, got %v", err)
+	}
+	if got := strings.Join(j.times, " "); got != "01-01 01-02 01-03" {
+		t.Errorf("expected the runs in order, got %s", got)
+	}
+	if runs := c.History("daily"); len(runs) != 3 {
+		t.Errorf("expected the runs in the history, got %d", len(runs))
+	}
+
+	j.times = nil
+	if err := c.Backfill("daily", from.Add(time.Hour), to, BackfillReverse()); err == nil {
+		t.Error("expected a failed run")
+	}
+	if got := strings.Join(j.times, " "); got != "01-03 01-02" {
+		t.Errorf("expected the runs in reverse order, got %s", got)
+	}
+
+	j.times = nil
+	c.Backfill("daily", from, to.AddDate(0, 0, 7), BackfillConcurrency(4))
+	if len(j.times) != 10 {
+		t.Errorf("expected 10 runs, got %v", j.times)
+	}
+
+	if err := c.Backfill("missing", from, to); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package cron
+
+import "sync"
+
+// WithBatchDispatch dispatches the runs through a queue holding up to depth
+// runs, served by parallelism workers, rather than starting a goroutine per
+// run. When many entries are due at the same time, the runs are started as
+// workers become free instead of all at once, and the time each run waited
+// is reported in JobResult.Wait and RunRecord.Wait. A run due while the queue
+// is full is skipped, with an EventJobSkipped event.
+func WithBatchDispatch(parallelism, depth int) Option {
+	return func(c *Cron) {
+		if parallelism > 0 {
+			c.batch = &batchDispatcher{parallelism: parallelism, depth: depth}
+		}
+	}
+}
+
+// batchDispatcher runs tasks from a bounded queue on a fixed set of workers.
+// While it is stopped, each task gets a goroutine of its own.
+type batchDispatcher struct {
+	parallelism int
+	depth       int
+
+	mu    sync.RWMutex
+	queue chan func() // nil while stopped
+}
+
+// start starts the workers.
+func (b *batchDispatcher) start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.queue != nil {
+		return
+	}
+	b.queue = make(chan func(), b.depth)
+	for i := 0; i < b.parallelism; i++ {
+		go work(b.queue)
+	}
+}
+
+// stop lets the workers exit once they are done with the queued tasks.
+func (b *batchDispatcher) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.queue != nil {
+		close(b.queue)
+		b.queue = nil
+	}
+}
+
+// submit queues f, and returns false if the queue is full.
+func (b *batchDispatcher) submit(f func()) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.queue == nil {
+		go f()
+		return true
+	}
+	select {
+	case b.queue <- f:
+		return true
+	default:
+		return false
+	}
+}
+
+// pending returns the number of runs waiting in the queue.
+func (b *batchDispatcher) pending() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.queue)
+}
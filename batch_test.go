@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+type batchJob struct {
+	id      string
+	started chan string
+	release chan struct{}
+}
+
+func (j *batchJob) ID() string { return j.id }
+
+func (j *batchJob) Run() (string, error) {
+	j.started <- j.id
+	<-j.release
+	return "", nil
+}
+
+func TestBatchDispatch(t *testing.T) {
+	c := New(WithBatchDispatch(1, 1))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	results := make(chan *JobResult, 10)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	started := make(chan string, 10)
+	release := make(chan struct{})
+	for _, id := range []string{"a", "b", "c"} {
+		c.AddJob("@yearly", &batchJob{id, started, release})
+	}
+	c.Start()
+	defer c.Stop()
+
+	c.RunNow("a")
+	<-started
+	c.RunNow("b")
+	c.RunNow("c")
+	for e := range events {
+		if e.Type == EventJobSkipped {
+			if e.EntryID != "c" || e.Msg != "Dispatch queue full" {
+				t.Errorf("expected c skipped, got %+v", e)
+			}
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	release <- struct{}{}
+	if id := <-started; id != "b" {
+		t.Errorf("expected b to run next, got %s", id)
+	}
+	release <- struct{}{}
+	// The result handlers of a and b may run in any order.
+	byID := make(map[string]*JobResult)
+	for i := 0; i < 2; i++ {
+		r := <-results
+		byID[r.JobId] = r
+	}
+	if byID["a"] == nil || byID["b"] == nil {
+		t.Fatalf("expected the results of a and b, got %v", byID)
+	}
+	if w := byID["b"].Wait; w < 10*time.Millisecond {
+		t.Errorf("expected b to wait in the queue, got %s", w)
+	}
+	if runs := c.History("b"); len(runs) != 1 || runs[0].Wait < 10*time.Millisecond {
+		t.Errorf("expected the wait recorded, got %+v", runs)
+	}
+}
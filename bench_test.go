@@ -0,0 +1,168 @@
+package cron
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// benchEntries returns n entries with due times spread over n seconds.
+func benchEntries(n int, now time.Time) map[string]*Entry {
+	entries := make(map[string]*Entry, n)
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		e := &Entry{
+			Schedule: ConstantDelaySchedule{time.Duration(n) * time.Second},
+			Job:      NewShellCommandJob(id, "true"),
+		}
+		e.Next = now.Add(time.Duration(i+1) * time.Second)
+		entries[id] = e
+	}
+	return entries
+}
+
+// BenchmarkWakeupSort measures a wakeup of the former run loop, which
+// rebuilt and sorted the entry list every time.
+func BenchmarkWakeupSort(b *testing.B) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := benchEntries(10000, now)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sorted := make([]*Entry, 0)
+		for _, e := range entries {
+			sorted = append(sorted, e)
+		}
+		sort.Sort(byTime(sorted))
+		e := sorted[0]
+		e.Next = e.Schedule.Next(e.Next)
+	}
+}
+
+// BenchmarkWakeupHeap measures a wakeup of the run loop, which reschedules
+// the first entry of its queue.
+func BenchmarkWakeupHeap(b *testing.B) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var queue entryHeap
+	for _, e := range benchEntries(10000, now) {
+		heap.Push(&queue, e)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e := queue[0]
+		e.Next = e.Schedule.Next(e.Next)
+		heap.Fix(&queue, 0)
+	}
+}
+
+// BenchmarkDispatch measures the run loop dispatching one entry per second,
+// the jobs running synchronously on a FakeClock. Each entry runs every
+// minute, as in high frequency schedules.
+func BenchmarkDispatch(b *testing.B) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	noop := func() (string, error) { return "", nil }
+	for i := 0; i < 60; i++ {
+		c.AddFunc(fmt.Sprintf("%d * * * * *", i), noop)
+	}
+	c.Start()
+	defer c.Stop()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clock.Advance(time.Second)
+	}
+}
+
+// BenchmarkAnnounce measures a wakeup of the run loop of a Cron holding
+// 10000 entries while the upcoming runs are subscribed to, one entry
+// running every second.
+func BenchmarkAnnounce(b *testing.B) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	for i := 0; i < 10000; i++ {
+		c.AddJob("@yearly", NewShellCommandJob(strconv.Itoa(i), "true"))
+	}
+	c.AddFunc("* * * * * *", func() (string, error) { return "", nil })
+	c.Start()
+	defer c.Stop()
+	firings, cancel := c.SubscribeUpcoming(16)
+	defer cancel()
+	go func() {
+		for range firings {
+		}
+	}()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clock.Advance(time.Second)
+	}
+}
+
+// BenchmarkAddRemove measures adding and removing an entry of a running
+// Cron holding 10000 entries.
+func BenchmarkAddRemove(b *testing.B) {
+	c := New()
+	for i := 0; i < 10000; i++ {
+		c.AddJob("@hourly", NewShellCommandJob(strconv.Itoa(i), "true"))
+	}
+	c.Start()
+	defer c.Stop()
+	job := NewShellCommandJob("added", "true")
+	schedule := Every(time.Hour)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Schedule(schedule, job)
+		c.RemoveJob("added")
+	}
+}
+
+// BenchmarkSpawn compares starting a goroutine per run with handing it to
+// the worker pool.
+func BenchmarkSpawn(b *testing.B) {
+	run := func(b *testing.B, p *workerPool) {
+		var wg sync.WaitGroup
+		f := func() { wg.Done() }
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wg.Add(1)
+			if !p.submit(f) {
+				f()
+			}
+		}
+		wg.Wait()
+	}
+	b.Run("goroutine", func(b *testing.B) { run(b, nil) })
+	b.Run("pool", func(b *testing.B) {
+		p := newWorkerPool(8, 1024)
+		p.start()
+		defer p.stop()
+		run(b, p)
+	})
+}
+
+// BenchmarkParse compares parsing a spec with finding it in a SpecCache.
+func BenchmarkParse(b *testing.B) {
+	const spec = "0 */5 9-17 * * MON-FRI"
+	b.Run("parser", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			defaultParser.Parse(spec)
+		}
+	})
+	b.Run("cache", func(b *testing.B) {
+		cache := NewSpecCache(defaultParser, DefaultSpecCacheSize)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cache.Parse(spec)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package cron
+
+import "time"
+
+// BreakerState is the state of the circuit breaker of an entry.
+type BreakerState string
+
+const (
+	// BreakerClosed breakers let the runs through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen breakers skip the runs until their cool-down is over.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen breakers let a single probe run through, which
+	// closes them if it succeeds and opens them again otherwise.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// WithBreaker gives the entry a circuit breaker, which opens after the given
+// number of consecutive failed runs. The runs due during the following
+// coolDown are skipped, emitting an EventJobSkipped, so a broken job does not
+// hammer what it depends on. A probe run is then let through; if it is
+// skipped, such as in dry-run or when the dispatch queue is full, the next
+// run due probes instead.
+func WithBreaker(failures int, coolDown time.Duration) EntryOption {
+	return func(e *Entry) {
+		if e.health == nil {
+			e.health = &entryHealth{}
+		}
+		e.health.breaker = &circuitBreaker{
+			failures: failures,
+			coolDown: coolDown,
+			state:    BreakerClosed,
+		}
+	}
+}
+
+// circuitBreaker is the breaker of an entry, guarded by the mutex of the
+// entryHealth holding it.
+type circuitBreaker struct {
+	failures int
+	coolDown time.Duration
+	state    BreakerState
+	opened   time.Time
+	probing  bool
+}
+
+// allow reports whether the run due at t may start, turning the breaker half
+// open if its cool-down is over.
+func (b *circuitBreaker) allow(t time.Time) bool {
+	switch b.state {
+	case BreakerOpen:
+		if t.Before(b.opened.Add(b.coolDown)) {
+			return false
+		}
+		b.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+	default:
+		return true
+	}
+	b.probing = true
+	return true
+}
+
+// skip releases the probe the breaker let through, its run being skipped,
+// so that the next run due probes instead.
+func (b *circuitBreaker) skip() {
+	b.probing = false
+}
+
+// record updates the breaker with the outcome of a run finished at now,
+// the job having failed streak times in a row. It returns whether the
+// breaker opened or closed.
+func (b *circuitBreaker) record(err error, streak int, now time.Time) bool {
+	old := b.state
+	b.probing = false
+	switch {
+	case err == nil:
+		b.state = BreakerClosed
+	case b.state == BreakerHalfOpen:
+		b.state, b.opened = BreakerOpen, now
+	case b.state == BreakerClosed && streak >= b.failures:
+		b.state, b.opened = BreakerOpen, now
+	}
+	return b.state != old && b.state != BreakerHalfOpen
+}
+
+// skipRun reports the run of j due at t as skipped for the given reason.
+func (c *Cron) skipRun(j Job, t time.Time, reason string) {
+	c.entryLogf(j.ID(), "cron: skipping job %s due at %s: %s", j.ID(), t.Format(time.RFC3339), reason)
+	c.emit(Event{Type: EventJobSkipped, EntryID: j.ID(), Time: t, Msg: reason})
+}
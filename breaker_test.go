@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	runs, fail := 0, true
+	id, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+		runs++
+		if fail {
+			return "", errors.New("downstream unavailable")
+		}
+		return "", nil
+	}, WithBreaker(3, 5*time.Minute))
+	c.Start()
+	defer c.Stop()
+
+	expect := func(state BreakerState, wantRuns int) {
+		t.Helper()
+		e, _ := c.Entry(id)
+		if e.Breaker != state {
+			t.Errorf("expected the breaker %s, got %s", state, e.Breaker)
+		}
+		if runs != wantRuns {
+			t.Errorf("expected %d runs, got %d", wantRuns, runs)
+		}
+	}
+
+	// Opens after 3 failures, and skips the runs of the next 5 minutes.
+	clock.Advance(3 * time.Minute)
+	expect(BreakerOpen, 3)
+	clock.Advance(4 * time.Minute)
+	expect(BreakerOpen, 3)
+	// The probe run fails: open again.
+	clock.Advance(time.Minute)
+	expect(BreakerOpen, 4)
+	// The next probe succeeds: closed.
+	fail = false
+	clock.Advance(5 * time.Minute)
+	expect(BreakerClosed, 5)
+	clock.Advance(time.Minute)
+	expect(BreakerClosed, 6)
+
+	var skipped, opened, closed int
+	for len(events) > 0 {
+		switch e := <-events; e.Type {
+		case EventJobSkipped:
+			skipped++
+		case EventBreakerOpened:
+			opened++
+		case EventBreakerClosed:
+			closed++
+		}
+	}
+	if skipped != 8 || opened != 2 || closed != 1 {
+		t.Errorf("expected 8 skipped runs, 2 openings and 1 closing, got %d, %d and %d", skipped, opened, closed)
+	}
+}
+
+func TestBreakerProbe(t *testing.T) {
+	b := &circuitBreaker{failures: 1, coolDown: time.Minute, state: BreakerClosed}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.record(errors.New("failed"), 1, now)
+	if b.allow(now.Add(30 * time.Second)) {
+		t.Error("expected an open breaker to skip runs")
+	}
+	if !b.allow(now.Add(time.Minute)) {
+		t.Error("expected a probe run after the cool-down")
+	}
+	if b.allow(now.Add(61 * time.Second)) {
+		t.Error("expected a single probe run at a time")
+	}
+	if changed := b.record(nil, 0, now.Add(62*time.Second)); b.state != BreakerClosed || !changed {
+		t.Errorf("expected the probe to close the breaker, got %s", b.state)
+	}
+}
+
+func TestBreakerSkippedProbe(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	runs := 0
+	id, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+		runs++
+		return "", errors.New("downstream unavailable")
+	}, WithBreaker(1, time.Minute))
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Minute)
+	// The probe run due after the cool-down is skipped in dry-run: the next
+	// run probes instead.
+	c.dryRun = true
+	clock.Advance(time.Minute)
+	c.dryRun = false
+	clock.Advance(time.Minute)
+	if e, _ := c.Entry(id); runs != 2 || e.Breaker != BreakerOpen {
+		t.Errorf("expected the next run to probe, got %d runs and the breaker %s", runs, e.Breaker)
+	}
+}
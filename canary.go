@@ -0,0 +1,176 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// DefaultCanaryRuns is the number of runs of a canary before it is promoted
+// or rolled back, by default.
+const DefaultCanaryRuns = 10
+
+// CanaryConfig rolls out the change of a JobConfig progressively. Instead of
+// replacing the running job, the new definition runs as a canary next to the
+// old one, on the schedule of the old one. Once the canary ran Runs times,
+// it replaces the old definition if it failed proportionally no more than
+// the old one did in the meantime, and is dropped otherwise.
+type CanaryConfig struct {
+	// Fraction is the share of the firings running the canary instead of
+	// the old job, e.g. 0.1. Zero runs both on every firing, reporting the
+	// result of the old job: the canary is then a shadow whose outcomes are
+	// compared with those of the old job.
+	Fraction float64 `json:"fraction,omitempty"`
+	// Runs is the number of runs of the canary, DefaultCanaryRuns if zero.
+	Runs int `json:"runs,omitempty"`
+}
+
+// canaryJob runs the old and the new definitions of a configured job.
+type canaryJob struct {
+	old  Job
+	next *Entry
+	cfg  CanaryConfig
+	cron *Cron
+
+	mu          sync.Mutex
+	oldRuns     int
+	oldFailures int
+	newRuns     int
+	newFailures int
+	// mismatches counts the shadow runs whose outcome differed.
+	mismatches int
+	decided    bool
+}
+
+// startCanary makes the new entry of a job config the canary of the
+// current one, reporting whether the job exists. It must be called through
+// do.
+func (c *Cron) startCanary(cfg CanaryConfig, next *Entry) bool {
+	e, found := c.entries[next.Job.ID()]
+	if !found {
+		return false
+	}
+	old := e.Job
+	if cj, isCanary := old.(*canaryJob); isCanary {
+		old = cj.old
+	}
+	if cfg.Runs <= 0 {
+		cfg.Runs = DefaultCanaryRuns
+	}
+	e.Job = &canaryJob{old: old, next: next, cfg: cfg, cron: c}
+	return true
+}
+
+func (j *canaryJob) ID() string { return j.old.ID() }
+
+func (j *canaryJob) JobType() string {
+	typ, _ := describe(j.old)
+	return typ
+}
+
+func (j *canaryJob) Params() map[string]string {
+	_, params := describe(j.old)
+	return params
+}
+
+func (j *canaryJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+func (j *canaryJob) RunContext(ctx context.Context) (msg string, err error) {
+	if j.cfg.Fraction > 0 {
+		if rand.Float64() < j.cfg.Fraction {
+			msg, err = runJob(ctx, j.next.Job)
+			j.record(false, nil, err)
+		} else {
+			msg, err = runJob(ctx, j.old)
+			j.record(true, err, nil)
+		}
+		return msg, err
+	}
+
+	// Shadow: both run, the old job's result is reported.
+	done := make(chan error, 1)
+	go func() {
+		_, err := runJob(ctx, j.next.Job)
+		done <- err
+	}()
+	msg, err = runJob(ctx, j.old)
+	j.record(true, err, <-done)
+	return msg, err
+}
+
+// record counts the outcome of a run of the old job if old is true, and of
+// a run of the canary. It decides on the canary after its last run.
+func (j *canaryJob) record(old bool, oldErr, newErr error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	shadow := j.cfg.Fraction <= 0
+	if old {
+		j.oldRuns++
+		if oldErr != nil {
+			j.oldFailures++
+		}
+	}
+	if !old || shadow {
+		j.newRuns++
+		if newErr != nil {
+			j.newFailures++
+		}
+	}
+	if shadow && (oldErr == nil) != (newErr == nil) {
+		j.mismatches++
+	}
+	if j.decided || j.newRuns < j.cfg.Runs {
+		return
+	}
+	j.decided = true
+	// The canary fails no more often than the old job.
+	promote := j.newFailures*max1(j.oldRuns) <= j.oldFailures*j.newRuns
+	summary := fmt.Sprintf("Canary failed %d of %d runs, the old job %d of %d", j.newFailures, j.newRuns, j.oldFailures, j.oldRuns)
+	if shadow {
+		summary += fmt.Sprintf(", with %d different outcomes", j.mismatches)
+	}
+	// The entries can not be changed from a run, which may be in the run
+	// loop.
+	go j.cron.finishCanary(j, promote, summary)
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// finishCanary promotes the canary, replacing the entry of the old job, or
+// drops it.
+func (c *Cron) finishCanary(j *canaryJob, promote bool, summary string) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	id := j.ID()
+	current := false
+	c.do(func() {
+		if e, ok := c.entries[id]; ok && e.Job == j {
+			current = true
+			if !promote {
+				e.Job = j.old
+			}
+		}
+	})
+	if !current {
+		// Replaced or removed meanwhile.
+		return
+	}
+	if !promote {
+		c.entryErrorf(id, "cron: rolled back canary of %s: %s", id, summary)
+		c.emit(Event{Type: EventCanaryRolledBack, EntryID: id, Msg: summary})
+		return
+	}
+	if err := c.addEntry(j.next, true); err != nil {
+		c.entryErrorf(id, "cron: failed to promote canary of %s: %s", id, err)
+		return
+	}
+	c.emit(Event{Type: EventCanaryPromoted, EntryID: id, Msg: summary})
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type versionJob struct {
+	id, version string
+	fail        bool
+}
+
+func (j *versionJob) ID() string { return j.id }
+
+func (j *versionJob) Run() (string, error) {
+	if j.fail {
+		return "", errors.New(j.version + " failed")
+	}
+	return j.version, nil
+}
+
+func init() {
+	RegisterJobType("version", func(id string, params map[string]string) (Job, error) {
+		return &versionJob{id, params["version"], params["fail"] == "true"}, nil
+	})
+}
+
+func TestCanary(t *testing.T) {
+	c := New()
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	apply := func(version string, fail bool, canary *CanaryConfig) {
+		t.Helper()
+		params := map[string]string{"version": version}
+		if fail {
+			params["fail"] = "true"
+		}
+		err := c.ApplyConfig(&Config{Jobs: []JobConfig{{Name: "job", Spec: "@hourly", Type: "version", Params: params, Canary: canary}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(n int) (msgs []string) {
+		for i := 0; i < n; i++ {
+			c.runWithRecovery(c.entries["job"].Job, nil, slots{}, nil)
+			runs := c.History("job")
+			msgs = append(msgs, runs[len(runs)-1].Msg)
+		}
+		return msgs
+	}
+	wait := func(want EventType) Event {
+		t.Helper()
+		for {
+			select {
+			case ev := <-events:
+				if ev.Type == want {
+					return ev
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected a %s event", want)
+			}
+		}
+	}
+
+	apply("v1", false, nil)
+	apply("v2", false, &CanaryConfig{Runs: 3})
+	// The shadow canary runs along, the results are those of v1.
+	if msgs := strings.Join(run(3), " "); msgs != "v1 v1 v1" {
+		t.Errorf("expected the results of v1, got %s", msgs)
+	}
+	if ev := wait(EventCanaryPromoted); ev.Msg != "Canary failed 0 of 3 runs, the old job 0 of 3, with 0 different outcomes" {
+		t.Errorf("unexpected summary %q", ev.Msg)
+	}
+	if msgs := strings.Join(run(1), " "); msgs != "v2" {
+		t.Errorf("expected v2 to be promoted, got %s", msgs)
+	}
+	if len(c.History("job")) != 4 {
+		t.Error("expected the history to be kept")
+	}
+
+	apply("v3", true, &CanaryConfig{Fraction: 1, Runs: 2})
+	run(2)
+	if ev := wait(EventCanaryRolledBack); ev.Msg != "Canary failed 2 of 2 runs, the old job 0 of 0" {
+		t.Errorf("unexpected summary %q", ev.Msg)
+	}
+	if msgs := strings.Join(run(1), " "); msgs != "v2" {
+		t.Errorf("expected v2 to be restored, got %s", msgs)
+	}
+}
@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// cpuPeriod is the period of the CPU quota of a cgroup, in microseconds.
+const cpuPeriod = 100000
+
+// cgroup is a cgroup v2 limiting the resources of a command.
+type cgroup struct {
+	dir string
+}
+
+// newCgroup creates a cgroup in CgroupRoot allowing cpus CPUs and memory
+// bytes, zero meaning no limit.
+func newCgroup(cpus float64, memory int64) (*cgroup, error) {
+	dir, err := ioutil.TempDir(CgroupRoot, "cron-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create cgroup: %s", err)
+	}
+	g := &cgroup{dir: dir}
+	if cpus > 0 {
+		quota := int64(cpus * cpuPeriod)
+		if quota < 1000 {
+			quota = 1000
+		}
+		err = g.write("cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod))
+	}
+	if err == nil && memory > 0 {
+		err = g.write("memory.max", strconv.FormatInt(memory, 10))
+	}
+	if err != nil {
+		g.remove()
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *cgroup) write(file, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(g.dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("Failed to set cgroup %s: %s", file, err)
+	}
+	return nil
+}
+
+// hold makes cmd wait once started until release is called, so that it can
+// be moved into the cgroup before it runs anything and the limits apply to
+// every process it starts: cmd is run by /bin/sh, which waits for a pipe to
+// be closed before executing it. release must be called once cmd started,
+// or failed to.
+func (g *cgroup) hold(cmd *exec.Cmd) (release func(), err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create cgroup: %s", err)
+	}
+	fd := 3 + len(cmd.ExtraFiles)
+	cmd.ExtraFiles = append(cmd.ExtraFiles, r)
+	script := fmt.Sprintf(`read _ <&%d; exec %d<&-; exec "$@"`, fd, fd)
+	cmd.Args = append([]string{"/bin/sh", "-c", script, "sh"}, cmd.Args...)
+	cmd.Path = "/bin/sh"
+	return func() {
+		r.Close()
+		w.Close()
+	}, nil
+}
+
+// add moves the process with the given pid into the cgroup.
+func (g *cgroup) add(pid int) error {
+	return g.write("cgroup.procs", strconv.Itoa(pid))
+}
+
+// oomKilled reports whether a process of the cgroup was killed for going
+// over the memory limit.
+func (g *cgroup) oomKilled() bool {
+	data, err := ioutil.ReadFile(filepath.Join(g.dir, "memory.events"))
+	if err != nil {
+		return false
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		var n int
+		if _, err := fmt.Sscanf(s.Text(), "oom_kill %d", &n); err == nil {
+			return n > 0
+		}
+	}
+	return false
+}
+
+// remove kills the processes left in the cgroup and deletes it.
+func (g *cgroup) remove() {
+	// cgroup.kill exists since Linux 5.14; a process left behind on older
+	// kernels keeps the cgroup from being removed.
+	ioutil.WriteFile(filepath.Join(g.dir, "cgroup.kill"), []byte("1"), 0644)
+	os.Remove(g.dir)
+}
@@ -0,0 +1,59 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestShellCommandJobLimits(t *testing.T) {
+	// A plain directory stands for the cgroup hierarchy: the files the
+	// kernel would interpret are left for the test to read.
+	root, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	defer func(old string) { CgroupRoot = old }(CgroupRoot)
+	CgroupRoot = root
+
+	j := NewShellCommandJob("limited", "echo $$")
+	j.CPULimit = 0.5
+	j.MemoryLimit = 64 << 20
+	msg, err := j.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs, _ := filepath.Glob(filepath.Join(root, "cron-*"))
+	if len(dirs) != 1 {
+		t.Fatalf("expected a cgroup, got %v", dirs)
+	}
+	for file, want := range map[string]string{
+		"cpu.max":      "50000 100000",
+		"memory.max":   strconv.Itoa(64 << 20),
+		"cgroup.procs": strings.TrimSpace(msg),
+	} {
+		if got, _ := ioutil.ReadFile(filepath.Join(dirs[0], file)); string(got) != want {
+			t.Errorf("expected %s to be %q, got %q", file, want, got)
+		}
+	}
+
+	// The command only runs once it joined its cgroup.
+	os.RemoveAll(dirs[0])
+	j.Command = "cat " + root + "/cron-*/cgroup.procs; echo; echo $$"
+	msg, err = j.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Fields(msg); len(lines) != 2 || lines[0] != lines[1] {
+		t.Errorf("expected the command to run in its cgroup, got %q", msg)
+	}
+
+	CgroupRoot = filepath.Join(root, "missing")
+	if _, err := j.Run(); err == nil || !strings.Contains(err.Error(), "Failed to create cgroup") {
+		t.Errorf("expected the cgroup creation to fail, got %v", err)
+	}
+}
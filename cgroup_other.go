@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package cron
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// cgroup is only supported on Linux.
+type cgroup struct{}
+
+func newCgroup(cpus float64, memory int64) (*cgroup, error) {
+	return nil, errors.New("Resource limits require Linux cgroups")
+}
+
+func (g *cgroup) hold(cmd *exec.Cmd) (func(), error) { return func() {}, nil }
+func (g *cgroup) add(pid int) error                  { return nil }
+func (g *cgroup) oomKilled() bool                    { return false }
+func (g *cgroup) remove()                            {}
@@ -0,0 +1,94 @@
+package cron
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Chaos describes the faults injected by WithChaos.
+type Chaos struct {
+	// DispatchDelay is the longest artificial delay before a run starts.
+	// Each run is delayed by a random duration up to it, on the clock of
+	// the Cron: with a FakeClock, the delay only elapses as the clock is
+	// advanced, which the runs made in the run loop would wait for forever.
+	DispatchDelay time.Duration
+	// FailureRate is the fraction of the runs, from 0 to 1, failing with
+	// ErrChaos without running the job.
+	FailureRate float64
+	// HandlerPanicRate is the fraction of the results, from 0 to 1, whose
+	// result handler panics instead of receiving them.
+	HandlerPanicRate float64
+	// ClockSkew is added to the time read from the clock of the Cron.
+	ClockSkew time.Duration
+	// Seed seeds the random choices, for reproducible faults.
+	Seed int64
+}
+
+// chaos injects the faults of a Chaos. A nil chaos injects none.
+type chaos struct {
+	Chaos
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// WithChaos makes the Cron misbehave as described by ch, so that the
+// applications can check how they deal with late runs, failures, panicking
+// result handlers and a skewed clock. It is meant for tests, never for
+// production.
+func WithChaos(ch Chaos) Option {
+	return func(c *Cron) {
+		c.chaos = &chaos{Chaos: ch, rand: rand.New(rand.NewSource(ch.Seed))}
+	}
+}
+
+// chance reports whether an event of probability rate happens.
+func (ch *chaos) chance(rate float64) bool {
+	if ch == nil || rate <= 0 {
+		return false
+	}
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.rand.Float64() < rate
+}
+
+// delay sleeps on clock for the dispatch delay of a run.
+func (ch *chaos) delay(clock Clock) {
+	if ch == nil || ch.DispatchDelay <= 0 {
+		return
+	}
+	ch.mu.Lock()
+	d := time.Duration(ch.rand.Int63n(int64(ch.DispatchDelay) + 1))
+	ch.mu.Unlock()
+	sleep(clock, d)
+}
+
+// failure returns ErrChaos for the runs to fail.
+func (ch *chaos) failure(id string) error {
+	if ch == nil || !ch.chance(ch.FailureRate) {
+		return nil
+	}
+	return fmt.Errorf("%w: run of %s", ErrChaos, id)
+}
+
+// handler returns h, panicking for the results it should not receive.
+func (ch *chaos) handler(h func(*JobResult)) func(*JobResult) {
+	if ch == nil || ch.HandlerPanicRate <= 0 || h == nil {
+		return h
+	}
+	return func(r *JobResult) {
+		if ch.chance(ch.HandlerPanicRate) {
+			panic(fmt.Sprintf("cron: chaos: result handler of %s", r.JobId))
+		}
+		h(r)
+	}
+}
+
+// skew returns the clock skew.
+func (ch *chaos) skew() time.Duration {
+	if ch == nil {
+		return 0
+	}
+	return ch.ClockSkew
+}
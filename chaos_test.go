@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type chaosJob struct {
+	runs int32
+}
+
+func (j *chaosJob) ID() string { return "job" }
+
+func (j *chaosJob) Run() (string, error) {
+	atomic.AddInt32(&j.runs, 1)
+	return "", nil
+}
+
+func TestChaosFailures(t *testing.T) {
+	const seed, maxDelay = 7, 50 * time.Millisecond
+	c := New(WithChaos(Chaos{FailureRate: 1, DispatchDelay: maxDelay, Seed: seed}))
+	results := make(chan *JobResult, 1)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	job := &chaosJob{}
+	c.AddJob("@yearly", job)
+	c.Start()
+	defer c.Stop()
+
+	c.RunNow("job")
+	r := <-results
+	if !errors.Is(r.Error, ErrChaos) || atomic.LoadInt32(&job.runs) != 0 {
+		t.Errorf("expected an injected failure without running the job, got %+v", r)
+	}
+	delay := time.Duration(rand.New(rand.NewSource(seed)).Int63n(int64(maxDelay) + 1))
+	if r.Wait < delay {
+		t.Errorf("expected the run to wait at least %v, got %v", delay, r.Wait)
+	}
+}
+
+func TestChaosHandlerPanics(t *testing.T) {
+	received := 0
+	h := func(*JobResult) { received++ }
+	ch := &chaos{Chaos: Chaos{HandlerPanicRate: 1}, rand: rand.New(rand.NewSource(1))}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the handler to panic")
+			}
+		}()
+		ch.handler(h)(&JobResult{JobId: "job"})
+	}()
+	if received != 0 {
+		t.Errorf("expected no result received, got %d", received)
+	}
+
+	var none *chaos
+	none.handler(h)(&JobResult{})
+	if received != 1 || none.failure("job") != nil || none.skew() != 0 {
+		t.Error("expected no fault without chaos")
+	}
+}
+
+func TestChaosClockSkew(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(WithClock(NewFakeClock(start)), WithLocation(time.UTC), WithChaos(Chaos{ClockSkew: -time.Minute}))
+	if now := c.now(); !now.Equal(start.Add(-time.Minute)) {
+		t.Errorf("expected the clock skewed by a minute, got %v", now)
+	}
+}
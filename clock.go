@@ -0,0 +1,56 @@
+package cron
+
+import "time"
+
+// Clock tells the time and creates timers for a Cron. It lets tests and
+// simulations control the time seen by the scheduler.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer sending the time on its channel after at
+	// least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel the time is sent on when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer
+	// already fired or was stopped.
+	Stop() bool
+	// Reset changes the timer to fire after duration d. It must be called
+	// on stopped or fired timers whose channel was drained, and returns
+	// whether the timer was active.
+	Reset(d time.Duration) bool
+}
+
+// RealClock is the Clock of the system, backed by the time package.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+func (RealClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+
+func (t realTimer) Stop() bool { return t.t.Stop() }
+
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+// sleep waits for d to elapse on clock.
+func sleep(clock Clock, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-clock.NewTimer(d).C()
+}
+
+// WithClock makes the Cron use the given clock instead of the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *Cron) {
+		c.clock = clock
+	}
+}
@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+// stubClock is a Clock frozen at a given time, whose timers are fired by the
+// test.
+type stubClock struct {
+	now    time.Time
+	timers chan *stubTimer
+}
+
+type stubTimer struct {
+	clock *stubClock
+	d     time.Duration
+	c     chan time.Time
+}
+
+func (c *stubClock) Now() time.Time { return c.now }
+
+func (c *stubClock) NewTimer(d time.Duration) Timer {
+	t := &stubTimer{c, d, make(chan time.Time, 1)}
+	c.timers <- t
+	return t
+}
+
+func (t *stubTimer) C() <-chan time.Time { return t.c }
+
+func (t *stubTimer) Stop() bool { return true }
+
+func (t *stubTimer) Reset(d time.Duration) bool {
+	t.d = d
+	t.clock.timers <- t
+	return false
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &stubClock{
+		now:    time.Date(2020, 1, 1, 0, 0, 30, 0, time.UTC),
+		timers: make(chan *stubTimer, 10),
+	}
+	cron := New(WithClock(clock))
+	cron.AddResultHandler(func(*JobResult) {})
+	ran := make(chan struct{}, 1)
+	cron.AddFunc("0 * * * * *", func() (string, error) {
+		ran <- struct{}{}
+		return "", nil
+	})
+	cron.Start()
+	defer cron.Stop()
+
+	timer := <-clock.timers
+	if timer.d != 30*time.Second {
+		t.Fatalf("expected a timer of 30s, got %s", timer.d)
+	}
+	timer.c <- clock.now.Add(timer.d)
+	select {
+	case <-ran:
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to run when the clock's timer fired")
+	}
+}
@@ -0,0 +1,189 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// CloudEventTypePrefix prefixes the type of the CloudEvents built from
+// scheduler events, e.g. "com.github.ringtail.cron.job.finished".
+const CloudEventTypePrefix = "com.github.ringtail.cron."
+
+// ResultCloudEventType is the type of the CloudEvents built from the
+// results of the runs.
+const ResultCloudEventType = CloudEventTypePrefix + "job.result"
+
+// CloudEvent is an event in the structured JSON format of CloudEvents 1.0.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Event     `json:"data"`
+}
+
+// NewCloudEvent wraps a scheduler event in a CloudEvent coming from source.
+// The subject is the entry ID.
+func NewCloudEvent(source string, e Event) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.Must(uuid.NewV4(), nil).String(),
+		Source:          source,
+		Type:            CloudEventTypePrefix + strings.Replace(string(e.Type), "_", ".", -1),
+		Subject:         e.EntryID,
+		Time:            e.Time,
+		DataContentType: "application/json",
+		Data:            e,
+	}
+}
+
+// resultCloudEvent is a CloudEvent whose data is a result, encoded like by
+// JobResult.MarshalJSON.
+type resultCloudEvent struct {
+	CloudEvent
+	Data resultJSON `json:"data"`
+}
+
+// CloudEventSender delivers a CloudEvent encoded in JSON.
+type CloudEventSender func(ctx context.Context, event []byte) error
+
+// HTTPCloudEventSender posts events to url in structured content mode, as
+// expected by Knative brokers and other CloudEvents receivers. A nil client
+// means http.DefaultClient.
+func HTTPCloudEventSender(client *http.Client, url string) CloudEventSender {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, event []byte) error {
+		req, err := http.NewRequest("POST", url, bytes.NewReader(event))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("POST %s: %s", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+// BrokerCloudEventSender publishes events to topic on the broker at url,
+// using the publishers registered with RegisterPublisher, e.g. "kafka".
+func BrokerCloudEventSender(url, topic string) CloudEventSender {
+	return func(ctx context.Context, event []byte) error {
+		return publish(ctx, url, topic, event)
+	}
+}
+
+// CloudEventSink emits scheduler events as CloudEvents.
+type CloudEventSink struct {
+	// Source is the CloudEvents source attribute, e.g. "/cron/production".
+	Source string
+	Send   CloudEventSender
+	// Types restricts the emitted events. Empty means every event.
+	Types []EventType
+	// Timeout bounds each delivery. It defaults to 10 seconds.
+	Timeout time.Duration
+	// Results also emits the results of the runs, as CloudEvents of type
+	// ResultCloudEventType, see DeliverResult.
+	Results bool
+}
+
+// NewCloudEventSink returns a sink sending events from source with send.
+func NewCloudEventSink(source string, send CloudEventSender, types ...EventType) *CloudEventSink {
+	return &CloudEventSink{Source: source, Send: send, Types: types}
+}
+
+// Attach subscribes the sink to the events of c. Failed deliveries are
+// logged to the error log of c. Calling detach stops it.
+func (s *CloudEventSink) Attach(c *Cron, buffer int) (detach func()) {
+	events, cancel := c.SubscribeEvents(buffer)
+	go func() {
+		for e := range events {
+			if err := s.Deliver(e); err != nil {
+				c.logf("cron: failed to deliver %s event: %s", e.Type, err)
+			}
+		}
+	}()
+	if !s.Results {
+		return cancel
+	}
+	results, cancelResults := c.SubscribeResults(buffer)
+	go func() {
+		for r := range results {
+			if err := s.DeliverResult(r, c.now()); err != nil {
+				c.logf("cron: failed to deliver result of %s: %s", r.JobId, err)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		cancelResults()
+	}
+}
+
+// Deliver sends the event unless its type is filtered out.
+func (s *CloudEventSink) Deliver(e Event) error {
+	if len(s.Types) > 0 {
+		wanted := false
+		for _, t := range s.Types {
+			wanted = wanted || t == e.Type
+		}
+		if !wanted {
+			return nil
+		}
+	}
+	data, err := json.Marshal(NewCloudEvent(s.Source, e))
+	if err != nil {
+		return err
+	}
+	return s.send(data)
+}
+
+// DeliverResult sends the result of a run finished at t, whatever the
+// Types of the sink. The subject of the CloudEvent is the entry ID.
+func (s *CloudEventSink) DeliverResult(r *JobResult, t time.Time) error {
+	ce := resultCloudEvent{
+		CloudEvent: CloudEvent{
+			SpecVersion:     "1.0",
+			ID:              uuid.Must(uuid.NewV4(), nil).String(),
+			Source:          s.Source,
+			Type:            ResultCloudEventType,
+			Subject:         r.JobId,
+			Time:            t,
+			DataContentType: "application/json",
+		},
+		Data: newResultJSON(r),
+	}
+	data, err := json.Marshal(ce)
+	if err != nil {
+		return err
+	}
+	return s.send(data)
+}
+
+// send sends an encoded CloudEvent within the timeout of the sink.
+func (s *CloudEventSink) send(data []byte) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.Send(ctx, data)
+}
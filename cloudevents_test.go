@@ -0,0 +1,89 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCloudEventSink(t *testing.T) {
+	received := make(chan CloudEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/cloudevents+json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var ce CloudEvent
+		json.Unmarshal(body, &ce)
+		received <- ce
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	cron.Schedule(Every(time.Hour), testChanJob{"job", make(chan struct{}, 1)})
+	sink := NewCloudEventSink("/cron/test", HTTPCloudEventSender(nil, srv.URL), EventJobFinished)
+	detach := sink.Attach(cron, 10)
+	defer detach()
+	cron.RunNow("job")
+
+	select {
+	case ce := <-received:
+		if ce.SpecVersion != "1.0" || ce.ID == "" || ce.Source != "/cron/test" ||
+			ce.Type != "com.github.ringtail.cron.job.finished" || ce.Subject != "job" || ce.Data.Msg != "job" {
+			t.Errorf("unexpected event %+v", ce)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected a CloudEvent")
+	}
+	select {
+	case ce := <-received:
+		t.Errorf("expected other event types to be filtered, got %s", ce.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCloudEventSinkResults(t *testing.T) {
+	received := make(chan []byte, 2)
+	sink := NewCloudEventSink("/cron/test", func(ctx context.Context, event []byte) error {
+		received <- event
+		return nil
+	}, EventJobStarted)
+	sink.Results = true
+	at := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	if err := sink.DeliverResult(&JobResult{JobId: "job", Msg: "done", Attempt: 2, MaxAttempts: 3}, at); err != nil {
+		t.Fatal(err)
+	}
+	var ce struct {
+		CloudEvent
+		Data JobResult `json:"data"`
+	}
+	if err := json.Unmarshal(<-received, &ce); err != nil {
+		t.Fatal(err)
+	}
+	if ce.Type != ResultCloudEventType || ce.Subject != "job" || !ce.Time.Equal(at) ||
+		ce.Data.JobId != "job" || ce.Data.Msg != "done" || ce.Data.Attempt != 2 || ce.Data.MaxAttempts != 3 {
+		t.Errorf("unexpected result event %+v", ce)
+	}
+}
+
+func TestBrokerCloudEventSender(t *testing.T) {
+	var topics []string
+	RegisterPublisher("cetest", func(ctx context.Context, u *url.URL) (Publisher, error) {
+		return testPublisher{&topics}, nil
+	})
+	sink := NewCloudEventSink("/cron", BrokerCloudEventSender("cetest://broker", "cron-events"))
+	if err := sink.Deliver(Event{Type: EventEntryAdded, EntryID: "job"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0] != "cron-events" {
+		t.Errorf("topics = %v", topics)
+	}
+}
@@ -0,0 +1,225 @@
+// Command cronctl operates a scheduler through its admin HTTP API (see
+// package admin).
+//
+// Usage:
+//
+//	cronctl [-addr url] <command> [arguments]
+//
+// Commands:
+//
+//	list                 list entries
+//	validate <spec>      check that a spec parses
+//	next [-n N] <spec>   show the next N activation times of a spec
+//	tail [-entry id]     print job runs as they happen
+//	pause <id>           pause an entry
+//	resume <id>          resume a paused entry
+//	run <id>             run an entry now
+//	import <crontab>     add the entries of a crontab file ("-" for stdin)
+//
+// The address defaults to $CRONCTL_ADDR, or http://localhost:8080.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin/client"
+)
+
+const usage = `usage: cronctl [-addr url] <command> [arguments]
+
+commands:
+  list                 list entries
+  validate <spec>      check that a spec parses
+  next [-n N] <spec>   show the next N activation times of a spec
+  tail [-entry id]     print job runs as they happen
+  pause <id>           pause an entry
+  resume <id>          resume a paused entry
+  run <id>             run an entry now
+  import <crontab>     add the entries of a crontab file ("-" for stdin)
+`
+
+// errUsage reports invalid command line usage.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == errUsage:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "cronctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("cronctl", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	addr := fs.String("addr", defaultAddr(), "admin API address")
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		return errUsage
+	}
+	c := client.New(*addr)
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+
+	switch cmd {
+	case "list":
+		return list(c, stdout)
+	case "validate":
+		if len(args) != 1 {
+			return errUsage
+		}
+		if _, err := cron.Parse(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "ok")
+		return nil
+	case "next":
+		return next(args, stdout)
+	case "tail":
+		tfs := flag.NewFlagSet("tail", flag.ContinueOnError)
+		tfs.SetOutput(ioutil.Discard)
+		entry := tfs.String("entry", "", "only print the runs of this entry")
+		if err := tfs.Parse(args); err != nil {
+			return errUsage
+		}
+		return tail(c, stdout, *entry, nil)
+	case "pause":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return c.PauseEntry(args[0])
+	case "resume":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return c.ResumeEntry(args[0])
+	case "run":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return c.RunEntry(args[0])
+	case "import":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return importCrontab(c, args[0], stdin, stdout)
+	}
+	return errUsage
+}
+
+func defaultAddr() string {
+	if addr := os.Getenv("CRONCTL_ADDR"); addr != "" {
+		return addr
+	}
+	return "http://localhost:8080"
+}
+
+func next(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("next", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	n := fs.Int("n", 5, "number of activation times")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errUsage
+	}
+	schedule, err := cron.Parse(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	for i := 0; i < *n; i++ {
+		if t = schedule.Next(t); t.IsZero() {
+			break
+		}
+		fmt.Fprintln(stdout, t.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func list(c *client.Client, stdout io.Writer) error {
+	entries, err := c.ListEntries()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSPEC\tNEXT\tLAST\tRUNS\tFAILURES\tSTATUS")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			e.ID, e.Spec, formatTime(e.Next), formatTime(e.Stats.LastRun), e.Stats.Runs, e.Stats.Failures, e.Status)
+	}
+	return tw.Flush()
+}
+
+// tail prints job runs as they finish, until stop is closed.
+func tail(c *client.Client, stdout io.Writer, entry string, stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	started := make(map[string]time.Time)
+	return c.Events(ctx, entry, func(e cron.Event) error {
+		switch e.Type {
+		case cron.EventJobStarted:
+			started[e.EntryID] = e.Time
+		case cron.EventJobFinished:
+			start, ok := started[e.EntryID]
+			if !ok {
+				start = e.Time
+			}
+			delete(started, e.EntryID)
+			result := "ok"
+			if e.Error != "" {
+				result = "error: " + e.Error
+			}
+			fmt.Fprintf(stdout, "%s %s %s (%s) %s\n",
+				start.Format(time.RFC3339), e.EntryID, result, e.Time.Sub(start), e.Msg)
+		}
+		return nil
+	})
+}
+
+func importCrontab(c *client.Client, path string, stdin io.Reader, stdout io.Writer) error {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	cfg, err := cron.ParseCrontab(r)
+	if err != nil {
+		return err
+	}
+	for _, jc := range cfg.Jobs {
+		if _, err := c.AddJob(jc); err != nil {
+			return fmt.Errorf("%s: %s", jc.Name, err)
+		}
+		fmt.Fprintln(stdout, "added", jc.Name)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
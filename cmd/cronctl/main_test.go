@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/admin"
+	"github.com/ringtail/go-cron/admin/client"
+)
+
+type commandJob struct {
+	id     string
+	params map[string]string
+}
+
+func (j *commandJob) ID() string                { return j.id }
+func (j *commandJob) Run() (string, error)      { return "done", nil }
+func (j *commandJob) JobType() string           { return cron.CommandJobType }
+func (j *commandJob) Params() map[string]string { return j.params }
+
+func init() {
+	cron.RegisterJobType(cron.CommandJobType, func(id string, params map[string]string) (cron.Job, error) {
+		return &commandJob{id, params}, nil
+	})
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	c := cron.New()
+	c.AddResultHandler(func(*cron.JobResult) {})
+	c.Start()
+	srv := httptest.NewServer(admin.NewHandler(c))
+	t.Cleanup(func() {
+		srv.Close()
+		c.Stop()
+	})
+	return srv
+}
+
+func runCmd(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := run(args, strings.NewReader(stdin), &out); err != nil {
+		t.Fatalf("cronctl %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String()
+}
+
+func TestImportListPause(t *testing.T) {
+	srv := newTestServer(t)
+
+	out := runCmd(t, "# backup\n30 2 * * * backup.sh\n", "-addr", srv.URL, "import", "-")
+	if out != "added backup\n" {
+		t.Errorf("unexpected import output %q", out)
+	}
+	runCmd(t, "", "-addr", srv.URL, "pause", "backup")
+
+	out = runCmd(t, "", "-addr", srv.URL, "list")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "backup ") || !strings.HasSuffix(lines[1], "paused") {
+		t.Errorf("unexpected list output:\n%s", out)
+	}
+	runCmd(t, "", "-addr", srv.URL, "resume", "backup")
+	if out = runCmd(t, "", "-addr", srv.URL, "list"); !strings.HasSuffix(strings.TrimSpace(out), "scheduled") {
+		t.Errorf("unexpected list output:\n%s", out)
+	}
+
+	if err := run([]string{"-addr", srv.URL, "run", "missing"}, nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a missing entry")
+	}
+}
+
+func TestTail(t *testing.T) {
+	srv := newTestServer(t)
+	runCmd(t, "# job\n@yearly job.sh\n", "-addr", srv.URL, "import", "-")
+
+	var out bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan error)
+	c := client.New(srv.URL)
+	go func() { done <- tail(c, &out, "", stop) }()
+
+	time.Sleep(50 * time.Millisecond)
+	runCmd(t, "", "-addr", srv.URL, "run", "job")
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), " job ok ") {
+		t.Errorf("expected the run to be printed, got %q", out.String())
+	}
+}
+
+func TestLocalCommands(t *testing.T) {
+	if out := runCmd(t, "", "validate", "0 30 * * * *"); out != "ok\n" {
+		t.Errorf("unexpected validate output %q", out)
+	}
+	if err := run([]string{"validate", "bogus"}, nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+	out := runCmd(t, "", "next", "-n", "3", "@hourly")
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 3 {
+		t.Errorf("expected 3 activation times, got:\n%s", out)
+	}
+	if err := run([]string{"frobnicate"}, nil, &bytes.Buffer{}); err != errUsage {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
@@ -0,0 +1,709 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Config is a declarative description of the jobs a Cron should run.
+type Config struct {
+	Jobs []JobConfig `json:"jobs"`
+}
+
+// JobConfig describes a single job. The name identifies the job and is used
+// as its id; the type selects the factory registered with RegisterJobType.
+type JobConfig struct {
+	Name    string            `json:"name"`
+	Spec    string            `json:"spec"`
+	Type    string            `json:"type"`
+	Params  map[string]string `json:"params,omitempty"`
+	Timeout string            `json:"timeout,omitempty"`
+	Retries int               `json:"retries,omitempty"`
+	// Misfire is the misfire policy of the job.
+	Misfire MisfirePolicy `json:"misfire,omitempty"`
+	// MaxRuns is the number of runs after which the job is removed.
+	MaxRuns int `json:"max_runs,omitempty"`
+	// Tags select the job in maintenance windows.
+	Tags []string `json:"tags,omitempty"`
+	// Resources are held by the job while it runs.
+	Resources []string `json:"resources,omitempty"`
+	// Namespace shares the concurrency slots fairly with other namespaces.
+	Namespace string `json:"namespace,omitempty"`
+	// Priority lets the runs of the job waiting for a slot jump the queue.
+	Priority int `json:"priority,omitempty"`
+	// Preemptible lets the runs of the job be cancelled for the runs of a
+	// higher priority.
+	Preemptible bool `json:"preemptible,omitempty"`
+	// Synchronous runs the job in the run loop, see WithSynchronous.
+	Synchronous bool `json:"synchronous,omitempty"`
+	// SLA is how long after being due each run should be done, as a Go
+	// duration.
+	SLA string `json:"sla,omitempty"`
+	// Canary rolls out the changes of the job progressively.
+	Canary *CanaryConfig `json:"canary,omitempty"`
+}
+
+var (
+	configFormatsMu sync.RWMutex
+	configFormats   = map[string]func([]byte, interface{}) error{
+		".json": json.Unmarshal,
+	}
+)
+
+// RegisterConfigFormat makes LoadConfig decode files with the given extension
+// using unmarshal. JSON is supported out of the box; YAML support can be
+// added with e.g.
+//
+//	cron.RegisterConfigFormat(".yaml", yaml.Unmarshal)
+func RegisterConfigFormat(ext string, unmarshal func([]byte, interface{}) error) {
+	configFormatsMu.Lock()
+	defer configFormatsMu.Unlock()
+	configFormats[strings.ToLower(ext)] = unmarshal
+}
+
+// LoadConfig reads a config file, picking the decoder from its extension.
+func LoadConfig(path string) (*Config, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	configFormatsMu.RLock()
+	unmarshal, ok := configFormats[ext]
+	configFormatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unsupported config format %q: %s", ext, path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("Failed to decode %s: %s", path, err)
+	}
+	return &cfg, nil
+}
+
+// LoadConfigDir reads every config file in dir with a registered extension,
+// in lexical order, and merges their jobs into a single config, mirroring
+// the /etc/cron.d model. Other files and subdirectories are ignored.
+func LoadConfigDir(dir string) (*Config, error) {
+	files, err := configFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	merged := &Config{}
+	origin := make(map[string]string)
+	for _, fi := range files {
+		path := filepath.Join(dir, fi.Name())
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, jc := range cfg.Jobs {
+			if other, dup := origin[jc.Name]; dup {
+				return nil, fmt.Errorf("Job %q defined in both %s and %s", jc.Name, other, path)
+			}
+			origin[jc.Name] = path
+			merged.Jobs = append(merged.Jobs, jc)
+		}
+	}
+	return merged, nil
+}
+
+// configFiles returns the config files of dir, sorted by name.
+func configFiles(dir string) ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	configFormatsMu.RLock()
+	defer configFormatsMu.RUnlock()
+	files := infos[:0]
+	for _, fi := range infos {
+		if _, ok := configFormats[strings.ToLower(filepath.Ext(fi.Name()))]; ok && fi.Mode().IsRegular() {
+			files = append(files, fi)
+		}
+	}
+	return files, nil
+}
+
+// AddJobConfig adds the single job described by jc, independently of any
+// config source. It fails with ErrDuplicateJob if a job with the same name
+// already exists.
+func (c *Cron) AddJobConfig(jc JobConfig) error {
+	if jc.Name == "" {
+		return fmt.Errorf("Job with spec %q has no name", jc.Spec)
+	}
+	e, err := jc.entry(c.parser)
+	if err != nil {
+		return err
+	}
+	return c.addEntry(e, false)
+}
+
+// ApplyConfig makes the configured jobs of the Cron match cfg. Jobs that are
+// unchanged since the last call keep their entry (and so their Prev time),
+// changed jobs are replaced, and jobs missing from cfg are removed. Entries
+// not added through ApplyConfig are left alone: a job of cfg named like one
+// of them fails with ErrDuplicateJob, unless the entry was restored from the
+// store. Nothing is changed if any job in cfg is invalid, or if the entries
+// would exceed WithMaxEntries.
+func (c *Cron) ApplyConfig(cfg *Config) error {
+	return c.applyConfig("", cfg)
+}
+
+// applyConfig makes the jobs of a source match cfg, like ApplyConfig.
+func (c *Cron) applyConfig(source string, cfg *Config) error {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	entries, err := c.prepareConfig(source, cfg)
+	if err != nil {
+		return err
+	}
+	return c.commitConfigs(sourceUpdate(source, cfg, entries))
+}
+
+// prepareConfig validates the config of a source and builds its entries.
+// configMu must be held.
+func (c *Cron) prepareConfig(source string, cfg *Config) (map[string]*Entry, error) {
+	entries := make(map[string]*Entry, len(cfg.Jobs))
+	for _, jc := range cfg.Jobs {
+		if jc.Name == "" {
+			return nil, fmt.Errorf("Job with spec %q has no name", jc.Spec)
+		}
+		if _, dup := entries[jc.Name]; dup {
+			return nil, fmt.Errorf("Duplicate job name %q", jc.Name)
+		}
+		for other, jobs := range c.configured {
+			if _, dup := jobs[jc.Name]; dup && other != source {
+				return nil, fmt.Errorf("Job %q is already defined by %s", jc.Name, other)
+			}
+		}
+		e, err := jc.entry(c.parser)
+		if err != nil {
+			return nil, fmt.Errorf("Job %s: %s", jc.Name, err)
+		}
+		e.locate()
+		entries[jc.Name] = e
+	}
+	var taken string
+	c.do(func() {
+		for _, jc := range cfg.Jobs {
+			if e, ok := c.entries[jc.Name]; ok && !e.restored && !c.isConfigured(jc.Name) {
+				taken = jc.Name
+				return
+			}
+		}
+	})
+	if taken != "" {
+		return nil, fmt.Errorf("%w: %s was not added by a config", ErrDuplicateJob, taken)
+	}
+	return entries, nil
+}
+
+// isConfigured reports whether a config source defines the job with the
+// given name. configMu must be held.
+func (c *Cron) isConfigured(name string) bool {
+	for _, jobs := range c.configured {
+		if _, ok := jobs[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// configUpdate is a change of config sources, applied at once by
+// commitConfigs.
+type configUpdate struct {
+	sources  []string
+	configs  []*Config
+	prepared []map[string]*Entry
+	// store tells whether the entries of the store are reloaded, as
+	// restored in stored.
+	store  bool
+	stored []*Entry
+}
+
+// sourceUpdate returns the update applying cfg and its prepared entries to
+// a source.
+func sourceUpdate(source string, cfg *Config, entries map[string]*Entry) configUpdate {
+	return configUpdate{
+		sources:  []string{source},
+		configs:  []*Config{cfg},
+		prepared: []map[string]*Entry{entries},
+	}
+}
+
+// commitConfigs applies the configs of the sources of u and their prepared
+// entries, and the entries of the store if u reloads it, at once: either
+// all of them are applied or, if the entries would exceed WithMaxEntries,
+// none is. configMu must be held.
+func (c *Cron) commitConfigs(u configUpdate) (err error) {
+	var removed, added []string
+	c.do(func() {
+		// The jobs a source no longer defines are removed, unless another
+		// source now defines them.
+		stale := make(map[string]bool)
+		defined := make(map[string]bool)
+		for source, jobs := range c.configured {
+			for name := range jobs {
+				defined[name] = true
+			}
+			for i := range u.sources {
+				if u.sources[i] != source {
+					continue
+				}
+				for name := range jobs {
+					if _, ok := u.prepared[i][name]; !ok {
+						stale[name] = true
+						delete(defined, name)
+					}
+				}
+			}
+		}
+		for _, entries := range u.prepared {
+			for name := range entries {
+				delete(stale, name)
+				defined[name] = true
+			}
+		}
+		// The store defines the entries it holds that no source defines,
+		// and no longer the restored ones it does not hold anymore.
+		var restored []*Entry
+		if u.store {
+			held := make(map[string]bool, len(u.stored))
+			for _, e := range u.stored {
+				id := e.Job.ID()
+				held[id] = true
+				if old, ok := c.entries[id]; !defined[id] && (!ok || !sameDefinition(old, e)) {
+					restored = append(restored, e)
+				}
+			}
+			for id, e := range c.entries {
+				if e.restored && !held[id] && !defined[id] {
+					stale[id] = true
+				}
+			}
+		}
+
+		count, grows := len(c.entries), false
+		for _, entries := range u.prepared {
+			for name := range entries {
+				if _, ok := c.entries[name]; !ok {
+					count, grows = count+1, true
+				}
+			}
+		}
+		for _, e := range restored {
+			if _, ok := c.entries[e.Job.ID()]; !ok {
+				count, grows = count+1, true
+			}
+		}
+		for name := range stale {
+			if _, ok := c.entries[name]; ok {
+				count--
+			}
+		}
+		if c.maxEntries > 0 && grows && count > c.maxEntries {
+			err = ErrQuotaExceeded
+			return
+		}
+
+		// Nothing may fail from here on: the entries were counted above.
+		for name := range stale {
+			if c.unlink(name) {
+				removed = append(removed, name)
+			}
+		}
+		for i, source := range u.sources {
+			configured := c.configured[source]
+			next := make(map[string]JobConfig, len(u.configs[i].Jobs))
+			for _, jc := range u.configs[i].Jobs {
+				next[jc.Name] = jc
+				if old, ok := configured[jc.Name]; ok {
+					if reflect.DeepEqual(old, jc) {
+						continue
+					}
+					if jc.Canary != nil && c.startCanary(*jc.Canary, u.prepared[i][jc.Name]) {
+						continue
+					}
+				}
+				c.insert(jc.Name, u.prepared[i][jc.Name], true)
+				added = append(added, jc.Name)
+			}
+			c.configured[source] = next
+		}
+		for _, e := range restored {
+			e.restored = true
+			c.insert(e.Job.ID(), e, true)
+			added = append(added, e.Job.ID())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range removed {
+		c.history.forget(id)
+		c.emit(Event{Type: EventEntryRemoved, EntryID: id})
+	}
+	for _, id := range added {
+		c.emit(Event{Type: EventEntryAdded, EntryID: id})
+	}
+	return nil
+}
+
+// entry builds the entry described by the job config, parsing its spec
+// with parser.
+func (jc JobConfig) entry(parser ScheduleParser) (*Entry, error) {
+	schedule, err := parser.Parse(jc.Spec)
+	if err != nil {
+		return nil, err
+	}
+	var timeout time.Duration
+	if jc.Timeout != "" {
+		if timeout, err = time.ParseDuration(jc.Timeout); err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", jc.Timeout, err)
+		}
+	}
+	if jc.Retries < 0 {
+		return nil, fmt.Errorf("Negative retries (%d) not allowed", jc.Retries)
+	}
+	if err := jc.Misfire.validate(); err != nil {
+		return nil, err
+	}
+	var sla time.Duration
+	if jc.SLA != "" {
+		if sla, err = time.ParseDuration(jc.SLA); err != nil {
+			return nil, fmt.Errorf("Failed to parse sla %s: %s", jc.SLA, err)
+		}
+	}
+	if jc.MaxRuns < 0 {
+		return nil, fmt.Errorf("Negative max runs (%d) not allowed", jc.MaxRuns)
+	}
+	job, err := newJob(jc.Type, jc.Name, jc.Params)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{
+		Schedule:    schedule,
+		Job:         &configuredJob{job, jc, timeout},
+		Spec:        jc.Spec,
+		Name:        jc.Name,
+		Misfire:     jc.Misfire,
+		MaxRuns:     jc.MaxRuns,
+		Retries:     jc.Retries,
+		Tags:        jc.Tags,
+		Resources:   sortedResources(jc.Resources),
+		Namespace:   jc.Namespace,
+		Priority:    jc.Priority,
+		Preemptible: jc.Preemptible,
+		Synchronous: jc.Synchronous,
+		SLA:         sla,
+	}, nil
+}
+
+// configuredJob applies the timeout of a JobConfig to its job. The retries
+// of the config are the ones of its entry, see WithRetries.
+type configuredJob struct {
+	Job
+	cfg     JobConfig
+	timeout time.Duration
+}
+
+func (j *configuredJob) JobType() string           { return j.cfg.Type }
+func (j *configuredJob) Params() map[string]string { return j.cfg.Params }
+
+func (j *configuredJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext runs the job, cancelling its context once the timeout of the
+// config elapses. A job ignoring its context runs to completion, but fails
+// all the same if it took longer than the timeout.
+func (j *configuredJob) RunContext(ctx context.Context) (string, error) {
+	if j.timeout <= 0 {
+		return runJob(ctx, j.Job)
+	}
+	ctx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+	msg, err := runJob(ctx, j.Job)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return msg, fmt.Errorf("Job %s timed out after %s", j.cfg.Name, j.timeout)
+	}
+	return msg, err
+}
+
+// ConfigWatcher periodically checks a config source for changes and applies
+// it to a Cron whenever it changed. Each watcher manages its own set of jobs,
+// so several sources can be watched by the same Cron.
+type ConfigWatcher struct {
+	cron        *Cron
+	source      string
+	load        func() (*Config, error)
+	fingerprint func() (string, error)
+	last        string
+	mu          sync.Mutex
+	done        chan struct{}
+	once        sync.Once
+}
+
+// WatchConfigFile applies the config file to the Cron, then re-applies it
+// whenever its modification time or size changes, checking every interval,
+// which must be positive. Reload errors are logged and leave the current
+// entries in place. The file is polled rather than watched for file system
+// events, so an edit keeping both its modification time and size is missed
+// until the next one or a call to Reload.
+func (c *Cron) WatchConfigFile(path string, interval time.Duration) (*ConfigWatcher, error) {
+	return c.watchConfig(path, func() (*Config, error) {
+		return LoadConfig(path)
+	}, func() (string, error) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d:%d", fi.ModTime().UnixNano(), fi.Size()), nil
+	}, interval)
+}
+
+// WatchConfigDir applies the merged config files of dir to the Cron (see
+// LoadConfigDir), then re-applies them whenever a file is added, removed or
+// modified, checking every interval. Like WatchConfigFile, it polls the
+// modification times and sizes of the files.
+func (c *Cron) WatchConfigDir(dir string, interval time.Duration) (*ConfigWatcher, error) {
+	return c.watchConfig(dir, func() (*Config, error) {
+		return LoadConfigDir(dir)
+	}, func() (string, error) {
+		files, err := configFiles(dir)
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		for _, fi := range files {
+			fmt.Fprintf(&b, "%s:%d:%d;", fi.Name(), fi.ModTime().UnixNano(), fi.Size())
+		}
+		return b.String(), nil
+	}, interval)
+}
+
+func (c *Cron) watchConfig(source string, load func() (*Config, error), fingerprint func() (string, error), interval time.Duration) (*ConfigWatcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("Invalid watch interval %s", interval)
+	}
+	w := &ConfigWatcher{
+		cron:        c,
+		source:      source,
+		load:        load,
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	c.configMu.Lock()
+	c.watchers[w] = struct{}{}
+	c.configMu.Unlock()
+	go w.watch(interval)
+	return w, nil
+}
+
+// Reload loads and applies the config source, whether it changed or not.
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.reload()
+}
+
+// reload loads and applies the config source. w.mu must be held.
+func (w *ConfigWatcher) reload() error {
+	fp, cfg, err := w.read()
+	if err != nil {
+		return err
+	}
+	w.cron.configMu.Lock()
+	defer w.cron.configMu.Unlock()
+	entries, err := w.cron.prepareConfig(w.source, cfg)
+	if err != nil {
+		return err
+	}
+	if err := w.cron.commitConfigs(sourceUpdate(w.source, cfg, entries)); err != nil {
+		return err
+	}
+	w.last = fp
+	return nil
+}
+
+// read loads the config source along with its fingerprint.
+func (w *ConfigWatcher) read() (string, *Config, error) {
+	fp, err := w.fingerprint()
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := w.load()
+	if err != nil {
+		return "", nil, err
+	}
+	return fp, cfg, nil
+}
+
+func (w *ConfigWatcher) watch(interval time.Duration) {
+	timer := w.cron.clock.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C():
+			w.check()
+			timer.Reset(interval)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// check applies the config source if its fingerprint changed.
+func (w *ConfigWatcher) check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fp, err := w.fingerprint()
+	if err == nil && fp != w.last {
+		err = w.reload()
+	}
+	if err != nil {
+		w.cron.logf("cron: failed to reload config %s: %v", w.source, err)
+	}
+}
+
+// Close stops watching. The applied entries stay in place.
+func (w *ConfigWatcher) Close() {
+	w.once.Do(func() {
+		close(w.done)
+		w.cron.configMu.Lock()
+		delete(w.cron.watchers, w)
+		w.cron.configMu.Unlock()
+	})
+}
+
+// ReloadConfig reloads every watched config source, and the entries of the
+// store set by WithStore, at once. Either all sources are applied or, if any
+// of them fails to load or is invalid, or if the entries would exceed
+// WithMaxEntries, none is. The store defines the entries it holds that no
+// config source defines: they are added, or replaced if their definition
+// changed, and the entries restored from the store that it no longer holds
+// are removed.
+func (c *Cron) ReloadConfig() error {
+	c.configMu.Lock()
+	watchers := make([]*ConfigWatcher, 0, len(c.watchers))
+	for w := range c.watchers {
+		watchers = append(watchers, w)
+	}
+	c.configMu.Unlock()
+	sort.Slice(watchers, func(i, j int) bool { return watchers[i].source < watchers[j].source })
+
+	for _, w := range watchers {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+
+	type loaded struct {
+		fp  string
+		cfg *Config
+	}
+	configs := make([]loaded, len(watchers))
+	for i, w := range watchers {
+		fp, cfg, err := w.read()
+		if err != nil {
+			return err
+		}
+		configs[i] = loaded{fp, cfg}
+	}
+	stored, err := c.loadStoredEntries()
+	if err != nil {
+		return err
+	}
+
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	// Validate every source against the others as they will be once applied.
+	current := c.configured
+	c.configured = make(map[string]map[string]JobConfig, len(current))
+	for source, jobs := range current {
+		c.configured[source] = jobs
+	}
+	for i, w := range watchers {
+		jobs := make(map[string]JobConfig, len(configs[i].cfg.Jobs))
+		for _, jc := range configs[i].cfg.Jobs {
+			jobs[jc.Name] = jc
+		}
+		c.configured[w.source] = jobs
+	}
+	prepared := make([]map[string]*Entry, len(watchers))
+	for i, w := range watchers {
+		entries, err := c.prepareConfig(w.source, configs[i].cfg)
+		if err != nil {
+			c.configured = current
+			return fmt.Errorf("%s: %s", w.source, err)
+		}
+		prepared[i] = entries
+	}
+	c.configured = current
+
+	u := configUpdate{
+		sources:  make([]string, len(watchers)),
+		configs:  make([]*Config, len(watchers)),
+		prepared: prepared,
+		store:    c.store != nil,
+		stored:   stored,
+	}
+	for i, w := range watchers {
+		u.sources[i], u.configs[i] = w.source, configs[i].cfg
+	}
+	if err := c.commitConfigs(u); err != nil {
+		return err
+	}
+	for i, w := range watchers {
+		w.last = configs[i].fp
+	}
+	return nil
+}
+
+// ReloadOnSignal calls ReloadConfig whenever the process receives one of the
+// given signals, or SIGHUP if none are given, the way classic cron daemons
+// are reloaded. Reload errors are logged. The returned function stops
+// listening for the signals.
+func (c *Cron) ReloadOnSignal(sig ...os.Signal) (stop func()) {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := c.ReloadConfig(); err != nil {
+					c.logf("cron: failed to reload config: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
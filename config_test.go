@@ -0,0 +1,416 @@
+package cron
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestApplyConfigDiff(t *testing.T) {
+	c := New()
+	c.AddFunc("@hourly", func() (string, error) { return "", nil })
+	err := c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "keep", Spec: "@hourly", Type: "test"},
+		{Name: "change", Spec: "@hourly", Type: "test"},
+		{Name: "drop", Spec: "@hourly", Type: "test"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := time.Date(2012, 7, 9, 14, 45, 0, 0, time.UTC)
+	c.entries["keep"].Prev = prev
+	c.entries["change"].Prev = prev
+
+	err = c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "keep", Spec: "@hourly", Type: "test"},
+		{Name: "change", Spec: "@daily", Type: "test"},
+		{Name: "add", Spec: "@daily", Type: "test"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(c.entries))
+	}
+	if _, ok := c.entries["drop"]; ok {
+		t.Error("expected removed job to be dropped")
+	}
+	if !c.entries["keep"].Prev.Equal(prev) {
+		t.Error("expected unchanged job to keep its Prev time")
+	}
+	if e := c.entries["change"]; e.Spec != "@daily" || !e.Prev.IsZero() {
+		t.Errorf("expected changed job to be replaced, got spec %q prev %v", e.Spec, e.Prev)
+	}
+}
+
+func TestApplyConfigInvalid(t *testing.T) {
+	c := New()
+	err := c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "ok", Spec: "@hourly", Type: "test"},
+		{Name: "bad", Spec: "@hourly", Type: "test", Timeout: "soon"},
+	}})
+	if err == nil {
+		t.Fatal("expected an error for an invalid timeout")
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(c.entries))
+	}
+}
+
+func TestApplyConfigTakenName(t *testing.T) {
+	c := New()
+	c.AddJob("@hourly", &testDescribedJob{"taken", "mine"})
+	err := c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "ok", Spec: "@hourly", Type: "test"},
+		{Name: "taken", Spec: "@daily", Type: "test"},
+	}})
+	if !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("expected ErrDuplicateJob, got %v", err)
+	}
+	if e := c.entries["taken"]; len(c.entries) != 1 || e.Spec != "@hourly" {
+		t.Errorf("expected the entry to be left alone, got %d entries", len(c.entries))
+	}
+
+	// Entries restored from the store may be taken over.
+	c.entries["taken"].restored = true
+	if err := c.ApplyConfig(&Config{Jobs: []JobConfig{{Name: "taken", Spec: "@daily", Type: "test"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if e := c.entries["taken"]; e.Spec != "@daily" {
+		t.Errorf("expected the restored entry to be replaced, got %q", e.Spec)
+	}
+}
+
+func TestApplyConfigQuota(t *testing.T) {
+	c := New(WithMaxEntries(2))
+	if err := c.ApplyConfig(&Config{Jobs: []JobConfig{{Name: "a", Spec: "@hourly", Type: "test"}}}); err != nil {
+		t.Fatal(err)
+	}
+	err := c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "a", Spec: "@daily", Type: "test"},
+		{Name: "b", Spec: "@hourly", Type: "test"},
+		{Name: "c", Spec: "@hourly", Type: "test"},
+	}})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if e := c.entries["a"]; len(c.entries) != 1 || e.Spec != "@hourly" {
+		t.Errorf("expected nothing to be applied, got %d entries", len(c.entries))
+	}
+	// Replacing a job with another one fits.
+	if err := c.ApplyConfig(&Config{Jobs: []JobConfig{
+		{Name: "b", Spec: "@hourly", Type: "test"},
+		{Name: "c", Spec: "@hourly", Type: "test"},
+	}}); err != nil || len(c.entries) != 2 {
+		t.Errorf("expected the jobs to be replaced, got %v and %d entries", err, len(c.entries))
+	}
+}
+
+type flakyJob struct {
+	failures int
+	calls    int
+}
+
+func (j *flakyJob) ID() string { return "flaky" }
+
+func (j *flakyJob) Run() (string, error) {
+	j.calls++
+	if j.calls <= j.failures {
+		return "", errors.New("failed")
+	}
+	return "done", nil
+}
+
+func TestConfiguredJobRetries(t *testing.T) {
+	RegisterJobType("flaky", func(id string, params map[string]string) (Job, error) {
+		return &flakyJob{failures: 2}, nil
+	})
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	var results []*JobResult
+	c.AddResultHandler(func(r *JobResult) { results = append(results, r) })
+	if err := c.AddJobConfig(JobConfig{Name: "flaky", Spec: "@hourly", Type: "flaky", Retries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Hour)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Attempt != i+1 || r.MaxAttempts != 3 || (r.Error == nil) != (i == 2) {
+			t.Errorf("unexpected result of attempt %d: %+v", i+1, r)
+		}
+	}
+}
+
+func TestConfiguredJobTimeout(t *testing.T) {
+	slow := FuncJob(func() (string, error) {
+		time.Sleep(time.Second)
+		return "", nil
+	})
+	job := &configuredJob{slow, JobConfig{Name: "slow"}, 10 * time.Millisecond}
+	if _, err := job.Run(); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
+
+func TestJobConfigParser(t *testing.T) {
+	c := New(WithParser(NewParser(Minute | Hour | Dom | Month | Dow)))
+	if err := c.AddJobConfig(JobConfig{Name: "a", Spec: "30 2 * * 1", Type: "test"}); err != nil {
+		t.Fatalf("expected the spec to be parsed by the parser of the Cron, got %v", err)
+	}
+	if _, err := c.WatchConfigFile("jobs.json", 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "jobs.json")
+	write := func(content string, mtime time.Time) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, mtime, mtime)
+	}
+	write(`{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"}]}`, time.Now().Add(-time.Minute))
+
+	c := New()
+	w, err := c.WatchConfigFile(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if len(c.Entries()) != 1 {
+		t.Fatalf("expected 1 entry at startup, got %d", len(c.Entries()))
+	}
+
+	write(`{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"},
+		{"name": "b", "spec": "@daily", "type": "test"}]}`, time.Now())
+	deadline := time.Now().Add(OneSecond)
+	for {
+		w.mu.Lock()
+		n := len(c.entries)
+		w.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the changed config to be applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoadConfigDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.json":    `{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"}]}`,
+		"b.json":    `{"jobs": [{"name": "b", "spec": "@daily", "type": "test"}]}`,
+		"README.md": `not a config file`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := LoadConfigDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Jobs) != 2 || cfg.Jobs[0].Name != "a" || cfg.Jobs[1].Name != "b" {
+		t.Errorf("unexpected merged jobs %v", cfg.Jobs)
+	}
+
+	dup := `{"jobs": [{"name": "a", "spec": "@daily", "type": "test"}]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "c.json"), []byte(dup), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigDir(dir); err == nil {
+		t.Error("expected an error for a job defined in two files")
+	}
+}
+
+func TestWatchConfigDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a.json")
+	if err := ioutil.WriteFile(a, []byte(`{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	w, err := c.WatchConfigDir(dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	b := filepath.Join(dir, "b.json")
+	if err := ioutil.WriteFile(b, []byte(`{"jobs": [{"name": "b", "spec": "@daily", "type": "test"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(a)
+
+	deadline := time.Now().Add(OneSecond)
+	for {
+		w.mu.Lock()
+		_, hasA := c.entries["a"]
+		_, hasB := c.entries["b"]
+		w.mu.Unlock()
+		if !hasA && hasB {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the directory change to be applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "main.json")
+	jobsDir := filepath.Join(dir, "cron.d")
+	os.Mkdir(jobsDir, 0755)
+	write := func(path, content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(file, `{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"}]}`)
+	write(filepath.Join(jobsDir, "b.json"), `{"jobs": [{"name": "b", "spec": "@hourly", "type": "test"}]}`)
+
+	c := New()
+	fw, err := c.WatchConfigFile(file, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fw.Close()
+	dw, err := c.WatchConfigDir(jobsDir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dw.Close()
+	if len(c.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(c.entries))
+	}
+
+	// A job moves from the file to the directory, while the file gets an
+	// invalid job: nothing may be applied.
+	write(file, `{"jobs": [{"name": "c", "spec": "bogus", "type": "test"}]}`)
+	write(filepath.Join(jobsDir, "b.json"), `{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"},
+		{"name": "b", "spec": "@hourly", "type": "test"}]}`)
+	if err := c.ReloadConfig(); err == nil {
+		t.Fatal("expected an error for the invalid job")
+	}
+	if len(c.entries) != 2 {
+		t.Fatalf("expected entries to be unchanged, got %d", len(c.entries))
+	}
+
+	write(file, `{"jobs": [{"name": "c", "spec": "@daily", "type": "test"}]}`)
+	stop := c.ReloadOnSignal()
+	defer stop()
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("can not send SIGHUP:", err)
+	}
+
+	deadline := time.Now().Add(OneSecond)
+	for {
+		c.configMu.Lock()
+		n := len(c.entries)
+		_, hasA := c.entries["a"]
+		c.configMu.Unlock()
+		if n == 3 && hasA {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected SIGHUP to reload all sources")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadConfigStore(t *testing.T) {
+	dir := t.TempDir()
+	store := FileStore(filepath.Join(dir, "cron.json"))
+	save := func(specs map[string]string) {
+		s := &Snapshot{Version: snapshotVersion}
+		for id, spec := range specs {
+			s.Entries = append(s.Entries, EntryState{ID: id, Spec: spec, Type: "test", Params: map[string]string{"name": id}})
+		}
+		if err := store.Save(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(map[string]string{"changed": "@hourly", "dropped": "@hourly"})
+	file := filepath.Join(dir, "jobs.json")
+	if err := ioutil.WriteFile(file, []byte(`{"jobs": [{"name": "a", "spec": "@hourly", "type": "test"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(WithStore(store))
+	c.Start()
+	defer c.Stop()
+	w, err := c.WatchConfigFile(file, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	c.AddJob("@hourly", &testDescribedJob{"mine", "mine"})
+	specs := func() map[string]string {
+		specs := make(map[string]string)
+		for _, e := range c.Entries() {
+			specs[e.ID] = e.Spec
+		}
+		return specs
+	}
+
+	// An invalid config source: the store is not reloaded either.
+	save(map[string]string{"changed": "@daily", "added": "@hourly"})
+	ioutil.WriteFile(file, []byte(`{"jobs": [{"name": "a", "spec": "bogus", "type": "test"}]}`), 0644)
+	if err := c.ReloadConfig(); err == nil {
+		t.Fatal("expected an error for the invalid job")
+	}
+	if got := specs(); len(got) != 4 || got["changed"] != "@hourly" {
+		t.Fatalf("expected the entries to be unchanged, got %v", got)
+	}
+
+	ioutil.WriteFile(file, []byte(`{"jobs": [{"name": "a", "spec": "@daily", "type": "test"}]}`), 0644)
+	if err := c.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"a": "@daily", "changed": "@daily", "added": "@hourly", "mine": "@hourly"}
+	if got := specs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the store to be reloaded, got %v", got)
+	}
+}
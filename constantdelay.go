@@ -25,3 +25,28 @@ func Every(duration time.Duration) ConstantDelaySchedule {
 func (schedule ConstantDelaySchedule) Next(t time.Time) time.Time {
 	return t.Add(schedule.Delay - time.Duration(t.Nanosecond())*time.Nanosecond)
 }
+
+// AlignedDelaySchedule is a recurring duty cycle aligned on the wall clock,
+// e.g. "Every 15 minutes" activating at :00, :15, :30 and :45, rather than
+// relative to the time it is started. Delays dividing a day are aligned on
+// midnight in the location of the time passed to Next; the others on the
+// Unix epoch.
+type AlignedDelaySchedule struct {
+	Delay time.Duration
+}
+
+// EveryAligned returns a Schedule that activates once every duration,
+// aligned on the wall clock. The duration is rounded as by Every.
+func EveryAligned(duration time.Duration) AlignedDelaySchedule {
+	return AlignedDelaySchedule(Every(duration))
+}
+
+// Next returns the first aligned time after t.
+func (schedule AlignedDelaySchedule) Next(t time.Time) time.Time {
+	if (24*time.Hour)%schedule.Delay != 0 {
+		return t.Truncate(schedule.Delay).Add(schedule.Delay)
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+	return midnight.Add(since - since%schedule.Delay + schedule.Delay)
+}
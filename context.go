@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContextJob is a Job that can be cancelled. The Cron runs it with
+// RunContext instead of Run, passing a context that is done when the context
+// given to StartContext or RunContext is.
+type ContextJob interface {
+	Job
+	RunContext(ctx context.Context) (msg string, err error)
+}
+
+// runJob runs j with ctx if it is a ContextJob.
+func runJob(ctx context.Context, j Job) (string, error) {
+	if cj, ok := j.(ContextJob); ok {
+		return cj.RunContext(ctx)
+	}
+	return j.Run()
+}
+
+// StartContext starts the scheduler in its own go-routine like Start, and
+// stops it once ctx is done. The jobs still running are cancelled through
+// their context.
+func (c *Cron) StartContext(ctx context.Context) {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+	if c.running {
+		return
+	}
+	c.ctx = ctx
+	c.begin()
+	go c.stopOnDone(ctx, c.halt)
+	go c.run()
+}
+
+// RunContext runs the scheduler like Run until ctx is done or Stop is
+// called. The jobs still running are cancelled through their context.
+func (c *Cron) RunContext(ctx context.Context) {
+	c.runMu.Lock()
+	if c.running {
+		c.runMu.Unlock()
+		return
+	}
+	c.ctx = ctx
+	c.begin()
+	go c.stopOnDone(ctx, c.halt)
+	c.runMu.Unlock()
+	c.run()
+}
+
+// stopOnDone stops the scheduler when ctx is done, unless it is stopped
+// first and halt is closed.
+func (c *Cron) stopOnDone(ctx context.Context, halt <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		c.Stop()
+	case <-halt:
+	}
+}
+
+type scheduledKey struct{}
+
+type clockKey struct{}
+
+type cronKey struct{}
+
+// RunClock returns the clock of the Cron running the run given ctx, see
+// WithClock, for the jobs to time their timeouts with. Outside of a run, it
+// returns a RealClock.
+func RunClock(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return RealClock{}
+}
+
+// UpstreamOutput returns the message of the last successful run of the job
+// with the given id in the Cron running the run given ctx, like the output
+// function of the parameter templates, so that a ContextJob can process what
+// another job produced. It fails outside of a run, or if the job has not
+// succeeded yet.
+func UpstreamOutput(ctx context.Context, id string) (string, error) {
+	c, ok := ctx.Value(cronKey{}).(*Cron)
+	if !ok {
+		return "", fmt.Errorf("No output of job %s outside of a run", id)
+	}
+	return c.lastOutput(id)
+}
+
+// ScheduledTime returns the time the run given ctx was due at, which differs
+// from the current time for late runs and for the runs of Backfill.
+func ScheduledTime(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(scheduledKey{}).(time.Time)
+	return t, ok
+}
@@ -0,0 +1,180 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingJob runs until its context is done.
+type blockingJob struct {
+	started chan struct{}
+}
+
+func (j blockingJob) ID() string { return "blocking" }
+
+func (j blockingJob) Run() (string, error) {
+	return j.RunContext(context.Background())
+}
+
+func (j blockingJob) RunContext(ctx context.Context) (string, error) {
+	j.started <- struct{}{}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestStartContext(t *testing.T) {
+	c := New()
+	results := make(chan *JobResult, 1)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	job := blockingJob{make(chan struct{}, 1)}
+	c.Schedule(ConstantDelaySchedule{time.Second}, job)
+	events, cancelEvents := c.SubscribeEvents(10)
+	defer cancelEvents()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.StartContext(ctx)
+	select {
+	case <-job.started:
+	case <-time.After(2 * OneSecond):
+		t.Fatal("expected the job to start")
+	}
+	cancel()
+
+	select {
+	case r := <-results:
+		if r.Error != context.Canceled {
+			t.Errorf("expected the job to be cancelled, got %v", r.Error)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to return once cancelled")
+	}
+	for {
+		select {
+		case e := <-events:
+			if e.Type == EventSchedulerStopped {
+				return
+			}
+		case <-time.After(OneSecond):
+			t.Fatal("expected the scheduler to stop")
+		}
+	}
+}
+
+func TestRestartContext(t *testing.T) {
+	c := New()
+	results := make(chan *JobResult, 2)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	job := blockingJob{make(chan struct{}, 2)}
+	c.AddJob("@yearly", job, WithRetries(1, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.StartContext(ctx)
+	c.RunNow("blocking")
+	<-job.started
+	// The run keeps the context it was dispatched with, and is not retried
+	// once it is cancelled.
+	c.Stop()
+	c.Start()
+	defer c.Stop()
+	cancel()
+	select {
+	case r := <-results:
+		if r.Error != context.Canceled || r.Attempt != 1 {
+			t.Errorf("expected the run to be cancelled, got %+v", r)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to return once cancelled")
+	}
+	select {
+	case <-job.started:
+		t.Error("expected the cancelled run not to be retried")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.RunContext(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(OneSecond):
+		t.Fatal("expected RunContext to return once the context is done")
+	}
+}
+
+// clockJob records the clock of its runs.
+type clockJob struct {
+	clock Clock
+}
+
+func (j *clockJob) ID() string { return "clock" }
+
+func (j *clockJob) Run() (string, error) {
+	return j.RunContext(context.Background())
+}
+
+func (j *clockJob) RunContext(ctx context.Context) (string, error) {
+	j.clock = RunClock(ctx)
+	return "", nil
+}
+
+// upstreamJob returns the output of the job it reads.
+type upstreamJob struct {
+	upstream string
+}
+
+func (j *upstreamJob) ID() string { return "downstream" }
+
+func (j *upstreamJob) Run() (string, error) {
+	return j.RunContext(context.Background())
+}
+
+func (j *upstreamJob) RunContext(ctx context.Context) (string, error) {
+	return UpstreamOutput(ctx, j.upstream)
+}
+
+func TestUpstreamOutput(t *testing.T) {
+	if _, err := UpstreamOutput(context.Background(), "extract"); err == nil {
+		t.Error("expected no output outside of a run")
+	}
+	c := New()
+	c.AddJob("@yearly", &testDescribedJob{"extract", "rows"})
+	c.AddJob("@yearly", &upstreamJob{"extract"})
+	run := func(id string) RunRecord {
+		c.runWithRecovery(c.entries[id].Job, nil, slots{}, nil)
+		runs := c.History(id)
+		return runs[len(runs)-1]
+	}
+
+	if r := run("downstream"); !strings.Contains(r.Error, "Job extract has no successful run") {
+		t.Errorf("expected the run to fail without input, got %+v", r)
+	}
+	run("extract")
+	if r := run("downstream"); r.Msg != "rows" {
+		t.Errorf("expected the output of extract, got %+v", r)
+	}
+}
+
+func TestRunClock(t *testing.T) {
+	if _, ok := RunClock(context.Background()).(RealClock); !ok {
+		t.Error("expected the system clock outside of a run")
+	}
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	job := &clockJob{}
+	c.AddJob("@hourly", job)
+	c.Start()
+	defer c.Stop()
+	clock.Advance(time.Hour)
+	if job.clock != Clock(clock) {
+		t.Errorf("expected the clock of the Cron, got %v", job.clock)
+	}
+}
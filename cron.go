@@ -1,9 +1,13 @@
 package cron
 
 import (
+	"container/heap"
+	"context"
+	"fmt"
 	"log"
 	"runtime"
 	"sort"
+	"sync"
 	"time"
 	"github.com/satori/go.uuid"
 )
@@ -14,14 +18,64 @@ import (
 type Cron struct {
 	entries       map[string]*Entry
 	stop          chan struct{}
-	add           chan *Entry
 	resultHandler func(r *JobResult)
-	remove        chan string
-	sortedEntries []*Entry
-	snapshot      chan []*Entry
+	handlerMu     sync.Mutex
+	queue         entryHeap
+	retries       []*pendingRetry
+	ops           chan func()
 	running       bool
+	// runMu guards running: it is held for writing while the scheduler
+	// starts or stops, and for reading while the entries are accessed.
+	runMu sync.RWMutex
+	// idleMu serializes access to the entries while not running.
+	idleMu sync.Mutex
 	ErrorLog      *log.Logger
 	location      *time.Location
+	locationMu    sync.RWMutex
+	clock         Clock
+	dryRun        bool
+	observer      bool
+	observeEvery  time.Duration
+	parser        ScheduleParser
+	wrappers      []JobWrapper
+	maxConcurrent int
+	sem           *fairQueue
+	weights       map[string]int
+	groups        []tagGroup
+	resources     resourceLocks
+	store         Store
+	ctx           context.Context
+	halt          chan struct{}
+	active        activeJobs
+	maxEntries    int
+	disableAfter  int
+	quarantine    bool
+	pool          *workerPool
+	inLoop        bool
+	batch         *batchDispatcher
+	jumpThreshold time.Duration
+	alignEvery    bool
+	chained       int32
+	drainTimeout  time.Duration
+	chaos         *chaos
+	results       *resultDispatcher
+	resultLimit   resultLimit
+	loggerMu      sync.RWMutex
+	loggers       map[string]entryLogs
+	loop          loopStats
+	changes       changeLog
+	upcoming      upcomingFeed
+	windows       []*window
+	configMu      sync.Mutex
+	configured    map[string]map[string]JobConfig
+	watchers      map[*ConfigWatcher]struct{}
+	history       *runHistory
+	accounting    bool
+	secrets       SecretProvider
+	slaHandler    func(SLABreach)
+	anomalyFactor float64
+	subscribers   resultSubscribers
+	events        eventSubscribers
 }
 
 type JobResult struct {
@@ -29,6 +83,20 @@ type JobResult struct {
 	Ref   Job
 	Msg   string
 	Error error
+	Usage Usage
+	// Wait is how long the run waited to be started once dispatched.
+	Wait time.Duration
+
+	// Attempt is the number of the attempt the result is of, from 1, out
+	// of the MaxAttempts the run is given by WithRetries.
+	Attempt     int
+	MaxAttempts int
+	// Retry is true for the attempts after the first one.
+	Retry bool
+
+	// IdempotencyKey identifies the run among the results of every run, see
+	// IdempotencyKey.
+	IdempotencyKey string
 }
 
 // Job is an interface for submitted cron jobs.
@@ -60,8 +128,135 @@ type Entry struct {
 
 	// The Job to run.
 	Job Job
+
+	// The spec the entry was added with. This is empty if the entry was added
+	// with a Schedule directly.
+	Spec string
+
+	// An optional human readable name for the entry.
+	Name string
+
+	// The tags of the entry, which maintenance windows may select it by.
+	Tags []string
+
+	// The resources the job holds while it runs, sorted by name.
+	Resources []string
+
+	// The namespace of the entry, sharing the slots of WithMaxConcurrent
+	// fairly with the other namespaces.
+	Namespace string
+
+	// The priority of the runs of the entry waiting for a slot of
+	// WithMaxConcurrent, 0 by default.
+	Priority int
+
+	// Preemptible entries have their runs cancelled to make room for the
+	// runs of a higher priority.
+	Preemptible bool
+
+	// Synchronous entries are run in the run loop rather than in the
+	// background.
+	Synchronous bool
+
+	// The logger of the scheduler messages about the entry, and the prefix
+	// of these messages, see WithEntryLogger and WithLogPrefix.
+	Logger    *log.Logger
+	LogPrefix string
+
+	// The logger of the messages reporting failures of the entry, and the
+	// handler of the panics of its job, see WithEntryErrorLog and
+	// WithPanicHandler.
+	ErrorLog     *log.Logger
+	PanicHandler func(p Panic) error
+
+	// The number of times a failed run is attempted again, and how long
+	// after the failure, see WithRetries.
+	Retries    int
+	RetryDelay time.Duration
+
+	// The time zone the schedule is evaluated in, or nil for the one of
+	// the Cron.
+	Location *time.Location
+
+	// Paused entries keep being scheduled, but their job is not run.
+	Paused bool
+
+	// How long after being due each run should be done, or zero if the
+	// entry has no SLA.
+	SLA time.Duration
+
+	// What to do with the runs missed while the system was suspended or the
+	// clock jumped forward. The zero value is MisfireRunOnce.
+	Misfire MisfirePolicy
+
+	// The number of times the scheduler ran the job.
+	Runs int
+
+	// The number of runs after which the entry is removed. Zero means no
+	// limit.
+	MaxRuns int
+
+	// The time the entry is removed at, whatever its schedule. This is the
+	// zero time if it does not expire.
+	Expires time.Time
+
+	// The state of the circuit breaker of the entry when it was copied by
+	// Entries or Entry. It is empty if the entry has no breaker.
+	Breaker BreakerState
+
+	// Disabled entries failed too many times in a row, and are not run
+	// until they are enabled again. It is set on the copies returned by
+	// Entries and Entry.
+	Disabled bool
+
+	// The version of the definition of the entry, 1 when added and
+	// incremented whenever its job is replaced.
+	Version int
+
+	// The revision of the change the entry was added or last replaced at,
+	// see Watch. Unlike its version, it never comes back after a rollback,
+	// so that updates can expect it, see JobDefinition.
+	Revision uint64
+
+	// previous is the definition the entry replaced, for RollbackJob.
+	previous *Entry
+
+	// restored tells whether the entry was restored from the store, so
+	// that a config source defining its job may take it over.
+	restored bool
+
+	// ttl is how long after being added the entry expires.
+	ttl time.Duration
+
+	// triggers are the pending runs of an entry with an AfterSchedule, in
+	// increasing order.
+	triggers []time.Time
+
+	health *entryHealth
+
+	// The id of the job the entry was added with. It is set on the copies
+	// returned by Entries and Entry.
+	ID string
+
+	// The status of the entry when it was copied by Entries or Entry.
+	Status EntryStatus
+
+	// index is the position of the entry in the queue of the run loop.
+	index int
 }
 
+// EntryStatus describes whether an entry is waiting, paused or running.
+type EntryStatus string
+
+const (
+	EntryScheduled EntryStatus = "scheduled"
+	EntryPaused    EntryStatus = "paused"
+	// EntryRunning entries have a run of their job in progress.
+	EntryRunning EntryStatus = "running"
+	// EntryDisabled entries were disabled for failing too often.
+	EntryDisabled EntryStatus = "disabled"
+)
+
 // byTime is a wrapper for sorting the entry array by time
 // (with zero time at the end).
 type byTime []*Entry
@@ -81,24 +276,76 @@ func (s byTime) Less(i, j int) bool {
 	return s[i].Next.Before(s[j].Next)
 }
 
-// New returns a new Cron job runner, in the Local time zone.
-func New() *Cron {
-	return NewWithLocation(time.Now().Location())
+// entryHeap is a priority queue of entries ordered by their next run time,
+// implementing heap.Interface. The run loop keeps its entries in it, so
+// finding and rescheduling the next entry is O(log n). Entries with a zero
+// next time will not run again; they come first, to be removed right away.
+type entryHeap []*Entry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool {
+	a, b := h[i].due(), h[j].due()
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && !b.IsZero()
+	}
+	return a.Before(b)
 }
 
-// NewWithLocation returns a new Cron job runner.
-func NewWithLocation(location *time.Location) *Cron {
-	return &Cron{
+func (h entryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entryHeap) Push(x interface{}) {
+	e := x.(*Entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+// New returns a new Cron job runner, in the Local time zone, configured by
+// the given options.
+func New(opts ...Option) *Cron {
+	c := &Cron{
 		entries:       make(map[string]*Entry),
-		add:           make(chan *Entry),
-		remove:        make(chan string),
 		stop:          make(chan struct{}),
-		sortedEntries: make([]*Entry, 0),
-		snapshot:      make(chan []*Entry),
+		ops:           make(chan func()),
 		running:       false,
 		ErrorLog:      nil,
-		location:      location,
+		location:      time.Local,
+		clock:         RealClock{},
+		parser:        DefaultSpecCache,
+		ctx:           context.Background(),
+		configured:    make(map[string]map[string]JobConfig),
+		watchers:      make(map[*ConfigWatcher]struct{}),
+		history:       newRunHistory(DefaultHistorySize),
+		jumpThreshold: DefaultJumpThreshold,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.maxConcurrent > 0 {
+		c.sem = newFairQueue(c.maxConcurrent, c.weights)
+		c.sem.preempted = c.preempted
+	}
+	c.setupResults()
+	return c
+}
+
+// NewWithLocation returns a new Cron job runner.
+//
+// Deprecated: Use New(WithLocation(location)).
+func NewWithLocation(location *time.Location) *Cron {
+	return New(WithLocation(location))
 }
 
 // A wrapper that turns a func() into a cron.Job
@@ -106,161 +353,609 @@ type FuncJob func() (msg string, err error)
 
 func (f FuncJob) Run() (msg string, err error) { return f() }
 
+// ID returns a new random id on every call. A FuncJob added to a Cron is
+// given an id once, which its entry and results use.
 func (f FuncJob) ID() string { return uuid.Must(uuid.NewV4(), nil).String() }
 
-// AddFunc adds a func to the Cron to be run on the given schedule.
-func (c *Cron) AddFunc(spec string, cmd func() (msg string, err error)) error {
-	return c.AddJob(spec, FuncJob(cmd))
+// funcJob is a FuncJob with the id it was given when added.
+type funcJob struct {
+	id string
+	f  FuncJob
+}
+
+func newFuncJob(f FuncJob) *funcJob {
+	return &funcJob{id: f.ID(), f: f}
+}
+
+func (j *funcJob) ID() string { return j.id }
+
+func (j *funcJob) Run() (msg string, err error) { return j.f() }
+
+// AddFunc adds a func to the Cron to be run on the given schedule. It returns
+// the id assigned to the func, under which its entry and results are known.
+func (c *Cron) AddFunc(spec string, cmd func() (msg string, err error), opts ...EntryOption) (id string, err error) {
+	job := newFuncJob(cmd)
+	if err := c.AddJob(spec, job, opts...); err != nil {
+		return "", err
+	}
+	return job.id, nil
 }
 
 // AddJob adds a Job to the Cron to be run on the given schedule.
-func (c *Cron) AddJob(spec string, cmd Job) error {
-	schedule, err := Parse(spec)
+func (c *Cron) AddJob(spec string, cmd Job, opts ...EntryOption) error {
+	schedule, err := c.parser.Parse(spec)
 	if err != nil {
 		return err
 	}
-	c.Schedule(schedule, cmd)
-	return nil
+	return c.schedule(spec, schedule, cmd, opts)
 }
 
+// RemoveJob removes the entry of the job with the given id, if any.
 func (c *Cron) RemoveJob(jobId string) {
-	c.remove <- jobId
+	c.Remove(jobId)
+}
+
+// Remove removes the entry of the job with the given id, like RemoveJob, or
+// fails with ErrJobNotFound if there is none.
+func (c *Cron) Remove(id string) (err error) {
+	c.do(func() {
+		if !c.unlink(id) {
+			err = jobNotFound(id)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	c.history.forget(id)
+	c.emit(Event{Type: EventEntryRemoved, EntryID: id})
+	return nil
+}
+
+// unlink removes the entry with the given id, reporting whether there was
+// one. It must be called through do.
+func (c *Cron) unlink(id string) bool {
+	old, ok := c.entries[id]
+	if !ok {
+		return false
+	}
+	if c.running {
+		heap.Remove(&c.queue, old.index)
+	}
+	delete(c.entries, id)
+	c.setLoggers(id, nil)
+	c.changed(ChangeRemoved, id, nil)
+	return true
+}
+
+// Schedule adds a Job to the Cron to be run on the given schedule. It only
+// fails with ErrQuotaExceeded.
+func (c *Cron) Schedule(schedule Schedule, cmd Job, opts ...EntryOption) error {
+	return c.schedule("", schedule, cmd, opts)
 }
 
-// Schedule adds a Job to the Cron to be run on the given schedule.
-func (c *Cron) Schedule(schedule Schedule, cmd Job) {
+func (c *Cron) schedule(spec string, schedule Schedule, cmd Job, opts []EntryOption) error {
 	entry := &Entry{
 		Schedule: schedule,
 		Job:      cmd,
+		Spec:     spec,
 	}
-	if !c.running {
-		c.entries[cmd.ID()] = entry
-		return
+	for _, opt := range opts {
+		opt(entry)
 	}
+	return c.addEntry(entry, true)
+}
 
-	c.add <- entry
+// addEntry adds a fully built entry. The entry of the same job is replaced
+// if replace is set; otherwise adding it fails with ErrDuplicateJob.
+func (c *Cron) addEntry(entry *Entry, replace bool) (err error) {
+	if f, ok := entry.Job.(FuncJob); ok {
+		entry.Job = newFuncJob(f)
+	}
+	id := entry.Job.ID()
+	entry.locate()
+	if entry.ttl > 0 {
+		entry.Expires = c.now().Add(entry.ttl)
+	}
+	c.do(func() { err = c.insert(id, entry, replace) })
+	if err == nil {
+		c.emit(Event{Type: EventEntryAdded, EntryID: id})
+	}
+	return err
 }
 
+// insert adds the entry under the given id, scheduling it if the scheduler
+// is running. It must be called through do.
+func (c *Cron) insert(id string, entry *Entry, replace bool) error {
+	old, exists := c.entries[id]
+	if exists && !replace {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, id)
+	} else if !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		return ErrQuotaExceeded
+	}
+	if c.alignEvery {
+		entry.Schedule = aligned(entry.Schedule)
+	}
+	c.noteChained(entry)
+	c.setLoggers(id, entry)
+	switch {
+	case entry.Version > 0:
+		// Rolled back to.
+	case exists:
+		entry.Version = old.Version + 1
+		entry.previous, old.previous = old, nil
+	default:
+		entry.Version = 1
+	}
+	change := ChangeAdded
+	if exists {
+		change = ChangeUpdated
+	}
+	defer c.changed(change, id, entry)
+	if !c.running {
+		c.entries[id] = entry
+		return nil
+	}
+	entry.Next = nextRun(entry, c.now())
+	if exists {
+		heap.Remove(&c.queue, old.index)
+	}
+	c.entries[id] = entry
+	heap.Push(&c.queue, entry)
+	return nil
+}
+
+// AddResultHandler sets the func called with the result of every run, in
+// its own goroutine. It may be called while the scheduler is running. A nil
+// handler discards the results, which is the default.
 func (c *Cron) AddResultHandler(Handler func(j *JobResult)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
 	c.resultHandler = Handler
 }
 
-// Entries returns a snapshot of the cron entries.
-func (c *Cron) Entries() []*Entry {
-	if c.running {
-		c.snapshot <- nil
-		x := <-c.snapshot
-		return x
+// handler returns the result handler, or nil if none is set.
+func (c *Cron) handler() func(r *JobResult) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	return c.resultHandler
+}
+
+// Entries returns a snapshot of the cron entries, ordered by their next run
+// time. The entries are copies, which the scheduler does not change.
+func (c *Cron) Entries() (entries []*Entry) {
+	c.do(func() { entries = c.entrySnapshot() })
+	return entries
+}
+
+// Entry returns a snapshot of the entry of the job with the given id.
+func (c *Cron) Entry(id string) (entry *Entry, ok bool) {
+	c.do(func() {
+		if e, found := c.entries[id]; found {
+			entry, ok = c.copyEntry(id, e), true
+		}
+	})
+	return entry, ok
+}
+
+// NextRun returns the next time the job with the given id runs. The run
+// times are only known while the scheduler is running; ErrSchedulerStopped
+// is returned otherwise.
+func (c *Cron) NextRun(id string) (next time.Time, err error) {
+	c.do(func() {
+		e, ok := c.entries[id]
+		switch {
+		case !ok:
+			err = jobNotFound(id)
+		case !c.running:
+			err = ErrSchedulerStopped
+		default:
+			next = e.Next
+		}
+	})
+	return next, err
+}
+
+// Pause stops the job with the given id from running until it is resumed.
+// The entry stays scheduled.
+func (c *Cron) Pause(id string) error {
+	return c.setPaused(id, true)
+}
+
+// Resume lets a paused job run again on its schedule.
+func (c *Cron) Resume(id string) error {
+	return c.setPaused(id, false)
+}
+
+func (c *Cron) setPaused(id string, paused bool) (err error) {
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		if e.Paused != paused {
+			e.Paused = paused
+			c.changed(ChangeStatus, id, e)
+		}
+	})
+	if err == nil {
+		typ := EventEntryResumed
+		if paused {
+			typ = EventEntryPaused
+		}
+		c.emit(Event{Type: typ, EntryID: id})
+	}
+	return err
+}
+
+// RunNow runs the job with the given id right away, outside of its schedule
+// and whether it is paused or not.
+func (c *Cron) RunNow(id string) (err error) {
+	var job Job
+	var health *entryHealth
+	var s slots
+	var d *TemplateData
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		job, health, s = e.Job, healthOf(e), e.slots(c.ctx)
+		d = templateData(e, c.now())
+	})
+	switch {
+	case err != nil:
+	case c.observer:
+		err = ErrObserver
+	case c.dryRun:
+		c.skipDryRun(job, c.now())
+	default:
+		c.dispatch(job, health, s, d)
 	}
-	return c.entrySnapshot()
+	return err
+}
+
+// do runs f in the run loop if the scheduler is running, or right away
+// otherwise, so f may access the entries safely. The scheduler does not
+// start or stop while f runs.
+func (c *Cron) do(f func()) {
+	c.runMu.RLock()
+	defer c.runMu.RUnlock()
+	if !c.running {
+		c.idleMu.Lock()
+		defer c.idleMu.Unlock()
+		f()
+		return
+	}
+	done := make(chan struct{})
+	c.ops <- func() {
+		f()
+		close(done)
+	}
+	<-done
 }
 
 // Location gets the time zone location
 func (c *Cron) Location() *time.Location {
+	c.locationMu.RLock()
+	defer c.locationMu.RUnlock()
 	return c.location
 }
 
 // Start the cron scheduler in its own go-routine, or no-op if already started.
 func (c *Cron) Start() {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
 	if c.running {
 		return
 	}
-	c.running = true
+	c.ctx = context.Background()
+	c.begin()
 	go c.run()
 }
 
 // Run the cron scheduler, or no-op if already running.
 func (c *Cron) Run() {
+	c.runMu.Lock()
 	if c.running {
+		c.runMu.Unlock()
 		return
 	}
-	c.running = true
+	c.ctx = context.Background()
+	c.begin()
+	c.runMu.Unlock()
 	c.run()
 }
 
-func (c *Cron) runWithRecovery(j Job) {
+// begin restores the entries saved in the store, if any, and marks the
+// scheduler as running. runMu must be held for writing.
+func (c *Cron) begin() {
+	c.loadStore()
+	if c.pool != nil {
+		c.pool.start()
+	}
+	if c.batch != nil {
+		c.batch.start()
+	}
+	c.halt = make(chan struct{})
+	if c.observer {
+		go c.observe(c.halt)
+	}
+	c.running = true
+	c.loop.start(c.now())
+	c.emit(Event{Type: EventSchedulerStarted})
+	if fc, ok := c.clock.(*FakeClock); ok {
+		fc.startScheduler()
+	}
+}
+
+func (c *Cron) runWithRecovery(j Job, h *entryHealth, s slots, d *TemplateData) error {
+	return c.runQueued(j, h, s, d, time.Time{})
+}
+
+// runAttempt runs the given attempt of a run of j, queued at the given time
+// or now if it is zero, in the run loop or not.
+func (c *Cron) runAttempt(j Job, h *entryHealth, s slots, d *TemplateData, queuedAt time.Time, attempt int, inLoop bool) (err error) {
+	if queuedAt.IsZero() {
+		queuedAt = c.now()
+	}
+	id := j.ID()
+	c.chaos.delay(c.clock)
+	runCtx, queued := c.runContext(id, s)
+	c.acquire(s, queued)
+	defer c.release(s, queued)
+	c.active.add(id, 1)
+	defer c.active.add(id, -1)
+	meter, ctx := c.meter(runCtx)
+	var (
+		secrets []string
+		sla     *slaWatch
+	)
+	start := c.now()
+	wait := start.Sub(queuedAt)
+	c.emit(Event{Type: EventJobStarted, EntryID: id, Time: start})
 	defer func() {
 		if r := recover(); r != nil {
 			const size = 64 << 10
 			buf := make([]byte, size)
 			buf = buf[:runtime.Stack(buf, false)]
-			c.logf("cron: panic running job: %v\n%s", r, buf)
+			v, _ := maskSecrets(secrets, fmt.Sprint(r), nil)
+			err = fmt.Errorf("panic: %s", v)
+			if s.onPanic == nil {
+				c.entryErrorf(id, "cron: panic running job: %s\n%s", v, buf)
+			} else if perr := s.onPanic(Panic{id, v, buf}); perr != nil {
+				err = perr
+			}
+			end := c.now()
+			c.history.record(id, start, end, wait, "", err, meter.stop(c.accounting))
+			sla.finish(end)
+			c.recordHealth(id, h, err)
+			c.quarantinePanic(id, h, err, buf)
+			c.emit(Event{Type: EventJobFinished, EntryID: id, Error: err.Error()})
+			if attempt > s.retries {
+				c.chain(id, err, end, inLoop)
+			}
 		}
 	}()
 
-	msg, err := j.Run()
+	var key string
+	if d != nil {
+		key = d.IdempotencyKey
+		ctx = context.WithValue(ctx, scheduledKey{}, d.ScheduledTime)
+		ctx = context.WithValue(ctx, idempotencyKey{}, key)
+	}
+	ctx = context.WithValue(ctx, loggerKey{}, c.runLogger(id))
+	ctx = context.WithValue(ctx, clockKey{}, c.clock)
+	ctx = context.WithValue(ctx, cronKey{}, c)
+	sla = c.watchSLA(id, d)
+	var run Job
+	run, secrets, err = c.prepare(ctx, j, d)
+	var msg string
+	if err == nil {
+		err = c.chaos.failure(id)
+	}
+	if err == nil {
+		msg, err = runJob(ctx, c.wrap(run))
+	}
+	if err != nil && queued.isPreempted() {
+		err = fmt.Errorf("%w: %s", ErrPreempted, err)
+	}
+	msg, err = maskSecrets(secrets, msg, err)
+	usage := meter.stop(c.accounting)
+	end := c.now()
+	c.checkDuration(id, end.Sub(start))
+	c.history.record(id, start, end, wait, msg, err, usage)
+	sla.finish(end)
+	c.recordHealth(id, h, err)
+	finished := Event{Type: EventJobFinished, EntryID: id, Msg: msg}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	c.emit(finished)
+	if err == nil || attempt > s.retries {
+		c.chain(id, err, end, inLoop)
+	}
 
+	// Only allocate the result if someone receives it.
+	handler := c.chaos.handler(c.handler())
+	if handler == nil && !c.subscribers.any() {
+		return err
+	}
 	js := &JobResult{
-		JobId: j.ID(),
+		JobId: id,
 		Ref:   j,
 		Msg:   msg,
 		Error: err,
+		Usage: usage,
+		Wait:  wait,
+
+		Attempt:     attempt,
+		MaxAttempts: s.retries + 1,
+		Retry:       attempt > 1,
+
+		IdempotencyKey: key,
+	}
+	c.subscribers.publish(js)
+	switch {
+	case handler == nil:
+	case c.synchronous():
+		c.handle(handler, js)
+	default:
+		c.deliver(handler, js)
+	}
+	return err
+}
+
+// dispatch runs j in the background. It returns false if the run was
+// skipped, the dispatch or worker queue being full.
+func (c *Cron) dispatch(j Job, h *entryHealth, s slots, d *TemplateData) bool {
+	if c.batch == nil {
+		if !c.pool.submit(func() { c.runWithRecovery(j, h, s, d) }) {
+			c.skipRun(j, c.now(), "Worker queue full")
+			return false
+		}
+		return true
 	}
-	go c.resultHandler(js)
+	queuedAt := c.now()
+	if !c.batch.submit(func() { c.runQueued(j, h, s, d, queuedAt) }) {
+		c.skipRun(j, queuedAt, "Dispatch queue full")
+		return false
+	}
+	return true
 }
 
 // Run the scheduler. this is private just due to the need to synchronize
 // access to the 'running' state variable.
 func (c *Cron) run() {
+	if fc, ok := c.clock.(*FakeClock); ok {
+		defer fc.stopScheduler()
+	}
+
 	// Figure out the next activation times for each entry.
 	now := c.now()
+	c.queue = make(entryHeap, 0, len(c.entries))
 	for _, entry := range c.entries {
-		entry.Next = entry.Schedule.Next(now)
+		entry.Next = nextRun(entry, now)
+		c.queue = append(c.queue, entry)
 	}
+	for i, entry := range c.queue {
+		entry.index = i
+	}
+	c.requeueAll()
+	c.retries = nil
 
+	// The timer is reset to the next entry to run after every event, and
+	// the next runs computed meanwhile are announced. wake is when it is
+	// expected to fire.
+	c.announce()
+	wake := now.Add(c.untilNext(now))
+	timer := c.newTimer(wake.Sub(now))
 	for {
-
-		c.sortedEntries = mapToArray(c.entries)
-		// Determine the next entry to run.
-		sort.Sort(byTime(c.sortedEntries))
-
-		var timer *time.Timer
-		if len(c.sortedEntries) == 0 || c.sortedEntries[0].Next.IsZero() {
-			// If there are no entries yet, just sleep - it still handles new entries
-			// and stop requests.
-			timer = time.NewTimer(100000 * time.Hour)
-		} else {
-			timer = time.NewTimer(c.sortedEntries[0].Next.Sub(now))
-		}
-
-		for {
-			select {
-			case now = <-timer.C:
-				now = now.In(c.location)
-				// Run every entry whose next time was less than now
-				for _, e := range c.sortedEntries {
-					if e.Next.After(now) || e.Next.IsZero() {
-						break
-					}
-					go c.runWithRecovery(e.Job)
-					e.Prev = e.Next
-					e.Next = e.Schedule.Next(now)
+		select {
+		case now = <-timer.C():
+			now = now.In(c.Location())
+			c.loop.wakeup(wake, now)
+			jump := c.clockJump(wake, now)
+			// Run every entry whose next time was less than now
+			for len(c.queue) > 0 {
+				e := c.queue[0]
+				if e.due().After(now) {
+					break
 				}
+				if e.Next.IsZero() || e.expired(now) {
+					c.complete(e)
+					continue
+				}
+				w, end := c.blackout(e, e.Next)
+				switch {
+				case e.Paused:
+				case w != nil && w.Policy == WindowDefer:
+					// Due again when the window ends.
+					e.Next = end
+					c.requeue(e)
+					continue
+				case w != nil:
+					c.skipRun(e.Job, e.Next, fmt.Sprintf("Maintenance window %s", w.Name))
+				case jump > 0 && now.Sub(e.Next) > c.jumpThreshold:
+					c.misfire(e, now)
+				default:
+					c.fire(e, e.Next)
+				}
+				e.Next = nextRun(e, now)
+				c.requeue(e)
+			}
+			c.retryDue(now)
+			if jump < 0 {
+				c.reschedule(now)
+			}
 
-			case newEntry := <-c.add:
-				timer.Stop()
-				now = c.now()
-				newEntry.Next = newEntry.Schedule.Next(now)
-				c.entries[newEntry.Job.ID()] = newEntry
-
-			case id := <-c.remove:
-				timer.Stop()
-				now = c.now()
-				delete(c.entries, id)
-
-			case <-c.snapshot:
-				c.snapshot <- c.entrySnapshot()
-				continue
+		case op := <-c.ops:
+			if !timer.Stop() {
+				select {
+				case <-timer.C():
+				default:
+				}
+			}
+			op()
+			now = c.now()
 
+		case <-c.stop:
+			timer.Stop()
+			return
+		}
+		c.announce()
+		wake = now.Add(c.untilNext(now))
+		timer.Reset(wake.Sub(now))
+	}
+}
 
-			case <-c.stop:
-				timer.Stop()
-				return
-			}
+// fire runs the job of e for its run due at t.
+func (c *Cron) fire(e *Entry, t time.Time) {
+	h := healthOf(e)
+	if ok, reason := h.allow(t); !ok {
+		if reason != "" {
+			c.skipRun(e.Job, t, reason)
+		}
+		return
+	}
+	e.Prev = t
+	e.Runs++
+	switch {
+	case c.observer:
+		// The scheduler the entries are mirrored from runs the job.
+		h.skipped()
+	case c.dryRun:
+		h.skipped()
+		c.skipDryRun(e.Job, t)
+	case c.synchronous() || e.Synchronous:
+		c.runInLoop(e.Job, h, e.slots(c.ctx), templateData(e, t))
+	default:
+		if !c.dispatch(e.Job, h, e.slots(c.ctx), templateData(e, t)) {
+			h.skipped()
+		}
+	}
+}
 
-			break
+// untilNext returns the time from now until the next entry or retry to run.
+func (c *Cron) untilNext(now time.Time) time.Duration {
+	if len(c.queue) == 0 && len(c.retries) == 0 {
+		// If there are no entries yet, just sleep - it still handles new entries
+		// and stop requests.
+		return 100000 * time.Hour
+	}
+	var due time.Time
+	if len(c.queue) > 0 {
+		due = c.queue[0].due()
+		if due.IsZero() {
+			// The entry will not run again and is removed right away.
+			return 0
 		}
 	}
+	if len(c.retries) > 0 && (due.IsZero() || c.retries[0].due.Before(due)) {
+		due = c.retries[0].due
+	}
+	return due.Sub(now)
 }
 
 // Logs an error to stderr or to the configured error log
@@ -274,27 +969,106 @@ func (c *Cron) logf(format string, args ...interface{}) {
 
 // Stop stops the cron scheduler if it is running; otherwise it does nothing.
 func (c *Cron) Stop() {
+	c.runMu.Lock()
 	if !c.running {
+		c.runMu.Unlock()
 		return
 	}
 	c.stop <- struct{}{}
+	if c.pool != nil {
+		c.pool.stop()
+	}
+	if c.batch != nil {
+		c.batch.stop()
+	}
+	close(c.halt)
 	c.running = false
+	c.runMu.Unlock()
+	c.emit(Event{Type: EventSchedulerStopped})
+	if !c.observer {
+		c.saveStore()
+	}
 }
 
 // entrySnapshot returns a copy of the current cron entry list.
 func (c *Cron) entrySnapshot() []*Entry {
-	return c.sortedEntries
+	entries := make([]*Entry, 0, len(c.entries))
+	for id, e := range c.entries {
+		entries = append(entries, c.copyEntry(id, e))
+	}
+	sort.Sort(byTime(entries))
+	return entries
 }
 
-// now returns current time in c location
-func (c *Cron) now() time.Time {
-	return time.Now().In(c.location)
+// copyEntry returns a copy of the entry with the given id, with its ID and
+// Status set.
+func (c *Cron) copyEntry(id string, e *Entry) *Entry {
+	cp := *e
+	cp.ID = id
+	cp.Tags = append([]string(nil), e.Tags...)
+	cp.Resources = append([]string(nil), e.Resources...)
+	e.health.copyTo(&cp)
+	switch {
+	case cp.Disabled:
+		cp.Status = EntryDisabled
+	case e.Paused:
+		cp.Status = EntryPaused
+	case c.active.running(id):
+		cp.Status = EntryRunning
+	default:
+		cp.Status = EntryScheduled
+	}
+	return &cp
 }
 
-func mapToArray(entries map[string]*Entry) []*Entry {
-	es := make([]*Entry, 0)
-	for _, e := range entries {
-		es = append(es, e)
+// activeJobs counts the runs in progress of each job.
+type activeJobs struct {
+	mu    sync.Mutex
+	runs  map[string]int
+	total int
+	// idle is closed once no job runs, for the callers of wait.
+	idle chan struct{}
+}
+
+func (a *activeJobs) add(id string, delta int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.runs == nil {
+		a.runs = make(map[string]int)
+	}
+	a.runs[id] += delta
+	if a.runs[id] <= 0 {
+		delete(a.runs, id)
 	}
-	return es
+	a.total += delta
+	if a.total <= 0 && a.idle != nil {
+		close(a.idle)
+		a.idle = nil
+	}
+}
+
+func (a *activeJobs) running(id string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.runs[id] > 0
+}
+
+// newTimer creates the timer the run loop waits on.
+func (c *Cron) newTimer(d time.Duration) Timer {
+	if fc, ok := c.clock.(*FakeClock); ok {
+		return fc.newTimer(d, true)
+	}
+	return c.clock.NewTimer(d)
+}
+
+// synchronous reports whether jobs are run in the run loop, which is the
+// case on a FakeClock and with WithSynchronousExecution.
+func (c *Cron) synchronous() bool {
+	_, ok := c.clock.(*FakeClock)
+	return ok || c.inLoop
+}
+
+// now returns current time in c location
+func (c *Cron) now() time.Time {
+	return c.clock.Now().Add(c.chaos.skew()).In(c.Location())
 }
@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"context"
 	//"fmt"
 	"sync"
 	"testing"
@@ -31,9 +32,8 @@ func (d DummyJob) ID() string {
 	return "1"
 }
 
-func (d DummyJob) Run() error {
+func (d DummyJob) Run() (msg string, err error) {
 	panic("YOLO")
-	return nil
 }
 
 //func TestJobPanicRecovery(t *testing.T) {
@@ -426,3 +426,173 @@ func stop(cron *Cron) chan bool {
 	}()
 	return ch
 }
+
+// Test that paused entries are skipped, and that they can still be run
+// manually.
+func TestPauseAndRunNow(t *testing.T) {
+	ran := make(chan struct{}, 10)
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	cron.AddJob("* * * * * ?", &testDescribedJob{"paused", "x"})
+	cron.Schedule(Every(time.Hour), testChanJob{"manual", ran})
+	if err := cron.Pause("paused"); err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	if err := cron.RunNow("manual"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected job to run now")
+	case <-ran:
+	}
+
+	<-time.After(OneSecond)
+	if e, _ := cron.Entry("paused"); !e.Prev.IsZero() {
+		t.Error("expected paused job not to run")
+	}
+	if runs := cron.History("manual"); len(runs) != 1 || runs[0].Msg != "manual" {
+		t.Errorf("unexpected history %+v", runs)
+	}
+	if err := cron.RunNow("missing"); err == nil {
+		t.Error("expected an error for a missing job")
+	}
+}
+
+type testChanJob struct {
+	id string
+	ch chan struct{}
+}
+
+func (j testChanJob) ID() string { return j.id }
+
+func (j testChanJob) Run() (msg string, err error) {
+	j.ch <- struct{}{}
+	return j.id, nil
+}
+
+func TestEntriesAreCopies(t *testing.T) {
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	job := blockingJob{make(chan struct{}, 1)}
+	cron.Schedule(ConstantDelaySchedule{time.Second}, job)
+	cron.AddJob("@hourly", NewShellCommandJob("paused", "true"))
+	cron.Pause("paused")
+	// Cancelling the context stops the scheduler and the blocking job.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cron.StartContext(ctx)
+
+	select {
+	case <-job.started:
+	case <-time.After(2 * OneSecond):
+		t.Fatal("expected the job to start")
+	}
+	status := make(map[string]EntryStatus)
+	for _, e := range cron.Entries() {
+		status[e.ID] = e.Status
+		e.Next = time.Time{}
+		e.Paused = true
+	}
+	if status["blocking"] != EntryRunning || status["paused"] != EntryPaused {
+		t.Errorf("unexpected statuses %v", status)
+	}
+	e, _ := cron.Entry("blocking")
+	if e.Paused || e.Next.IsZero() {
+		t.Error("expected changes to a snapshot not to affect the entry")
+	}
+}
+
+// Start, Stop and changes to the entries may be called concurrently.
+func TestConcurrentStartStop(t *testing.T) {
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			cron.Start()
+		}()
+		go func() {
+			defer wg.Done()
+			cron.Stop()
+		}()
+		go func(i int) {
+			defer wg.Done()
+			id := "job" + string(rune('a'+i))
+			cron.AddJob("@hourly", NewShellCommandJob(id, "true"))
+			cron.Entries()
+			cron.RemoveJob(id)
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * OneSecond):
+		t.Fatal("expected concurrent calls not to deadlock")
+	}
+	cron.Stop()
+	if entries := cron.Entries(); len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+// Jobs may run before a result handler is set.
+func TestNoResultHandler(t *testing.T) {
+	cron := New()
+	ran := make(chan struct{}, 2)
+	job := testChanJob{"nohandler", ran}
+	cron.runWithRecovery(job, nil, slots{}, nil)
+	cron.AddResultHandler(func(*JobResult) {})
+	cron.AddResultHandler(nil)
+	cron.runWithRecovery(job, nil, slots{}, nil)
+	if runs := cron.History("nohandler"); len(runs) != 2 || runs[1].Error != "" {
+		t.Errorf("expected 2 successful runs, got %+v", runs)
+	}
+}
+
+// Funcs keep the id they are given when added.
+func TestAddFuncID(t *testing.T) {
+	cron := New()
+	results := make(chan *JobResult, 1)
+	cron.AddResultHandler(func(r *JobResult) { results <- r })
+	id, err := cron.AddFunc("* * * * * ?", func() (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := cron.Entry(id); !ok || e.Job.ID() != id {
+		t.Fatalf("expected an entry for %s", id)
+	}
+	cron.Schedule(Every(time.Hour), FuncJob(func() (string, error) { return "", nil }))
+	for _, e := range cron.Entries() {
+		if e.Job.ID() != e.ID || e.Job.ID() != e.Job.ID() {
+			t.Errorf("expected a stable id for entry %s", e.ID)
+		}
+	}
+
+	if err := cron.RunNow(id); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-results:
+		if r.JobId != id {
+			t.Errorf("expected the result of %s, got %s", id, r.JobId)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected a result")
+	}
+
+	cron.RemoveJob(id)
+	if _, ok := cron.Entry(id); ok {
+		t.Error("expected the entry to be removed")
+	}
+}
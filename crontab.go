@@ -0,0 +1,366 @@
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CommandJobType is the job type used for commands imported from a crontab.
+// A factory must be registered under this name (see RegisterJobType) before
+// calling LoadCrontab. It receives the command in the "command" parameter and
+// each environment assignment in effect as an "env.NAME" parameter.
+const CommandJobType = "command"
+
+const (
+	commandParam = "command"
+	envParam     = "env."
+)
+
+// LoadCrontab parses classic crontab(5) content and adds an entry for each
+// line, building its job with the factory registered for CommandJobType.
+//
+// Lines are five time fields or a descriptor followed by the command.
+// Leading blanks are ignored. Environment assignments (NAME=value) apply to
+// every following entry, an empty one (NAME=) clearing the variable, and a
+// comment directly above an entry becomes its name. As in crontab(5), SHELL selects the shell running the commands,
+// MAILTO the recipients of their output, and CRON_TZ the time zone of the
+// following schedules. An unescaped "%" in a command ends it; the rest of the
+// line is sent to its standard input, with any further "%" read as a newline.
+// The time fields are parsed by the parser of the Cron, after a zero seconds
+// field. Nothing is added if any line is invalid, or if two entries have the
+// same id.
+func (c *Cron) LoadCrontab(r io.Reader) error {
+	lines, err := parseCrontab(r, c.parser)
+	if err != nil {
+		return err
+	}
+	entries := make([]*Entry, 0, len(lines))
+	for _, l := range lines {
+		schedule, err := c.parser.Parse(l.spec)
+		if err != nil {
+			return fmt.Errorf("crontab line %d: %s", l.lineNo, err)
+		}
+		job, err := newJob(CommandJobType, l.id, l.params)
+		if err != nil {
+			return fmt.Errorf("crontab line %d: %s", l.lineNo, err)
+		}
+		entries = append(entries, &Entry{
+			Schedule: schedule,
+			Job:      job,
+			Spec:     l.spec,
+			Name:     l.name,
+		})
+	}
+	for _, e := range entries {
+		if err := c.addEntry(e, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseCrontab parses crontab(5) content like LoadCrontab, returning a config
+// of CommandJobType jobs instead of adding them to a Cron. Unnamed entries
+// are given a stable name derived from their spec and command.
+func ParseCrontab(r io.Reader) (*Config, error) {
+	lines, err := parseCrontab(r, DefaultSpecCache)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	for _, l := range lines {
+		cfg.Jobs = append(cfg.Jobs, JobConfig{
+			Name:   l.id,
+			Spec:   l.spec,
+			Type:   CommandJobType,
+			Params: l.params,
+		})
+	}
+	return cfg, nil
+}
+
+// crontabLine is a parsed crontab entry line.
+type crontabLine struct {
+	lineNo   int
+	name, id string
+	spec     string
+	params   map[string]string
+}
+
+func parseCrontab(r io.Reader, parser ScheduleParser) ([]crontabLine, error) {
+	var (
+		lines  []crontabLine
+		ids    = make(map[string]int)
+		env    = make(map[string]string)
+		tz     string
+		name   string
+		lineNo int
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			name = ""
+			continue
+		case line[0] == '#':
+			name = strings.TrimSpace(line[1:])
+			continue
+		}
+
+		if key, value, ok := parseEnvAssignment(line); ok {
+			switch {
+			case key == "CRON_TZ":
+				tz = value
+			case value == "":
+				delete(env, key)
+			default:
+				env[key] = value
+			}
+			continue
+		}
+
+		spec, command, err := splitCrontabLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("crontab line %d: %s", lineNo, err)
+		}
+		if tz != "" {
+			spec = "CRON_TZ=" + tz + " " + spec
+		}
+		if _, err := parser.Parse(spec); err != nil {
+			return nil, fmt.Errorf("crontab line %d: %s", lineNo, err)
+		}
+
+		command, stdin, hasStdin := splitPercent(command)
+		params := map[string]string{commandParam: command}
+		if hasStdin {
+			params["stdin"] = stdin
+		}
+		for k, v := range env {
+			params[envParam+k] = v
+		}
+		if shell := env["SHELL"]; shell != "" {
+			params["shell"] = shell
+		}
+		if mailto := env["MAILTO"]; mailto != "" {
+			params["mailto"] = mailto
+		}
+		id := name
+		if id == "" {
+			id = crontabID(spec, command)
+		}
+		if prev, dup := ids[id]; dup {
+			return nil, fmt.Errorf("crontab line %d: Duplicate id %s, first on line %d", lineNo, id, prev)
+		}
+		ids[id] = lineNo
+		lines = append(lines, crontabLine{lineNo, name, id, spec, params})
+		name = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitCrontabLine splits an entry line into a spec understood by Parse and
+// the command. Crontab time fields have no seconds, so the spec is given a
+// leading zero seconds field.
+func splitCrontabLine(line string) (spec, command string, err error) {
+	n := 5
+	if line[0] == '@' {
+		n = 1
+	}
+	fields, rest := splitFields(line, n)
+	if len(fields) < n || rest == "" {
+		return "", "", fmt.Errorf("Expected schedule and command: %s", line)
+	}
+	if n == 1 {
+		if fields[0] == "@reboot" {
+			return "", "", fmt.Errorf("Unsupported descriptor: %s", fields[0])
+		}
+		return fields[0], rest, nil
+	}
+	if _, err := ParseStandard(strings.Join(fields, " ")); err != nil {
+		return "", "", err
+	}
+	return "0 " + strings.Join(fields, " "), rest, nil
+}
+
+// splitFields returns the first n whitespace separated fields of s and the
+// remainder with its inner spacing preserved.
+func splitFields(s string, n int) ([]string, string) {
+	var fields []string
+	for len(fields) < n {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			break
+		}
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			end = len(s)
+		}
+		fields = append(fields, s[:end])
+		s = s[end:]
+	}
+	return fields, strings.TrimSpace(s)
+}
+
+// splitPercent applies the crontab(5) meaning of "%" to a command: the first
+// unescaped "%" ends the command and the rest is its standard input, in
+// which every further unescaped "%" is a newline. "\%" is a literal "%".
+func splitPercent(s string) (command, stdin string, hasStdin bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '%':
+			b.WriteByte('%')
+			i++
+		case s[i] == '%' && !hasStdin:
+			command = b.String()
+			b.Reset()
+			hasStdin = true
+		case s[i] == '%':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	if !hasStdin {
+		return b.String(), "", false
+	}
+	return strings.TrimRightFunc(command, unicode.IsSpace), b.String(), true
+}
+
+// joinPercent is the inverse of splitPercent.
+func joinPercent(command, stdin string) string {
+	s := strings.Replace(command, "%", "\\%", -1)
+	if stdin != "" {
+		stdin = strings.Replace(stdin, "%", "\\%", -1)
+		s += " %" + strings.Replace(stdin, "\n", "%", -1)
+	}
+	return s
+}
+
+// parseEnvAssignment parses a "NAME = value" line. Values may be quoted.
+func parseEnvAssignment(line string) (key, value string, ok bool) {
+	i := strings.IndexByte(line, '=')
+	if i <= 0 {
+		return "", "", false
+	}
+	key = strings.TrimSpace(line[:i])
+	for j, r := range key {
+		if !(r == '_' || unicode.IsLetter(r) || (j > 0 && unicode.IsDigit(r))) {
+			return "", "", false
+		}
+	}
+	value = strings.TrimSpace(line[i+1:])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return key, value, true
+}
+
+// crontabID derives a stable id for an unnamed crontab entry.
+func crontabID(spec, command string) string {
+	h := fnv.New64a()
+	io.WriteString(h, spec)
+	io.WriteString(h, "\x00")
+	io.WriteString(h, command)
+	return fmt.Sprintf("crontab-%016x", h.Sum64())
+}
+
+// WriteCrontab writes the command entries of the Cron as crontab(5) content,
+// in the order of their ids. Each entry is named by a comment holding its id,
+// unless the id is the one LoadCrontab derives for it, and the environment
+// variables of the entry before are cleared if it does not set them.
+// Entries whose job is not a CommandJobType job, or whose schedule needs a
+// seconds field, can not be expressed and are written as comments.
+func (c *Cron) WriteCrontab(w io.Writer) error {
+	entries := c.Entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	bw := bufio.NewWriter(w)
+	env := make(map[string]string)
+	var tz string
+	for _, e := range entries {
+		typ, params := describe(e.Job)
+		fullSpec := entrySpec(e)
+		zone, spec := splitTimeZone(fullSpec)
+		spec, ok := crontabSpec(spec)
+		if typ != CommandJobType || !ok {
+			fmt.Fprintf(bw, "# skipped %s: not expressible in crontab\n\n", e.ID)
+			continue
+		}
+		if zone != tz {
+			tz = zone
+			fmt.Fprintf(bw, "CRON_TZ=%s\n", tz)
+		}
+
+		var stale []string
+		for name := range env {
+			if _, set := params[envParam+name]; !set {
+				stale = append(stale, name)
+			}
+		}
+		sort.Strings(stale)
+		for _, name := range stale {
+			delete(env, name)
+			fmt.Fprintf(bw, "%s=\n", name)
+		}
+		var keys []string
+		for k := range params {
+			if strings.HasPrefix(k, envParam) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			name := k[len(envParam):]
+			if v, set := env[name]; !set || v != params[k] {
+				env[name] = params[k]
+				fmt.Fprintf(bw, "%s=%s\n", name, params[k])
+			}
+		}
+
+		command := params[commandParam]
+		if e.ID != crontabID(fullSpec, command) {
+			fmt.Fprintf(bw, "# %s\n", e.ID)
+		}
+		fmt.Fprintf(bw, "%s %s\n", spec, joinPercent(command, params["stdin"]))
+	}
+	return bw.Flush()
+}
+
+// splitTimeZone splits a "CRON_TZ=Zone" or "TZ=Zone" prefix from a spec.
+func splitTimeZone(spec string) (zone, rest string) {
+	if !strings.HasPrefix(spec, "TZ=") && !strings.HasPrefix(spec, "CRON_TZ=") {
+		return "", spec
+	}
+	i := strings.IndexAny(spec, " \t")
+	if i < 0 {
+		return "", spec
+	}
+	return spec[strings.IndexByte(spec, '=')+1 : i], strings.TrimSpace(spec[i:])
+}
+
+// crontabSpec converts a spec understood by Parse into crontab time fields.
+func crontabSpec(spec string) (string, bool) {
+	if strings.HasPrefix(spec, "@") {
+		return spec, builtinDescriptors[spec]
+	}
+	fields := strings.Fields(spec)
+	switch {
+	case len(fields) == 6 && fields[0] == "0":
+		return strings.Join(fields[1:], " "), true
+	case len(fields) == 5:
+		// Parse allows the day of week to be omitted.
+		return strings.Join(append(fields[1:], "*"), " "), fields[0] == "0"
+	}
+	return "", false
+}
@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testCrontab = `
+SHELL=/bin/sh
+PATH = "/usr/bin:/bin"
+
+# nightly backup
+30 2 * * *   /usr/local/bin/backup  --full
+
+@hourly echo hi
+`
+
+func TestLoadCrontab(t *testing.T) {
+	c := New()
+	if err := c.LoadCrontab(strings.NewReader(testCrontab)); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(c.entries))
+	}
+
+	backup, ok := c.entries["nightly backup"]
+	if !ok {
+		t.Fatal("expected the comment to name the entry")
+	}
+	if backup.Spec != "0 30 2 * * *" {
+		t.Errorf("unexpected spec %q", backup.Spec)
+	}
+	job := backup.Job.(*ShellCommandJob)
+	if job.Command != "/usr/local/bin/backup  --full" {
+		t.Errorf("unexpected command %q", job.Command)
+	}
+	if strings.Join(job.Env, " ") != "PATH=/usr/bin:/bin SHELL=/bin/sh" {
+		t.Errorf("unexpected environment %v", job.Env)
+	}
+}
+
+func TestLoadCrontabInvalidLine(t *testing.T) {
+	c := New()
+	err := c.LoadCrontab(strings.NewReader("* * * * * ok\n61 * * * * bad\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected an error for line 2, got %v", err)
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(c.entries))
+	}
+}
+
+func TestWriteCrontab(t *testing.T) {
+	c := New()
+	if err := c.LoadCrontab(strings.NewReader(testCrontab)); err != nil {
+		t.Fatal(err)
+	}
+	c.AddFunc("*/5 * * * * *", func() (string, error) { return "", nil })
+
+	var buf bytes.Buffer
+	if err := c.WriteCrontab(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"PATH=/usr/bin:/bin\n",
+		"# nightly backup\n30 2 * * * /usr/local/bin/backup  --full\n",
+		"@hourly echo hi\n",
+		"# skipped ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// The exported crontab loads back into an equivalent scheduler.
+	reloaded := New()
+	if err := reloaded.LoadCrontab(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.entries) != 2 {
+		t.Errorf("expected 2 reloaded entries, got %d", len(reloaded.entries))
+	}
+}
+
+func TestWriteCrontabRoundTrip(t *testing.T) {
+	c := New()
+	err := c.LoadCrontab(strings.NewReader("A=1\n# b\n@daily echo b\n\nA=\n@hourly echo a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.AddJobConfig(JobConfig{Name: "custom", Spec: "0 0 12 * * *", Type: CommandJobType, Params: map[string]string{"command": "echo c", "env.A": "2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AddFunc("*/5 * * * * *", func() (string, error) { return "", nil })
+
+	var buf bytes.Buffer
+	if err := c.WriteCrontab(&buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := New()
+	if err := reloaded.LoadCrontab(strings.NewReader(buf.String())); err != nil {
+		t.Fatalf("%s\n%s", err, buf.String())
+	}
+	var ids, want []string
+	for _, e := range reloaded.Entries() {
+		ids = append(ids, e.ID)
+		if env := strings.Join(e.Job.(*ShellCommandJob).Env, " "); (e.ID == "b") != (env == "A=1") || (e.ID == "custom") != (env == "A=2") {
+			t.Errorf("unexpected environment of %s: %q", e.ID, env)
+		}
+	}
+	for _, e := range c.Entries() {
+		if typ, _ := describe(e.Job); typ == CommandJobType {
+			want = append(want, e.ID)
+		}
+	}
+	sort.Strings(ids)
+	sort.Strings(want)
+	if strings.Join(ids, " ") != strings.Join(want, " ") {
+		t.Errorf("expected ids %v, got %v in:\n%s", want, ids, buf.String())
+	}
+}
+
+func TestLoadCrontabDuplicateID(t *testing.T) {
+	c := New()
+	err := c.LoadCrontab(strings.NewReader("# a\n@hourly echo 1\n# a\n@daily echo 2\n"))
+	if err == nil || !strings.Contains(err.Error(), "Duplicate id a") {
+		t.Fatalf("expected a duplicate id error, got %v", err)
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(c.entries))
+	}
+}
+
+func TestParseCrontab(t *testing.T) {
+	cfg, err := ParseCrontab(strings.NewReader(testCrontab))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(cfg.Jobs))
+	}
+	if jc := cfg.Jobs[0]; jc.Name != "nightly backup" || jc.Type != CommandJobType || jc.Spec != "0 30 2 * * *" {
+		t.Errorf("unexpected job %+v", jc)
+	}
+	if jc := cfg.Jobs[1]; jc.Name != crontabID("@hourly", "echo hi") {
+		t.Errorf("expected a derived name, got %q", jc.Name)
+	}
+}
+
+func TestLoadCrontabSemantics(t *testing.T) {
+	const crontab = `
+  SHELL=/bin/bash
+	MAILTO=ops@example.com
+CRON_TZ=Asia/Tokyo
+	# greeting
+  0 9 * * * cat %hello%world \% done
+`
+	cfg, err := ParseCrontab(strings.NewReader(crontab))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(cfg.Jobs))
+	}
+	jc := cfg.Jobs[0]
+	if jc.Name != "greeting" || jc.Spec != "CRON_TZ=Asia/Tokyo 0 0 9 * * *" {
+		t.Errorf("unexpected job %+v", jc)
+	}
+	for k, want := range map[string]string{
+		"command": "cat",
+		"stdin":   "hello\nworld % done",
+		"shell":   "/bin/bash",
+		"mailto":  "ops@example.com",
+	} {
+		if got := jc.Params[k]; got != want {
+			t.Errorf("%s: expected %q, got %q", k, want, got)
+		}
+	}
+	if _, ok := jc.Params["env.CRON_TZ"]; ok {
+		t.Error("expected CRON_TZ not to be exported")
+	}
+
+	c := New()
+	if err := c.LoadCrontab(strings.NewReader(crontab)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteCrontab(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "CRON_TZ=Asia/Tokyo\n") ||
+		!strings.Contains(out, "0 9 * * * cat %hello%world \\% done\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestShellCommandJobStdinAndMail(t *testing.T) {
+	sent := captureMail(t)
+	CommandMailer = &Mailer{Addr: "smtp.example.com:25", From: "cron@example.com"}
+	defer func() { CommandMailer = nil }()
+
+	job, err := newJob(CommandJobType, "greeting", map[string]string{
+		"command": "cat",
+		"stdin":   "hello\n",
+		"mailto":  "ops@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := job.Run()
+	if err != nil || msg != "hello\n" {
+		t.Fatalf("unexpected result %q, %v", msg, err)
+	}
+	if len(*sent) != 1 || (*sent)[0].to[0] != "ops@example.com" {
+		t.Fatalf("expected the output to be mailed, got %+v", *sent)
+	}
+}
@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DescriptorFactory returns the schedule a custom descriptor stands for.
+type DescriptorFactory func() (Schedule, error)
+
+// descriptor is a registered descriptor, standing for either a spec or the
+// schedule returned by a factory.
+type descriptor struct {
+	spec    string
+	factory DescriptorFactory
+}
+
+var (
+	descriptorsMu sync.RWMutex
+	descriptors   = make(map[string]descriptor)
+)
+
+// builtinDescriptors are the descriptors understood by every parser.
+var builtinDescriptors = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// RegisterDescriptor makes the parsers accepting descriptors understand
+// name, e.g. "@nightly-maintenance", as the schedule returned by factory.
+// Descriptors are meant to be registered at init time, before specs using
+// them are parsed: DefaultSpecCache keeps the schedules it already parsed.
+// Registering a name again replaces it. It panics if the name does not
+// start with "@", contains whitespace or is a built-in descriptor.
+func RegisterDescriptor(name string, factory DescriptorFactory) {
+	if factory == nil {
+		panic("cron: RegisterDescriptor factory is nil")
+	}
+	registerDescriptor(name, descriptor{factory: factory})
+}
+
+// RegisterDescriptorSpec makes name an alias of spec, which is parsed by the
+// parser the descriptor is used with. The spec may start with a time zone
+// prefix, and may be another descriptor.
+func RegisterDescriptorSpec(name, spec string) {
+	registerDescriptor(name, descriptor{spec: spec})
+}
+
+func registerDescriptor(name string, d descriptor) {
+	if !strings.HasPrefix(name, "@") || len(name) == 1 || strings.ContainsAny(name, " \t\n") {
+		panic("cron: invalid descriptor name " + name)
+	}
+	if builtinDescriptors[name] || name == "@every" || name == "@date" {
+		panic("cron: built-in descriptor " + name)
+	}
+	descriptorsMu.Lock()
+	defer descriptorsMu.Unlock()
+	descriptors[name] = d
+}
+
+// Descriptors returns the names of the registered descriptors, sorted.
+func Descriptors() []string {
+	descriptorsMu.RLock()
+	defer descriptorsMu.RUnlock()
+	names := make([]string, 0, len(descriptors))
+	for name := range descriptors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maxDescriptorDepth bounds the chains of descriptor aliases, which would
+// otherwise loop forever on a cycle.
+const maxDescriptorDepth = 8
+
+// customDescriptor returns the schedule of a registered descriptor, parsing
+// the spec of aliases with p. ok is false if the descriptor is not
+// registered.
+func (p Parser) customDescriptor(name string, depth int) (schedule Schedule, ok bool, err error) {
+	descriptorsMu.RLock()
+	d, ok := descriptors[name]
+	descriptorsMu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	switch {
+	case d.factory != nil:
+		schedule, err = d.factory()
+	case depth >= maxDescriptorDepth:
+		err = fmt.Errorf("Too many nested descriptors")
+	default:
+		schedule, err = p.parse(d.spec, depth+1)
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("Failed to build descriptor %s: %w", name, err)
+	}
+	if schedule == nil {
+		return nil, true, fmt.Errorf("Descriptor %s has no schedule", name)
+	}
+	return schedule, true, nil
+}
@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegisterDescriptor(t *testing.T) {
+	RegisterDescriptorSpec("@test-nightly", "0 30 2 * * *")
+	RegisterDescriptorSpec("@test-alias", "@test-nightly")
+	RegisterDescriptor("@test-factory", func() (Schedule, error) {
+		return Every(90 * time.Minute), nil
+	})
+	RegisterDescriptorSpec("@test-loop", "@test-loop")
+	RegisterDescriptor("@test-failing", func() (Schedule, error) {
+		return nil, errors.New("boom")
+	})
+	defer func() {
+		descriptorsMu.Lock()
+		for _, name := range []string{"@test-nightly", "@test-alias", "@test-factory", "@test-loop", "@test-failing"} {
+			delete(descriptors, name)
+		}
+		descriptorsMu.Unlock()
+	}()
+
+	now := getTime("Mon Jul 9 14:45 2012")
+	for spec, expected := range map[string]time.Time{
+		"@test-nightly":                    getTime("Tue Jul 10 02:30 2012"),
+		"@test-alias":                      getTime("Tue Jul 10 02:30 2012"),
+		"@test-factory":                    now.Add(90 * time.Minute),
+		"CRON_TZ=Asia/Tokyo @test-nightly": time.Date(2012, 7, 9, 17, 30, 0, 0, time.UTC),
+	} {
+		sched, err := defaultParser.Parse(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if actual := sched.Next(now); !actual.Equal(expected) {
+			t.Errorf("%s: (expected) %v != %v (actual)", spec, expected, actual)
+		}
+	}
+
+	for _, spec := range []string{"@test-loop", "@test-failing", "@test-unknown"} {
+		if _, err := defaultParser.Parse(spec); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+	if _, err := NewParser(Minute | Hour | Dom | Month | Dow).Parse("@test-nightly"); err == nil {
+		t.Error("expected descriptors to need the Descriptor option")
+	}
+
+	names := Descriptors()
+	if len(names) < 5 || names[0] > names[len(names)-1] {
+		t.Errorf("expected the sorted registered names, got %v", names)
+	}
+	for _, name := range []string{"@daily", "@every", "nightly", "@two words"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			RegisterDescriptorSpec(name, "0 0 0 * * *")
+		}()
+	}
+}
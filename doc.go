@@ -31,9 +31,9 @@ A cron expression represents a set of times, using 6 space-separated fields.
 	Seconds      | Yes        | 0-59            | * / , -
 	Minutes      | Yes        | 0-59            | * / , -
 	Hours        | Yes        | 0-23            | * / , -
-	Day of month | Yes        | 1-31            | * / , - ?
+	Day of month | Yes        | 1-31            | * / , - ? LW
 	Month        | Yes        | 1-12 or JAN-DEC | * / , -
-	Day of week  | Yes        | 0-6 or SUN-SAT  | * / , - ?
+	Day of week  | Yes        | 0-6 or SUN-SAT  | * / , - ? L
 
 Note: Month and Day-of-week field values are case insensitive.  "SUN", "Sun",
 and "sun" are equally accepted.
@@ -70,6 +70,12 @@ Question mark ( ? )
 Question mark may be used instead of '*' for leaving either day-of-month or
 day-of-week blank.
 
+Last ( LW and L )
+
+In the day-of-month field, LW stands for the last weekday, Monday to Friday, of
+the month. In the day-of-week field, a day followed by L stands for the last
+such day of the month; e.g., 5L or FRIL is the last Friday of the month.
+
 Predefined schedules
 
 You may use one of several pre-defined schedules in place of a cron expression.
@@ -82,6 +88,9 @@ You may use one of several pre-defined schedules in place of a cron expression.
 	@daily (or @midnight)  | Run once a day, midnight                   | 0 0 0 * * *
 	@hourly                | Run once an hour, beginning of hour        | 0 0 * * * *
 
+Applications may register descriptors of their own, e.g. "@nightly-maintenance",
+with RegisterDescriptorSpec or RegisterDescriptor.
+
 Intervals
 
 You may also schedule a job to execute at fixed intervals, starting at the time it's added 
@@ -99,6 +108,19 @@ Note: The interval does not take the job runtime into account.  For example,
 if a job takes 3 minutes to run, and it is scheduled to run every 5 minutes,
 it will have only 2 minutes of idle time between each run.
 
+Intervals are relative to the time the job is added or cron is run, unless the
+Cron is created with WithAlignedIntervals: "@every 15m" then runs at :00, :15,
+:30 and :45, and "@every 1h" at the top of the hour.
+
+Chaining
+
+A job may run after another one rather than at times of its own:
+
+    @after <job id> [delay]
+
+e.g. "@after extract 5m" runs 5 minutes after each successful run of the job
+extract. See AfterSchedule.
+
 Time zones
 
 All interpretation and scheduling is done in the machine's local time zone (as
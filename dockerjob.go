@@ -0,0 +1,331 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DockerJobType is the job type of DockerJob.
+const DockerJobType = "docker"
+
+// DefaultDockerHost is the Docker daemon address used if DOCKER_HOST is not
+// set.
+const DefaultDockerHost = "unix:///var/run/docker.sock"
+
+// dockerAPIVersion is the Docker Engine API version spoken by DockerJob.
+const dockerAPIVersion = "v1.41"
+
+// DockerJob runs a container to completion through the Docker Engine API and
+// reports its logs as the result message. A non-zero exit code is reported as
+// an error. The container is removed afterwards.
+type DockerJob struct {
+	id string
+
+	Image string
+	// Cmd overrides the command of the image if not empty.
+	Cmd []string
+	// Env holds "KEY=value" pairs.
+	Env []string
+	// Mounts are bind mounts in "source:target[:ro]" form.
+	Mounts []string
+	// Memory limits the container memory in bytes. Zero means no limit.
+	Memory int64
+	// CPUs limits the container to a number of CPUs. Zero means no limit.
+	CPUs float64
+	// Pull pulls the image before every run.
+	Pull bool
+	// Timeout kills the container if it runs longer. Zero means no timeout.
+	Timeout time.Duration
+	// Host is the Docker daemon address, e.g. "unix:///var/run/docker.sock"
+	// or "tcp://127.0.0.1:2375". It defaults to DOCKER_HOST, or
+	// DefaultDockerHost.
+	Host string
+}
+
+// NewDockerJob returns a job with the given id running the image.
+func NewDockerJob(id, image string, cmd ...string) *DockerJob {
+	return &DockerJob{id: id, Image: image, Cmd: cmd}
+}
+
+func init() {
+	RegisterJobType(DockerJobType, newDockerJob)
+}
+
+// newDockerJob builds a DockerJob from the "image", "arg.N", "env.KEY",
+// "mount.N", "memory", "cpus", "pull", "timeout" and "host" parameters.
+func newDockerJob(id string, params map[string]string) (Job, error) {
+	j := NewDockerJob(id, params["image"])
+	if j.Image == "" {
+		return nil, fmt.Errorf("Missing image parameter")
+	}
+	j.Cmd = indexedParams(params, "arg.")
+	j.Mounts = indexedParams(params, "mount.")
+	j.Env = envParams(params)
+	j.Pull = params["pull"] == "true"
+	j.Host = params["host"]
+	if m := params["memory"]; m != "" {
+		memory, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse memory %s: %s", m, err)
+		}
+		j.Memory = memory
+	}
+	if c := params["cpus"]; c != "" {
+		cpus, err := strconv.ParseFloat(c, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse cpus %s: %s", c, err)
+		}
+		j.CPUs = cpus
+	}
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	return j, nil
+}
+
+// indexedParams returns the values of the prefix.0, prefix.1, ...
+// parameters.
+func indexedParams(params map[string]string, prefix string) []string {
+	var values []string
+	for i := 0; ; i++ {
+		v, ok := params[prefix+strconv.Itoa(i)]
+		if !ok {
+			return values
+		}
+		values = append(values, v)
+	}
+}
+
+// envParams returns the env.KEY parameters as sorted "KEY=value" pairs.
+func envParams(params map[string]string) []string {
+	var env []string
+	for k, v := range params {
+		if strings.HasPrefix(k, envParam) {
+			env = append(env, k[len(envParam):]+"="+v)
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+func (j *DockerJob) ID() string { return j.id }
+
+func (j *DockerJob) JobType() string { return DockerJobType }
+
+func (j *DockerJob) Params() map[string]string {
+	params := map[string]string{"image": j.Image}
+	for i, arg := range j.Cmd {
+		params["arg."+strconv.Itoa(i)] = arg
+	}
+	for i, m := range j.Mounts {
+		params["mount."+strconv.Itoa(i)] = m
+	}
+	for _, kv := range j.Env {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			params[envParam+kv[:i]] = kv[i+1:]
+		}
+	}
+	if j.Memory > 0 {
+		params["memory"] = strconv.FormatInt(j.Memory, 10)
+	}
+	if j.CPUs > 0 {
+		params["cpus"] = strconv.FormatFloat(j.CPUs, 'f', -1, 64)
+	}
+	if j.Pull {
+		params["pull"] = "true"
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	if j.Host != "" {
+		params["host"] = j.Host
+	}
+	return params
+}
+
+// Run runs the container and waits for it to exit.
+func (j *DockerJob) Run() (msg string, err error) {
+	d, err := newDockerClient(j.Host)
+	if err != nil {
+		return "", err
+	}
+
+	if j.Pull {
+		image, tag := j.Image, "latest"
+		if i := strings.LastIndexByte(image, ':'); i > strings.LastIndexByte(image, '/') {
+			image, tag = image[:i], image[i+1:]
+		}
+		q := url.Values{"fromImage": {image}, "tag": {tag}}
+		if err := d.call(context.Background(), "POST", "/images/create?"+q.Encode(), nil, nil); err != nil {
+			return "", fmt.Errorf("Failed to pull %s: %s", j.Image, err)
+		}
+	}
+
+	create := map[string]interface{}{
+		"Image": j.Image,
+		"Env":   j.Env,
+		"HostConfig": map[string]interface{}{
+			"Binds":    j.Mounts,
+			"Memory":   j.Memory,
+			"NanoCpus": int64(j.CPUs * 1e9),
+		},
+	}
+	if len(j.Cmd) > 0 {
+		create["Cmd"] = j.Cmd
+	}
+	var created struct{ Id string }
+	if err := d.call(context.Background(), "POST", "/containers/create", create, &created); err != nil {
+		return "", fmt.Errorf("Failed to create container: %s", err)
+	}
+	container := "/containers/" + created.Id
+	defer d.call(context.Background(), "DELETE", container+"?force=1", nil, nil)
+
+	if err := d.call(context.Background(), "POST", container+"/start", nil, nil); err != nil {
+		return "", fmt.Errorf("Failed to start container: %s", err)
+	}
+
+	ctx := context.Background()
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	var exit struct{ StatusCode int }
+	waitErr := d.call(ctx, "POST", container+"/wait", nil, &exit)
+	if ctx.Err() == context.DeadlineExceeded {
+		d.call(context.Background(), "POST", container+"/kill", nil, nil)
+	}
+
+	logs, err := d.logs(container)
+	if err != nil {
+		logs = ""
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return logs, fmt.Errorf("Container %s timed out after %s", j.Image, j.Timeout)
+	case waitErr != nil:
+		return logs, fmt.Errorf("Failed to wait for container: %s", waitErr)
+	case exit.StatusCode != 0:
+		return logs, fmt.Errorf("Container %s exited with code %d", j.Image, exit.StatusCode)
+	}
+	return logs, nil
+}
+
+// dockerClient calls the Docker Engine API.
+type dockerClient struct {
+	http *http.Client
+	base string
+}
+
+func newDockerClient(host string) (*dockerClient, error) {
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+	if host == "" {
+		host = DefaultDockerHost
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Docker host %s: %s", host, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		path := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+		return &dockerClient{&http.Client{Transport: transport}, "http://docker/" + dockerAPIVersion}, nil
+	case "tcp", "http":
+		return &dockerClient{http.DefaultClient, "http://" + u.Host + "/" + dockerAPIVersion}, nil
+	}
+	return nil, fmt.Errorf("Unsupported Docker host %s", host)
+}
+
+func (d *dockerClient) call(ctx context.Context, method, path string, in, out interface{}) error {
+	resp, err := d.do(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+func (d *dockerClient) do(ctx context.Context, method, path string, in interface{}) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, d.base+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct{ Message string }
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("%s %s: %s %s", method, path, resp.Status, apiErr.Message)
+	}
+	return resp, nil
+}
+
+// logs returns the combined stdout and stderr of a container.
+func (d *dockerClient) logs(container string) (string, error) {
+	resp, err := d.do(context.Background(), "GET", container+"/logs?stdout=1&stderr=1", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	out := &limitedBuffer{max: MaxCommandOutput}
+	// Logs of containers without a TTY are multiplexed in frames made of an
+	// 8 byte header, holding the stream and the payload size, and a payload.
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(resp.Body, header[:]); err != nil {
+			if err == io.EOF {
+				return out.String(), nil
+			}
+			return out.String(), err
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(out, resp.Body, size); err != nil {
+			return out.String(), err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cron
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDocker serves the parts of the Docker Engine API used by DockerJob.
+type fakeDocker struct {
+	mu       sync.Mutex
+	create   map[string]interface{}
+	calls    []string
+	exitCode int
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/"+dockerAPIVersion)
+	f.calls = append(f.calls, r.Method+" "+path)
+	switch path {
+	case "/containers/create":
+		json.NewDecoder(r.Body).Decode(&f.create)
+		json.NewEncoder(w).Encode(map[string]string{"Id": "c1"})
+	case "/containers/c1/wait":
+		json.NewEncoder(w).Encode(map[string]int{"StatusCode": f.exitCode})
+	case "/containers/c1/logs":
+		for i, line := range []string{"out\n", "err\n"} {
+			header := make([]byte, 8)
+			header[0] = byte(i + 1)
+			binary.BigEndian.PutUint32(header[4:], uint32(len(line)))
+			w.Write(header)
+			w.Write([]byte(line))
+		}
+	}
+}
+
+func TestDockerJob(t *testing.T) {
+	fake := &fakeDocker{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	params := map[string]string{
+		"image":   "alpine:3",
+		"arg.0":   "echo",
+		"arg.1":   "hi",
+		"env.FOO": "bar",
+		"mount.0": "/tmp:/data:ro",
+		"memory":  "1048576",
+		"cpus":    "0.5",
+		"host":    "tcp://" + strings.TrimPrefix(srv.URL, "http://"),
+	}
+	job, err := newJob(DockerJobType, "docker", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, got := describe(job); !reflect.DeepEqual(got, params) {
+		t.Errorf("params = %v, want %v", got, params)
+	}
+
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "out\nerr\n" {
+		t.Errorf("msg = %q", msg)
+	}
+	want := []string{
+		"POST /containers/create",
+		"POST /containers/c1/start",
+		"POST /containers/c1/wait",
+		"GET /containers/c1/logs",
+		"DELETE /containers/c1",
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+	host := fake.create["HostConfig"].(map[string]interface{})
+	if host["NanoCpus"].(float64) != 5e8 || host["Memory"].(float64) != 1048576 {
+		t.Errorf("host config = %v", host)
+	}
+	if !reflect.DeepEqual(fake.create["Env"], []interface{}{"FOO=bar"}) {
+		t.Errorf("env = %v", fake.create["Env"])
+	}
+
+	fake.exitCode = 3
+	if _, err := job.Run(); err == nil || !strings.Contains(err.Error(), "code 3") {
+		t.Errorf("err = %v, want exit code 3", err)
+	}
+}
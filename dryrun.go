@@ -0,0 +1,23 @@
+package cron
+
+import "time"
+
+// WithDryRun puts the Cron in dry-run mode: jobs are never run. Every run the
+// scheduler would have performed is logged and emitted as an EventJobDryRun
+// event instead, so a job set or spec changes can be validated safely.
+func WithDryRun() Option {
+	return func(c *Cron) {
+		c.dryRun = true
+	}
+}
+
+// DryRun reports whether the Cron is in dry-run mode.
+func (c *Cron) DryRun() bool {
+	return c.dryRun
+}
+
+// skipDryRun records the run of j due at t without running it.
+func (c *Cron) skipDryRun(j Job, t time.Time) {
+	c.entryLogf(j.ID(), "cron: dry run: job %s due at %s", j.ID(), t.Format(time.RFC3339))
+	c.emit(Event{Type: EventJobDryRun, EntryID: j.ID(), Time: t})
+}
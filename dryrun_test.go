@@ -0,0 +1,45 @@
+package cron
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestDryRun(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local))
+	c := New(WithClock(clock), WithDryRun())
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	ran := false
+	c.AddFunc("0 0 * * * *", func() (string, error) { return "", nil })
+	c.AddFunc("0 0 * * * *", func() (string, error) {
+		ran = true
+		return "", nil
+	})
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Hour)
+	if ran {
+		t.Error("expected the job not to run in dry-run mode")
+	}
+	var dryRuns int
+	for len(events) > 0 {
+		e := <-events
+		if e.Type == EventJobStarted {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if e.Type == EventJobDryRun {
+			dryRuns++
+			if !e.Time.Equal(clock.Now()) {
+				t.Errorf("expected the due time %v, got %v", clock.Now(), e.Time)
+			}
+		}
+	}
+	if dryRuns != 2 {
+		t.Errorf("expected 2 dry runs, got %d", dryRuns)
+	}
+}
@@ -0,0 +1,192 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// resultJSON is the JSON document of a JobResult. Durations are in
+// nanoseconds, like in the run history.
+type resultJSON struct {
+	JobID          string        `json:"job_id"`
+	Msg            string        `json:"msg,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Usage          Usage         `json:"usage"`
+	Wait           time.Duration `json:"wait"`
+	Attempt        int           `json:"attempt"`
+	MaxAttempts    int           `json:"max_attempts"`
+	Retry          bool          `json:"retry,omitempty"`
+	IdempotencyKey string        `json:"idempotency_key,omitempty"`
+}
+
+// MarshalJSON encodes the result with stable field names, so that results
+// can be shipped to logs, queues or webhooks as is. The Ref of the result is
+// left out and its Error is encoded as its message.
+func (r JobResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newResultJSON(&r))
+}
+
+// newResultJSON returns the JSON document of r.
+func newResultJSON(r *JobResult) resultJSON {
+	out := resultJSON{
+		JobID:          r.JobId,
+		Msg:            r.Msg,
+		Usage:          r.Usage,
+		Wait:           r.Wait,
+		Attempt:        r.Attempt,
+		MaxAttempts:    r.MaxAttempts,
+		Retry:          r.Retry,
+		IdempotencyKey: r.IdempotencyKey,
+	}
+	if r.Error != nil {
+		out.Error = r.Error.Error()
+	}
+	return out
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON. The decoded result
+// has no Ref, and its Error only keeps the message of the original one.
+func (r *JobResult) UnmarshalJSON(data []byte) error {
+	var in resultJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = JobResult{
+		JobId:          in.JobID,
+		Msg:            in.Msg,
+		Usage:          in.Usage,
+		Wait:           in.Wait,
+		Attempt:        in.Attempt,
+		MaxAttempts:    in.MaxAttempts,
+		Retry:          in.Retry,
+		IdempotencyKey: in.IdempotencyKey,
+	}
+	if in.Error != "" {
+		r.Error = errors.New(in.Error)
+	}
+	return nil
+}
+
+// entryJSON is the JSON document of an Entry. The fields shared with
+// EntryState have the same names. Durations are in nanoseconds.
+type entryJSON struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Spec        string            `json:"spec"`
+	Type        string            `json:"type,omitempty"`
+	Params      map[string]string `json:"params,omitempty"`
+	Prev        time.Time         `json:"prev"`
+	Next        time.Time         `json:"next"`
+	Location    string            `json:"location,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Resources   []string          `json:"resources,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Priority    int               `json:"priority,omitempty"`
+	Preemptible bool              `json:"preemptible,omitempty"`
+	Synchronous bool              `json:"synchronous,omitempty"`
+	Retries     int               `json:"retries,omitempty"`
+	RetryDelay  time.Duration     `json:"retry_delay,omitempty"`
+	Paused      bool              `json:"paused"`
+	SLA         time.Duration     `json:"sla,omitempty"`
+	Misfire     MisfirePolicy     `json:"misfire,omitempty"`
+	Runs        int               `json:"runs"`
+	MaxRuns     int               `json:"max_runs,omitempty"`
+	Expires     *time.Time        `json:"expires,omitempty"`
+	Breaker     BreakerState      `json:"breaker,omitempty"`
+	Disabled    bool              `json:"disabled,omitempty"`
+	Version     int               `json:"version"`
+	Revision    uint64            `json:"revision,omitempty"`
+	Status      EntryStatus       `json:"status,omitempty"`
+}
+
+// MarshalJSON encodes the entry with stable field names, e.g. for the
+// entries returned by Entries. The schedule is encoded as the spec of the
+// entry, derived from the schedule when possible like for Snapshot, and the
+// job as its id and, for a DescribedJob, its type and params.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	out := entryJSON{
+		ID:          e.ID,
+		Name:        e.Name,
+		Spec:        entrySpec(&e),
+		Prev:        e.Prev,
+		Next:        e.Next,
+		Tags:        e.Tags,
+		Resources:   e.Resources,
+		Namespace:   e.Namespace,
+		Priority:    e.Priority,
+		Preemptible: e.Preemptible,
+		Synchronous: e.Synchronous,
+		Retries:     e.Retries,
+		RetryDelay:  e.RetryDelay,
+		Paused:      e.Paused,
+		SLA:         e.SLA,
+		Misfire:     e.Misfire,
+		Runs:        e.Runs,
+		MaxRuns:     e.MaxRuns,
+		Breaker:     e.Breaker,
+		Disabled:    e.Disabled,
+		Version:     e.Version,
+		Revision:    e.Revision,
+		Status:      e.Status,
+	}
+	if e.Job != nil {
+		if out.ID == "" {
+			out.ID = e.Job.ID()
+		}
+		out.Type, out.Params = describe(e.Job)
+	}
+	if e.Location != nil {
+		out.Location = e.Location.String()
+	}
+	if !e.Expires.IsZero() {
+		out.Expires = &e.Expires
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes an entry encoded by MarshalJSON. The decoded entry
+// has neither a Schedule nor a Job; Restore rebuilds them from a Snapshot.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var in entryJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*e = Entry{
+		ID:          in.ID,
+		Name:        in.Name,
+		Spec:        in.Spec,
+		Prev:        in.Prev,
+		Next:        in.Next,
+		Tags:        in.Tags,
+		Resources:   in.Resources,
+		Namespace:   in.Namespace,
+		Priority:    in.Priority,
+		Preemptible: in.Preemptible,
+		Synchronous: in.Synchronous,
+		Retries:     in.Retries,
+		RetryDelay:  in.RetryDelay,
+		Paused:      in.Paused,
+		SLA:         in.SLA,
+		Misfire:     in.Misfire,
+		Runs:        in.Runs,
+		MaxRuns:     in.MaxRuns,
+		Breaker:     in.Breaker,
+		Disabled:    in.Disabled,
+		Version:     in.Version,
+		Revision:    in.Revision,
+		Status:      in.Status,
+	}
+	if in.Expires != nil {
+		e.Expires = *in.Expires
+	}
+	if in.Location != "" {
+		loc, err := time.LoadLocation(in.Location)
+		if err != nil {
+			return fmt.Errorf("Entry %s: %s", in.ID, err)
+		}
+		e.Location = loc
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJobResultJSON(t *testing.T) {
+	r := &JobResult{
+		JobId:       "backup",
+		Ref:         &chainJob{id: "backup"},
+		Msg:         "ok",
+		Error:       errors.New("disk full"),
+		Usage:       Usage{CPUTime: time.Second, Memory: 1024},
+		Wait:        time.Millisecond,
+		Attempt:     2,
+		MaxAttempts: 3,
+		Retry:       true,
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"job_id":"backup","msg":"ok","error":"disk full","usage":{"cpu_time":1000000000,"memory":1024,"precise":false},"wait":1000000,"attempt":2,"max_attempts":3,"retry":true}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	var got JobResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error == nil || got.Error.Error() != "disk full" || got.Ref != nil {
+		t.Errorf("unexpected result %+v", got)
+	}
+	got.Error, r.Error, r.Ref = nil, nil, nil
+	if !reflect.DeepEqual(&got, r) {
+		t.Errorf("expected %+v, got %+v", r, &got)
+	}
+}
+
+func TestEntryJSON(t *testing.T) {
+	c := New()
+	c.AddJob("@every 1h", &testDescribedJob{"report", "ops"}, WithTags("daily"), WithRetries(2, time.Minute))
+	c.entries["report"].Name = "Report"
+	e, _ := c.Entry("report")
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	for key, want := range map[string]interface{}{
+		"id":          "report",
+		"name":        "Report",
+		"spec":        "@every 1h",
+		"type":        "test",
+		"retries":     2.0,
+		"retry_delay": float64(time.Minute),
+		"paused":      false,
+		"version":     1.0,
+	} {
+		if !reflect.DeepEqual(fields[key], want) {
+			t.Errorf("expected %s to be %v, got %v", key, want, fields[key])
+		}
+	}
+	if _, ok := fields["expires"]; ok {
+		t.Errorf("expected no expiry, got %s", data)
+	}
+
+	var got Entry
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "report" || got.Name != "Report" || got.Spec != "@every 1h" || got.Retries != 2 ||
+		!reflect.DeepEqual(got.Tags, []string{"daily"}) || got.Job != nil || got.Schedule != nil {
+		t.Errorf("unexpected entry %+v", got)
+	}
+}
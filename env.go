@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvPrefix is the prefix of environment variables defining jobs.
+const EnvPrefix = "CRONJOB_"
+
+// envSeparator separates the spec from the command in a job variable.
+const envSeparator = "::"
+
+// ConfigFromEnv builds a config from environment variables of the form
+//
+//	CRONJOB_<NAME>="<spec> :: <command>"
+//
+// where spec is anything accepted by Parse. Each variable becomes a
+// CommandJobType job named after the lower-cased NAME. Other variables are
+// ignored.
+func ConfigFromEnv(environ []string) (*Config, error) {
+	cfg := &Config{}
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, EnvPrefix) {
+			continue
+		}
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			continue
+		}
+		key, value := kv[:i], kv[i+1:]
+		name := strings.ToLower(key[len(EnvPrefix):])
+		if name == "" {
+			return nil, fmt.Errorf("%s: missing job name", key)
+		}
+		sep := strings.Index(value, envSeparator)
+		if sep < 0 {
+			return nil, fmt.Errorf("%s: expected \"spec %s command\"", key, envSeparator)
+		}
+		spec := strings.TrimSpace(value[:sep])
+		command := strings.TrimSpace(value[sep+len(envSeparator):])
+		if spec == "" || command == "" {
+			return nil, fmt.Errorf("%s: expected \"spec %s command\"", key, envSeparator)
+		}
+		cfg.Jobs = append(cfg.Jobs, JobConfig{
+			Name:   name,
+			Spec:   spec,
+			Type:   CommandJobType,
+			Params: map[string]string{commandParam: command},
+		})
+	}
+	sort.Slice(cfg.Jobs, func(i, j int) bool { return cfg.Jobs[i].Name < cfg.Jobs[j].Name })
+	return cfg, nil
+}
+
+// envSource is the source of the jobs loaded by LoadEnv.
+const envSource = "env"
+
+// LoadEnv applies the jobs defined in the process environment (see
+// ConfigFromEnv) to the Cron. Like the jobs of a watched config file, they
+// are kept apart from the ones of ApplyConfig: loading either one does not
+// remove the jobs of the other.
+func (c *Cron) LoadEnv() error {
+	cfg, err := ConfigFromEnv(os.Environ())
+	if err != nil {
+		return err
+	}
+	return c.applyConfig(envSource, cfg)
+}
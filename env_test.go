@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	cfg, err := ConfigFromEnv([]string{
+		"HOME=/root",
+		"CRONJOB_CLEANUP=0 0 3 * * * :: rm -rf /tmp/cache",
+		"CRONJOB_PING=@every 1m ::curl -s http://localhost/ping",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(cfg.Jobs))
+	}
+	cleanup := cfg.Jobs[0]
+	if cleanup.Name != "cleanup" || cleanup.Spec != "0 0 3 * * *" || cleanup.Params["command"] != "rm -rf /tmp/cache" {
+		t.Errorf("unexpected job %+v", cleanup)
+	}
+	if ping := cfg.Jobs[1]; ping.Spec != "@every 1m" || ping.Type != CommandJobType {
+		t.Errorf("unexpected job %+v", ping)
+	}
+
+	c := New()
+	if err := c.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(c.entries))
+	}
+}
+
+func TestConfigFromEnvInvalid(t *testing.T) {
+	for _, kv := range []string{
+		"CRONJOB_=@hourly :: true",
+		"CRONJOB_X=@hourly true",
+		"CRONJOB_X= :: true",
+	} {
+		if _, err := ConfigFromEnv([]string{kv}); err == nil {
+			t.Errorf("expected an error for %q", kv)
+		}
+	}
+}
+
+func TestLoadEnvKeepsAppliedConfig(t *testing.T) {
+	os.Setenv("CRONJOB_PING", "@every 1m :: true")
+	defer os.Unsetenv("CRONJOB_PING")
+
+	c := New()
+	cfg := &Config{Jobs: []JobConfig{{Name: "backup", Spec: "@daily", Type: CommandJobType, Params: map[string]string{"command": "true"}}}}
+	if err := c.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"backup", "ping"} {
+		if _, ok := c.Entry(id); !ok {
+			t.Errorf("expected entry %s to be kept", id)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by scheduler operations, to be matched with errors.Is.
+var (
+	// ErrJobNotFound is returned for operations on a job id with no entry.
+	ErrJobNotFound = errors.New("Job not found")
+	// ErrInvalidSpec is matched by the *SpecError returned for invalid specs.
+	ErrInvalidSpec = errors.New("Invalid spec")
+	// ErrSchedulerStopped is returned for operations needing a running
+	// scheduler.
+	ErrSchedulerStopped = errors.New("Scheduler stopped")
+	// ErrDuplicateJob is returned when adding a job whose id is taken, by
+	// the operations that do not replace existing entries.
+	ErrDuplicateJob = errors.New("Job already exists")
+	// ErrQuotaExceeded is returned when adding an entry to a Cron holding
+	// the maximum number of entries set by WithMaxEntries.
+	ErrQuotaExceeded = errors.New("Entry quota exceeded")
+	// ErrNoPreviousVersion is returned when rolling back an entry that was
+	// never replaced.
+	ErrNoPreviousVersion = errors.New("No previous version")
+	// ErrObserver is returned when asking a Cron in observer mode to run a
+	// job.
+	ErrObserver = errors.New("Cron is an observer")
+	// ErrPreempted is matched by the error of a run cancelled to make room
+	// for a run of a higher priority.
+	ErrPreempted = errors.New("Preempted")
+	// ErrChaos is matched by the errors of the runs failed on purpose by
+	// WithChaos.
+	ErrChaos = errors.New("Injected failure")
+	// ErrRevisionUnavailable is returned when watching the entries from a
+	// revision whose changes are not kept.
+	ErrRevisionUnavailable = errors.New("Revision unavailable")
+	// ErrConflict is returned when updating an entry changed since the
+	// revision the update expects.
+	ErrConflict = errors.New("Conflict")
+)
+
+// SpecError describes an invalid spec. It matches ErrInvalidSpec.
+type SpecError struct {
+	// Spec is the invalid spec.
+	Spec string
+	// Field is the position of the invalid field in the spec, starting at 0,
+	// or -1 if the spec is invalid as a whole.
+	Field int
+	// Err describes the problem.
+	Err error
+}
+
+func (e *SpecError) Error() string { return e.Err.Error() }
+
+func (e *SpecError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrInvalidSpec.
+func (e *SpecError) Is(target error) bool { return target == ErrInvalidSpec }
+
+// jobNotFound returns an ErrJobNotFound error for the job with the given id.
+func jobNotFound(id string) error {
+	return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	c := New(WithMaxEntries(1))
+	if err := c.Pause("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	if err := c.RunNow("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+
+	jc := JobConfig{Name: "a", Spec: "@hourly", Type: CommandJobType, Params: map[string]string{"command": "true"}}
+	if err := c.AddJobConfig(jc); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddJobConfig(jc); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("expected ErrDuplicateJob, got %v", err)
+	}
+	if err := c.AddJob("@hourly", NewShellCommandJob("b", "true")); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	// Replacing an entry does not count against the quota.
+	if err := c.AddJob("@daily", NewShellCommandJob("a", "true")); err != nil {
+		t.Errorf("expected the entry to be replaced, got %v", err)
+	}
+	if _, err := c.NextRun("a"); !errors.Is(err, ErrSchedulerStopped) {
+		t.Errorf("expected ErrSchedulerStopped, got %v", err)
+	}
+
+	events, cancel := c.SubscribeEvents(1)
+	defer cancel()
+	if err := c.Remove("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %+v for a missing entry", e)
+	default:
+	}
+	if err := c.Remove("a"); err != nil {
+		t.Errorf("expected the entry to be removed, got %v", err)
+	}
+}
+
+func TestSpecError(t *testing.T) {
+	tests := []struct {
+		parser Parser
+		spec   string
+		field  int
+	}{
+		{defaultParser, "0 61 * * * *", 1},
+		{defaultParser, "* * * * * MON-XYZ", 5},
+		{standardParser, "* 25 * * *", 1},
+		{defaultParser, "* * *", -1},
+		{defaultParser, "@unknown", -1},
+	}
+	for _, test := range tests {
+		_, err := test.parser.Parse(test.spec)
+		var specErr *SpecError
+		if !errors.Is(err, ErrInvalidSpec) || !errors.As(err, &specErr) {
+			t.Errorf("%s: expected a SpecError, got %v", test.spec, err)
+			continue
+		}
+		if specErr.Field != test.field || specErr.Spec != test.spec {
+			t.Errorf("%s: expected field %d, got %+v", test.spec, test.field, specErr)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Alert reports a job failing several times in a row.
+type Alert struct {
+	JobID string `json:"job_id"`
+	// Failures is the number of consecutive failed runs.
+	Failures int       `json:"failures"`
+	Error    string    `json:"error"`
+	Msg      string    `json:"msg,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Notifier delivers alerts, e.g. to a log, a chat or a pager.
+type Notifier interface {
+	Notify(a Alert) error
+}
+
+// NotifierFunc adapts a func to a Notifier.
+type NotifierFunc func(a Alert) error
+
+func (f NotifierFunc) Notify(a Alert) error { return f(a) }
+
+// LogNotifier writes alerts to l, or to the standard logger if nil.
+func LogNotifier(l *log.Logger) Notifier {
+	printf := log.Printf
+	if l != nil {
+		printf = l.Printf
+	}
+	return NotifierFunc(func(a Alert) error {
+		printf("cron: job %s failed %d times in a row: %s", a.JobID, a.Failures, a.Error)
+		return nil
+	})
+}
+
+// MailNotifier emails alerts to the recipients through m.
+func MailNotifier(m Mailer, to ...string) Notifier {
+	return NotifierFunc(func(a Alert) error {
+		subject := fmt.Sprintf("Cron job %s failed %d times in a row", a.JobID, a.Failures)
+		body := fmt.Sprintf("Job %s failed %d times in a row.\n\nLast error: %s\n", a.JobID, a.Failures, a.Error)
+		if a.Msg != "" {
+			body += "\nOutput:\n" + a.Msg + "\n"
+		}
+		return m.Send(to, subject, body)
+	})
+}
+
+// Notify posts the alert as JSON to every URL of the sink, signed and
+// retried like results.
+func (s *WebhookSink) Notify(a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	var first error
+	for _, url := range s.URLs {
+		if err := s.deliver(url, body); err != nil {
+			s.deadLetter(DeadLetter{URL: url, Payload: body, Error: err.Error(), Time: s.clock().Now()})
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// EscalationStep notifies Notifier when a job has failed Failures times in
+// a row. Values of Failures below 1 mean 1.
+type EscalationStep struct {
+	Failures int
+	Notifier Notifier
+}
+
+// EscalationPolicy routes the failures of jobs to notifiers with
+// escalation, e.g. logging the first failure, calling a webhook on the third
+// one in a row and paging on the tenth. Each step notifies once per streak
+// of failures; a successful run resets the streak.
+//
+// The steps of a job are those of its entry id in Entries, else those of
+// the first of its tags in Tags, else Default.
+type EscalationPolicy struct {
+	Default []EscalationStep
+	Entries map[string][]EscalationStep
+	Tags    map[string][]EscalationStep
+	// Clock times the alerts. It defaults to the system clock, or to the
+	// clock of the Cron for Attach.
+	Clock Clock
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// Attach subscribes the policy to the results of c. Calling detach stops
+// it.
+func (p *EscalationPolicy) Attach(c *Cron, buffer int) (detach func()) {
+	if p.Clock == nil {
+		p.Clock = c.clock
+	}
+	results, cancel := c.SubscribeResults(buffer)
+	go func() {
+		for r := range results {
+			var tags []string
+			if len(p.Tags) > 0 {
+				if e, ok := c.Entry(r.JobId); ok {
+					tags = e.Tags
+				}
+			}
+			if err := p.Notify(r, tags); err != nil {
+				c.entryErrorf(r.JobId, "cron: failed to notify failure of %s: %s", r.JobId, err)
+			}
+		}
+	}()
+	return cancel
+}
+
+// Notify records the result of a job with the given tags, and notifies the
+// step of its policy reached by the failure, if any.
+func (p *EscalationPolicy) Notify(r *JobResult, tags []string) error {
+	p.mu.Lock()
+	if p.failures == nil {
+		p.failures = make(map[string]int)
+	}
+	if r.Error == nil {
+		delete(p.failures, r.JobId)
+		p.mu.Unlock()
+		return nil
+	}
+	p.failures[r.JobId]++
+	n := p.failures[r.JobId]
+	p.mu.Unlock()
+	clock := p.Clock
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	var first error
+	for _, step := range p.steps(r.JobId, tags) {
+		if step.Failures != n && !(step.Failures < 1 && n == 1) {
+			continue
+		}
+		a := Alert{JobID: r.JobId, Failures: n, Error: r.Error.Error(), Msg: r.Msg, Time: clock.Now()}
+		if err := step.Notifier.Notify(a); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// steps returns the escalation steps of a job.
+func (p *EscalationPolicy) steps(id string, tags []string) []EscalationStep {
+	if steps, ok := p.Entries[id]; ok {
+		return steps
+	}
+	for _, tag := range tags {
+		if steps, ok := p.Tags[tag]; ok {
+			return steps
+		}
+	}
+	return p.Default
+}
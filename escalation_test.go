@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEscalationPolicy(t *testing.T) {
+	var logs bytes.Buffer
+	var pages []Alert
+	pager := NotifierFunc(func(a Alert) error {
+		pages = append(pages, a)
+		return nil
+	})
+	p := &EscalationPolicy{
+		Default: []EscalationStep{
+			{1, LogNotifier(log.New(&logs, "", 0))},
+			{3, pager},
+		},
+		Tags: map[string][]EscalationStep{"critical": {{2, pager}}},
+	}
+
+	fail := &JobResult{JobId: "a", Error: errors.New("boom")}
+	for _, r := range []*JobResult{fail, fail, {JobId: "a"}, fail, fail, fail, fail} {
+		p.Notify(r, nil)
+	}
+	if got := strings.Count(logs.String(), "cron: job a failed 1 times in a row: boom"); got != 2 {
+		t.Errorf("expected the first failure of each streak logged, got:\n%s", logs.String())
+	}
+	if len(pages) != 1 || pages[0].Failures != 3 {
+		t.Errorf("expected a page on the third failure in a row, got %+v", pages)
+	}
+
+	pages = nil
+	critical := &JobResult{JobId: "b", Error: errors.New("boom")}
+	p.Notify(critical, []string{"other", "critical"})
+	p.Notify(critical, []string{"other", "critical"})
+	if len(pages) != 1 || pages[0].JobID != "b" || pages[0].Failures != 2 {
+		t.Errorf("expected the critical steps to page on the second failure, got %+v", pages)
+	}
+}
+
+func TestWebhookSinkNotify(t *testing.T) {
+	var got Alert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(WebhookSignatureHeader) != SignWebhook([]byte("secret"), body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink("secret", srv.URL)
+	if err := s.Notify(Alert{JobID: "a", Failures: 3, Error: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.JobID != "a" || got.Failures != 3 {
+		t.Errorf("unexpected alert %+v", got)
+	}
+}
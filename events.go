@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies what happened in the scheduler.
+type EventType string
+
+const (
+	EventSchedulerStarted EventType = "scheduler_started"
+	EventSchedulerStopped EventType = "scheduler_stopped"
+	EventEntryAdded       EventType = "entry_added"
+	EventEntryRemoved     EventType = "entry_removed"
+	EventEntryPaused      EventType = "entry_paused"
+	EventEntryResumed     EventType = "entry_resumed"
+	EventJobStarted       EventType = "job_started"
+	// EventJobFinished carries the message and error of the run.
+	EventJobFinished EventType = "job_finished"
+	// EventJobDryRun is emitted instead of running a job in dry-run mode.
+	EventJobDryRun EventType = "job_dry_run"
+	// EventClockJumped is emitted when the scheduler wakes up much later or
+	// earlier than expected, e.g. after the system was suspended or its
+	// clock stepped.
+	EventClockJumped EventType = "clock_jumped"
+	// EventEntryCompleted is emitted when an entry is removed because it
+	// will not run again or expired.
+	EventEntryCompleted EventType = "entry_completed"
+	// EventJobSkipped is emitted instead of running a job, with the reason
+	// in Msg.
+	EventJobSkipped EventType = "job_skipped"
+	// EventBreakerOpened and EventBreakerClosed are emitted when the circuit
+	// breaker of an entry opens or closes.
+	EventBreakerOpened EventType = "breaker_opened"
+	EventBreakerClosed EventType = "breaker_closed"
+	// EventEntryDisabled is emitted when an entry is disabled for failing
+	// too often or quarantined, with the last error in Error.
+	EventEntryDisabled EventType = "entry_disabled"
+	EventEntryEnabled  EventType = "entry_enabled"
+	// EventSLAMissed is emitted when a run misses the SLA of its entry, at
+	// the deadline of the run.
+	EventSLAMissed EventType = "sla_missed"
+	// EventDurationAnomaly is emitted when the duration of a run deviates
+	// from the usual ones, see WithAnomalyDetection.
+	EventDurationAnomaly EventType = "duration_anomaly"
+	// EventCanaryPromoted and EventCanaryRolledBack are emitted when the
+	// canary of a changed job config replaces the old job or is dropped,
+	// with the compared outcomes in Msg.
+	EventCanaryPromoted   EventType = "canary_promoted"
+	EventCanaryRolledBack EventType = "canary_rolled_back"
+	// EventEntryReplaced is emitted when the definition of an entry is
+	// replaced or rolled back, with its version in Msg.
+	EventEntryReplaced EventType = "entry_replaced"
+	// EventJobPreempted is emitted when a run is cancelled to make room for
+	// a run of a higher priority.
+	EventJobPreempted EventType = "job_preempted"
+	// EventHandlerPanicked is emitted when the result handler panics on the
+	// result of a run, with the panic in Error.
+	EventHandlerPanicked EventType = "handler_panicked"
+)
+
+// Event describes a change of the scheduler or of one of its entries.
+type Event struct {
+	Type    EventType `json:"type"`
+	EntryID string    `json:"entry_id,omitempty"`
+	Time    time.Time `json:"time"`
+	Msg     string    `json:"msg,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// eventSubscribers fans events out to subscribers.
+type eventSubscribers struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// SubscribeEvents returns a channel receiving every event from now on, until
+// cancel is called. Like results, events are dropped for subscribers that
+// fall more than buffer events behind.
+func (c *Cron) SubscribeEvents(buffer int) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, buffer)
+	s := &c.events
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[chan Event]struct{})
+	}
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// emit stamps the event and hands it to every subscriber.
+func (c *Cron) emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = c.now()
+	}
+	s := &c.events
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeEvents(t *testing.T) {
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	events, cancel := cron.SubscribeEvents(10)
+	defer cancel()
+
+	cron.Start()
+	cron.Schedule(Every(time.Hour), testChanJob{"job", make(chan struct{}, 1)})
+	cron.Pause("job")
+	cron.RunNow("job")
+
+	expected := []Event{
+		{Type: EventSchedulerStarted},
+		{Type: EventEntryAdded, EntryID: "job"},
+		{Type: EventEntryPaused, EntryID: "job"},
+		{Type: EventJobStarted, EntryID: "job"},
+		{Type: EventJobFinished, EntryID: "job", Msg: "job"},
+	}
+	for _, want := range expected {
+		select {
+		case e := <-events:
+			if e.Type != want.Type || e.EntryID != want.EntryID || e.Msg != want.Msg || e.Time.IsZero() {
+				t.Errorf("got event %+v, want %+v", e, want)
+			}
+		case <-time.After(OneSecond):
+			t.Fatalf("expected a %s event", want.Type)
+		}
+	}
+
+	cron.RemoveJob("job")
+	cron.Stop()
+	for _, want := range []EventType{EventEntryRemoved, EventSchedulerStopped} {
+		if e := <-events; e.Type != want {
+			t.Errorf("got event %s, want %s", e.Type, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cron
+
+import (
+	"container/heap"
+	"time"
+)
+
+// OnceSchedule activates once, at the given time.
+type OnceSchedule struct {
+	At time.Time
+}
+
+// Once returns a Schedule that activates once at t. Its entry is removed
+// after it has run.
+func Once(t time.Time) OnceSchedule {
+	return OnceSchedule{At: t}
+}
+
+// Next returns the time of the schedule if it is after t, or the zero time.
+func (s OnceSchedule) Next(t time.Time) time.Time {
+	if s.At.After(t) {
+		return s.At
+	}
+	return time.Time{}
+}
+
+// EndSchedule activates like its Schedule, until its End time.
+type EndSchedule struct {
+	Schedule Schedule
+	End      time.Time
+}
+
+// Until returns a Schedule that activates like s, but never after end. Its
+// entry is removed once its last run is done.
+func Until(s Schedule, end time.Time) EndSchedule {
+	return EndSchedule{Schedule: s, End: end}
+}
+
+// Next returns the next time of the Schedule, or the zero time if it is
+// after the end.
+func (s EndSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t)
+	if next.After(s.End) {
+		return time.Time{}
+	}
+	return next
+}
+
+// SetMaxRuns limits the number of times the scheduler runs the job with the
+// given id. The entry is removed once it has run n times. Zero means no
+// limit.
+func (c *Cron) SetMaxRuns(id string, n int) (err error) {
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		e.MaxRuns = n
+		if c.running {
+			e.Next = nextRun(e, c.now())
+			c.requeue(e)
+		}
+	})
+	return err
+}
+
+// EntryOption configures an entry when it is added.
+type EntryOption func(*Entry)
+
+// WithTTL makes the entry expire d after it is added: it is then removed,
+// whatever its schedule, and an EventEntryCompleted is emitted.
+func WithTTL(d time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.ttl = d
+	}
+}
+
+// expired reports whether e expired by now.
+func (e *Entry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// due returns when the run loop has to handle e next: at its next run, or
+// when it expires if that is earlier.
+func (e *Entry) due() time.Time {
+	if e.Expires.IsZero() || e.Next.IsZero() || e.Next.Before(e.Expires) {
+		return e.Next
+	}
+	return e.Expires
+}
+
+// exhausted reports whether e has run as many times as it may.
+func (e *Entry) exhausted() bool {
+	return e.MaxRuns > 0 && e.Runs >= e.MaxRuns
+}
+
+// complete removes e, which will not run again or expired. It must be
+// called in the run loop.
+func (c *Cron) complete(e *Entry) {
+	id := e.Job.ID()
+	heap.Remove(&c.queue, e.index)
+	delete(c.entries, id)
+	c.setLoggers(id, nil)
+	c.changed(ChangeRemoved, id, nil)
+	c.emit(Event{Type: EventEntryCompleted, EntryID: id})
+}
@@ -0,0 +1,130 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntriesComplete(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	runs := make(map[string]int)
+	job := func(id string) Job {
+		return FuncJob(func() (string, error) {
+			runs[id]++
+			return "", nil
+		})
+	}
+	add := func(id string, s Schedule) {
+		if err := c.addEntry(&Entry{Schedule: s, Job: &funcJob{id, job(id).(FuncJob)}}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("once", Once(start.Add(90*time.Second)))
+	add("past", Once(start.Add(-time.Hour)))
+	add("until", Until(Every(time.Minute), start.Add(3*time.Minute)))
+	add("limited", Every(time.Minute))
+	add("forever", Every(time.Minute))
+	if err := c.SetMaxRuns("limited", 2); err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(10 * time.Minute)
+	want := map[string]int{"once": 1, "until": 3, "limited": 2, "forever": 10}
+	for id, n := range want {
+		if runs[id] != n {
+			t.Errorf("expected %d runs of %s, got %d", n, id, runs[id])
+		}
+	}
+	if runs["past"] != 0 {
+		t.Errorf("expected no run of past, got %d", runs["past"])
+	}
+	entries := c.Entries()
+	if len(entries) != 1 || entries[0].ID != "forever" {
+		t.Errorf("expected only forever to be left, got %v", entries)
+	}
+	if entries[0].Runs != 10 {
+		t.Errorf("expected forever to count 10 runs, got %d", entries[0].Runs)
+	}
+
+	completed := make(map[string]bool)
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventEntryCompleted {
+			completed[e.EntryID] = true
+		}
+	}
+	for _, id := range []string{"once", "past", "until", "limited"} {
+		if !completed[id] {
+			t.Errorf("expected %s to complete", id)
+		}
+	}
+}
+
+func TestSetMaxRunsRunning(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	id, _ := c.AddFunc("@every 1m", func() (string, error) { return "", nil })
+	c.Start()
+	defer c.Stop()
+	clock.Advance(3 * time.Minute)
+	if err := c.SetMaxRuns(id, 2); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Second)
+	if _, ok := c.Entry(id); ok {
+		t.Error("expected the entry to be removed once over its limit")
+	}
+	if err := c.SetMaxRuns(id, 1); err == nil {
+		t.Error("expected an error for a removed entry")
+	}
+}
+
+func TestWithTTL(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	runs := 0
+	id, err := c.AddFunc("0 0 * * * *", func() (string, error) {
+		runs++
+		return "", nil
+	}, WithTTL(150*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := c.Entry(id)
+	if want := start.Add(150 * time.Minute); !e.Expires.Equal(want) {
+		t.Errorf("expected the entry to expire at %v, got %v", want, e.Expires)
+	}
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(2 * time.Hour)
+	if _, ok := c.Entry(id); !ok || runs != 2 {
+		t.Fatalf("expected the entry to be there after 2 runs, got %d runs", runs)
+	}
+	// Removed at 2:30, before its next run.
+	clock.Advance(30 * time.Minute)
+	if _, ok := c.Entry(id); ok {
+		t.Error("expected the entry to expire")
+	}
+	clock.Advance(time.Hour)
+	if runs != 2 {
+		t.Errorf("expected 2 runs, got %d", runs)
+	}
+	completed := false
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventEntryCompleted && e.EntryID == id {
+			completed = e.Time.Equal(start.Add(150 * time.Minute))
+		}
+	}
+	if !completed {
+		t.Error("expected the entry to complete when it expired")
+	}
+}
@@ -0,0 +1,186 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+)
+
+// WithNamespace puts the entry in a namespace, e.g. the tenant it runs for.
+func WithNamespace(ns string) EntryOption {
+	return func(e *Entry) {
+		e.Namespace = ns
+	}
+}
+
+// WithNamespaceWeight sets the weight of a namespace, 1 by default. When
+// runs wait for a slot under WithMaxConcurrent, the slots are given to the
+// namespaces in weighted round-robin, up to weight runs of a namespace in a
+// row, rather than in the order the runs were due, so that a burst of runs
+// in one namespace does not delay the others.
+func WithNamespaceWeight(ns string, weight int) Option {
+	return func(c *Cron) {
+		if c.weights == nil {
+			c.weights = make(map[string]int)
+		}
+		c.weights[ns] = weight
+	}
+}
+
+// fairQueue is a semaphore sharing its slots fairly between the namespaces
+// of the runs waiting for one. Runs with a priority are given a slot first,
+// and may preempt the preemptible runs of a lower priority.
+type fairQueue struct {
+	weights map[string]int
+	// preempted is called with the id of each run preempted.
+	preempted func(id string)
+
+	mu      sync.Mutex
+	free    int
+	waiting map[string][]*queuedRun
+	// order holds the namespaces with waiting runs, served in turn; the
+	// one at next has been given credit slots in a row.
+	order  []string
+	next   int
+	credit int
+	// urgent holds the waiting runs with a priority, highest first.
+	urgent []*queuedRun
+	// running holds the preemptible runs holding a slot.
+	running map[*queuedRun]struct{}
+}
+
+func newFairQueue(n int, weights map[string]int) *fairQueue {
+	return &fairQueue{
+		weights: weights,
+		free:    n,
+		waiting: make(map[string][]*queuedRun),
+		running: make(map[*queuedRun]struct{}),
+	}
+}
+
+// pending returns the number of runs waiting for a slot.
+func (q *fairQueue) pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.urgent)
+	for _, runs := range q.waiting {
+		n += len(runs)
+	}
+	return n
+}
+
+// acquire waits for a slot for a run in the namespace ns.
+func (q *fairQueue) acquire(ns string) {
+	q.wait(&queuedRun{namespace: ns})
+}
+
+// wait waits for a slot for r. A run with a priority jumps ahead of the
+// runs waiting without one, and preempts the lowest priority preemptible
+// run holding a slot if its priority is lower.
+func (q *fairQueue) wait(r *queuedRun) {
+	q.mu.Lock()
+	if q.free > 0 {
+		q.free--
+		q.hold(r)
+		q.mu.Unlock()
+		return
+	}
+	r.ready = make(chan struct{})
+	var victim *queuedRun
+	if r.priority > 0 {
+		i := sort.Search(len(q.urgent), func(i int) bool {
+			return q.urgent[i].priority < r.priority
+		})
+		q.urgent = append(q.urgent, nil)
+		copy(q.urgent[i+1:], q.urgent[i:])
+		q.urgent[i] = r
+		if victim = q.victim(r.priority); victim != nil {
+			victim.preempt()
+		}
+	} else {
+		if len(q.waiting[r.namespace]) == 0 {
+			q.order = append(q.order, r.namespace)
+		}
+		q.waiting[r.namespace] = append(q.waiting[r.namespace], r)
+	}
+	q.mu.Unlock()
+	if victim != nil && q.preempted != nil {
+		q.preempted(victim.id)
+	}
+	<-r.ready
+}
+
+// hold records r as holding a slot. q.mu must be held.
+func (q *fairQueue) hold(r *queuedRun) {
+	if r.cancel != nil {
+		q.running[r] = struct{}{}
+	}
+}
+
+// victim returns the preemptible run to cancel for a run of the given
+// priority, the one of the lowest priority, or nil if there is none.
+func (q *fairQueue) victim(priority int) *queuedRun {
+	var victim *queuedRun
+	for r := range q.running {
+		if r.priority < priority && !r.isPreempted() &&
+			(victim == nil || r.priority < victim.priority) {
+			victim = r
+		}
+	}
+	return victim
+}
+
+// leave frees the slot of r, handing it over to the next waiting run if any.
+func (q *fairQueue) leave(r *queuedRun) {
+	q.mu.Lock()
+	delete(q.running, r)
+	q.mu.Unlock()
+	q.release()
+}
+
+// release frees a slot, handing it over to the next waiting run if any.
+func (q *fairQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.urgent) > 0 {
+		r := q.urgent[0]
+		q.urgent[0] = nil
+		q.urgent = q.urgent[1:]
+		q.hold(r)
+		close(r.ready)
+		return
+	}
+	if len(q.order) == 0 {
+		q.free++
+		return
+	}
+	ns := q.order[q.next]
+	runs := q.waiting[ns]
+	r := runs[0]
+	runs[0] = nil
+	runs = runs[1:]
+	q.credit++
+	switch {
+	case len(runs) == 0:
+		delete(q.waiting, ns)
+		q.order = append(q.order[:q.next], q.order[q.next+1:]...)
+		q.credit = 0
+	case q.credit >= q.weight(ns):
+		q.waiting[ns] = runs
+		q.next++
+		q.credit = 0
+	default:
+		q.waiting[ns] = runs
+	}
+	if q.next >= len(q.order) {
+		q.next = 0
+	}
+	q.hold(r)
+	close(r.ready)
+}
+
+func (q *fairQueue) weight(ns string) int {
+	if w, ok := q.weights[ns]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFairQueue(t *testing.T) {
+	q := newFairQueue(1, map[string]int{"a": 2})
+	q.acquire("")
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	waiters := 0
+	for _, run := range []string{"a1", "a2", "a3", "a4", "a5", "b1", "b2", "c1"} {
+		run := run
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.acquire(run[:1])
+			mu.Lock()
+			order = append(order, run)
+			mu.Unlock()
+			q.release()
+		}()
+		// Queue the runs in order.
+		waiters++
+		for deadline := time.Now().Add(time.Second); q.queued() < waiters; {
+			if time.Now().After(deadline) {
+				t.Fatal("run not queued")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	q.release()
+	wg.Wait()
+	if got, want := strings.Join(order, " "), "a1 a2 b1 c1 a3 a4 b2 a5"; got != want {
+		t.Errorf("expected the runs in order %s, got %s", want, got)
+	}
+	if q.free != 1 {
+		t.Errorf("expected the slot to be free, got %d", q.free)
+	}
+}
+
+func (q *fairQueue) queued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, runs := range q.waiting {
+		n += len(runs)
+	}
+	return n
+}
+
+func TestWithNamespace(t *testing.T) {
+	c := New(WithMaxConcurrent(1), WithNamespaceWeight("tenant", 3))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	id, _ := c.AddFunc("@yearly", func() (string, error) {
+		wg.Done()
+		return "", nil
+	}, WithNamespace("tenant"))
+	if e, _ := c.Entry(id); e.Namespace != "tenant" {
+		t.Errorf("expected the entry in namespace tenant, got %q", e.Namespace)
+	}
+	c.RunNow(id)
+	c.RunNow(id)
+	wg.Wait()
+}
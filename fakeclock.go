@@ -0,0 +1,209 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock for tests whose time only moves when Advance is
+// called. A Cron using it runs its jobs synchronously in its run loop, so
+// when Advance returns every job due by then has run and its result was
+// handled. Such jobs must not call back into the Cron.
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+	// pending counts the run loops of Crons not waiting on a timer.
+	pending int
+}
+
+// NewFakeClock returns a FakeClock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer firing once Advance moves the clock by d.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.newTimer(d, false)
+}
+
+// Set moves the clock to t, firing the timers due in order. It panics if t
+// is before the current time.
+func (c *FakeClock) Set(t time.Time) {
+	d := t.Sub(c.Now())
+	if d < 0 {
+		panic("cron: FakeClock moved backwards")
+	}
+	c.Advance(d)
+}
+
+// Advance moves the clock forward by d. The timers due are fired one at a
+// time in order, with the clock set to their deadline, and Advance waits for
+// a Cron to be done with each firing before the next one.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target := c.now.Add(d)
+	for {
+		for c.pending > 0 {
+			c.cond.Wait()
+		}
+		if len(c.timers) == 0 || c.timers[0].deadline.After(target) {
+			break
+		}
+		t := c.timers[0]
+		c.remove(t)
+		if t.deadline.After(c.now) {
+			c.now = t.deadline
+		}
+		if !t.scheduler {
+			select {
+			case t.c <- c.now:
+			default:
+			}
+			continue
+		}
+		// The run loop is busy until it waits on its timer again.
+		c.pending++
+		now, stopped := c.now, t.stopped
+		c.mu.Unlock()
+		select {
+		case t.c <- now:
+		case <-stopped:
+		}
+		c.mu.Lock()
+	}
+	c.now = target
+}
+
+// Jump moves the clock by d, which may be negative, without firing any
+// timer, like the system being suspended or its clock being stepped. The
+// timers keep the time they had left, so they fire late or early by d.
+func (c *FakeClock) Jump(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.pending > 0 {
+		c.cond.Wait()
+	}
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		t.deadline = t.deadline.Add(d)
+	}
+}
+
+func (c *FakeClock) newTimer(d time.Duration, scheduler bool) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{
+		clock:     c,
+		deadline:  c.now.Add(d),
+		scheduler: scheduler,
+		stopped:   make(chan struct{}),
+	}
+	if scheduler {
+		t.c = make(chan time.Time)
+		c.setPending(-1)
+	} else {
+		t.c = make(chan time.Time, 1)
+	}
+	c.insert(t)
+	return t
+}
+
+// insert adds t to the timers, which are ordered by deadline.
+func (c *FakeClock) insert(t *fakeTimer) {
+	i := sort.Search(len(c.timers), func(i int) bool {
+		return c.timers[i].deadline.After(t.deadline)
+	})
+	c.timers = append(c.timers, nil)
+	copy(c.timers[i+1:], c.timers[i:])
+	c.timers[i] = t
+}
+
+// remove removes t from the timers, reporting whether it was there.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// setPending adjusts the count of busy run loops, waking up Advance.
+func (c *FakeClock) setPending(delta int) {
+	c.pending += delta
+	c.cond.Broadcast()
+}
+
+// startScheduler and stopScheduler track the run loop of a Cron, which is
+// busy until it creates its first timer.
+func (c *FakeClock) startScheduler() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setPending(1)
+}
+
+func (c *FakeClock) stopScheduler() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setPending(-1)
+}
+
+// fakeTimer is a timer of a FakeClock. Its fields other than c are guarded
+// by the mutex of the clock.
+type fakeTimer struct {
+	clock     *FakeClock
+	deadline  time.Time
+	scheduler bool
+	c         chan time.Time
+	// stopped is closed when the timer is stopped, cancelling a firing in
+	// progress.
+	stopped chan struct{}
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-t.stopped:
+	default:
+		close(t.stopped)
+	}
+	if !c.remove(t) {
+		return false
+	}
+	if t.scheduler {
+		c.setPending(1)
+	}
+	return true
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.remove(t)
+	t.deadline = c.now.Add(d)
+	t.stopped = make(chan struct{})
+	c.insert(t)
+	if !active && t.scheduler {
+		c.setPending(-1)
+	}
+	return active
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockAdvance(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	c.location = time.UTC
+	var hourly, weekdays int
+	var results int
+	c.AddResultHandler(func(*JobResult) { results++ })
+	c.AddFunc("0 0 * * * *", func() (string, error) {
+		hourly++
+		return "", nil
+	})
+	c.AddFunc("0 30 9 * * MON-FRI", func() (string, error) {
+		weekdays++
+		return "", nil
+	})
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(4 * 7 * 24 * time.Hour)
+	if hourly != 4*7*24 {
+		t.Errorf("expected %d hourly runs, got %d", 4*7*24, hourly)
+	}
+	if weekdays != 4*5 {
+		t.Errorf("expected %d weekday runs, got %d", 4*5, weekdays)
+	}
+	if results != hourly+weekdays {
+		t.Errorf("expected %d results, got %d", hourly+weekdays, results)
+	}
+	if want := time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC); !clock.Now().Equal(want) {
+		t.Errorf("expected the clock at %v, got %v", want, clock.Now())
+	}
+}
+
+func TestFakeClockTimer(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	timer := clock.NewTimer(time.Minute)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("expected the timer not to fire yet")
+	default:
+	}
+	clock.Advance(time.Minute)
+	select {
+	case now := <-timer.C():
+		if want := time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC); !now.Equal(want) {
+			t.Errorf("expected the timer to fire at %v, got %v", want, now)
+		}
+	default:
+		t.Fatal("expected the timer to fire")
+	}
+	if timer.Stop() {
+		t.Error("expected Stop to report the timer already fired")
+	}
+}
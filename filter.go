@@ -0,0 +1,160 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EntryFilter selects entries for EntriesFiltered. The zero value of each
+// field matches every entry.
+type EntryFilter struct {
+	// Tag selects the entries with this tag.
+	Tag string
+	// Namespace selects the entries of this namespace.
+	Namespace string
+	// Status selects the entries with this status.
+	Status EntryStatus
+	// NextAfter and NextBefore select the entries whose next run is at or
+	// after NextAfter, and before NextBefore.
+	NextAfter  time.Time
+	NextBefore time.Time
+
+	// Sort is the order of the entries, by next run time by default, and
+	// Descending reverses it. Entries that sort equally are ordered by
+	// next run time.
+	Sort       EntrySort
+	Descending bool
+
+	// Offset skips the first matching entries, and Limit bounds the number
+	// of entries returned, if positive.
+	Offset int
+	Limit  int
+}
+
+// EntrySort is an order of the entries listed by EntriesFiltered.
+type EntrySort string
+
+const (
+	// SortByNext orders the entries by next run time, the entries that
+	// will not run again last.
+	SortByNext EntrySort = "next"
+	// SortByName orders the entries by name, or id if they have none.
+	SortByName EntrySort = "name"
+	// SortByLastDuration orders the entries by the duration of their last
+	// run.
+	SortByLastDuration EntrySort = "last_duration"
+	// SortByFailures orders the entries by number of failed runs, e.g.
+	// descending for the most failing first.
+	SortByFailures EntrySort = "failures"
+	// SortByTag orders the entries by their first tag, the entries without
+	// tags first.
+	SortByTag EntrySort = "tag"
+)
+
+// match reports whether the entry e matches the filter, except for its
+// status.
+func (f *EntryFilter) match(e *Entry) bool {
+	switch {
+	case f.Tag != "" && !hasTag(e.Tags, f.Tag):
+		return false
+	case f.Namespace != "" && e.Namespace != f.Namespace:
+		return false
+	case !f.NextAfter.IsZero() && e.Next.Before(f.NextAfter):
+		return false
+	case !f.NextBefore.IsZero() && !e.Next.Before(f.NextBefore):
+		return false
+	}
+	return true
+}
+
+// EntriesFiltered returns a snapshot of the entries matching the filter, in
+// the order of the filter, and the number of matching entries before
+// applying the offset and limit of the filter. It fails for an unknown
+// order. Only the
+// returned entries are copied, so that a page can be listed cheaply among
+// many entries.
+func (c *Cron) EntriesFiltered(filter EntryFilter) (entries []*Entry, total int, err error) {
+	c.do(func() {
+		matched := make([]*Entry, 0)
+		ids := make(map[*Entry]string)
+		for id, e := range c.entries {
+			if !filter.match(e) {
+				continue
+			}
+			if filter.Status != "" && c.copyEntry(id, e).Status != filter.Status {
+				continue
+			}
+			matched = append(matched, e)
+			ids[e] = id
+		}
+		if err = c.sortEntries(matched, ids, filter.Sort, filter.Descending); err != nil {
+			return
+		}
+		total = len(matched)
+		if filter.Offset > 0 {
+			if filter.Offset > len(matched) {
+				filter.Offset = len(matched)
+			}
+			matched = matched[filter.Offset:]
+		}
+		if filter.Limit > 0 && filter.Limit < len(matched) {
+			matched = matched[:filter.Limit]
+		}
+		entries = make([]*Entry, len(matched))
+		for i, e := range matched {
+			entries[i] = c.copyEntry(ids[e], e)
+		}
+	})
+	return entries, total, err
+}
+
+// sortEntries sorts the entries with the given ids in the given order. It
+// must be called through do.
+func (c *Cron) sortEntries(entries []*Entry, ids map[*Entry]string, by EntrySort, descending bool) error {
+	var less func(a, b *Entry) bool
+	switch by {
+	case "", SortByNext:
+	case SortByName:
+		name := func(e *Entry) string {
+			if e.Name != "" {
+				return e.Name
+			}
+			return ids[e]
+		}
+		less = func(a, b *Entry) bool { return name(a) < name(b) }
+	case SortByLastDuration, SortByFailures:
+		stats := make(map[*Entry]EntryStats, len(entries))
+		for _, e := range entries {
+			stats[e] = c.Stats(ids[e])
+		}
+		if by == SortByFailures {
+			less = func(a, b *Entry) bool { return stats[a].Failures < stats[b].Failures }
+		} else {
+			less = func(a, b *Entry) bool { return stats[a].LastDuration < stats[b].LastDuration }
+		}
+	case SortByTag:
+		tag := func(e *Entry) string {
+			if len(e.Tags) == 0 {
+				return ""
+			}
+			return e.Tags[0]
+		}
+		less = func(a, b *Entry) bool { return tag(a) < tag(b) }
+	default:
+		return fmt.Errorf("Unknown entry sort %q", by)
+	}
+
+	sort.Sort(byTime(entries))
+	switch {
+	case less == nil && descending:
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	case less != nil && descending:
+		sort.SliceStable(entries, func(i, j int) bool { return less(entries[j], entries[i]) })
+	case less != nil:
+		sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEntriesFiltered(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	for i := 1; i <= 6; i++ {
+		opts := []EntryOption{WithNamespace(fmt.Sprint("ns", i%2))}
+		if i <= 3 {
+			opts = append(opts, WithTags("db"))
+		}
+		c.Schedule(Every(time.Duration(i)*time.Hour), &chainJob{id: fmt.Sprint("job", i)}, opts...)
+	}
+	c.Start()
+	defer c.Stop()
+	c.Pause("job2")
+
+	ids := func(entries []*Entry) (s string) {
+		for _, e := range entries {
+			s += e.ID + " "
+		}
+		return s
+	}
+	for _, test := range []struct {
+		filter EntryFilter
+		ids    string
+		total  int
+	}{
+		{EntryFilter{}, "job1 job2 job3 job4 job5 job6 ", 6},
+		{EntryFilter{Tag: "db"}, "job1 job2 job3 ", 3},
+		{EntryFilter{Namespace: "ns0"}, "job2 job4 job6 ", 3},
+		{EntryFilter{Status: EntryPaused}, "job2 ", 1},
+		{EntryFilter{Tag: "db", Status: EntryScheduled}, "job1 job3 ", 2},
+		{EntryFilter{NextAfter: clock.Now().Add(2 * time.Hour), NextBefore: clock.Now().Add(5 * time.Hour)}, "job2 job3 job4 ", 3},
+		{EntryFilter{Offset: 2, Limit: 3}, "job3 job4 job5 ", 6},
+		{EntryFilter{Namespace: "ns1", Offset: 2}, "job5 ", 3},
+		{EntryFilter{Offset: 10}, "", 6},
+		{EntryFilter{Tag: "web"}, "", 0},
+	} {
+		entries, total, _ := c.EntriesFiltered(test.filter)
+		if got := ids(entries); got != test.ids || total != test.total {
+			t.Errorf("%+v: expected %q of %d, got %q of %d", test.filter, test.ids, test.total, got, total)
+		}
+	}
+}
+
+func TestEntriesSorted(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	for i, e := range []struct {
+		name, tag string
+		failures  int
+		duration  time.Duration
+	}{
+		{"delta", "web", 0, 3 * time.Second},
+		{"alpha", "", 2, time.Second},
+		{"charlie", "db", 5, 2 * time.Second},
+		{"", "db", 1, 0},
+	} {
+		id := fmt.Sprint("job", i+1)
+		c.Schedule(Every(time.Duration(i+1)*time.Hour), &chainJob{id: id})
+		c.entries[id].Name = e.name
+		if e.tag != "" {
+			c.entries[id].Tags = []string{e.tag}
+		}
+		start := clock.Now()
+		for n := 0; n < e.failures; n++ {
+			c.history.record(id, start, start.Add(e.duration), 0, "", errors.New("failed"), Usage{})
+		}
+		c.history.record(id, start, start.Add(e.duration), 0, "", nil, Usage{})
+	}
+	c.Start()
+	defer c.Stop()
+
+	for _, test := range []struct {
+		by         EntrySort
+		descending bool
+		ids        string
+	}{
+		{"", false, "job1 job2 job3 job4 "},
+		{SortByNext, true, "job4 job3 job2 job1 "},
+		{SortByName, false, "job2 job3 job1 job4 "},
+		{SortByLastDuration, false, "job4 job2 job3 job1 "},
+		{SortByFailures, true, "job3 job2 job4 job1 "},
+		{SortByTag, false, "job2 job3 job4 job1 "},
+		{SortByTag, true, "job1 job3 job4 job2 "},
+	} {
+		entries, _, err := c.EntriesFiltered(EntryFilter{Sort: test.by, Descending: test.descending})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		for _, e := range entries {
+			got += e.ID + " "
+		}
+		if got != test.ids {
+			t.Errorf("sorted by %q descending %v: expected %q, got %q", test.by, test.descending, test.ids, got)
+		}
+	}
+	if _, _, err := c.EntriesFiltered(EntryFilter{Sort: "color"}); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+}
@@ -0,0 +1,145 @@
+package cron
+
+import "time"
+
+// FiscalCalendar divides fiscal years into periods.
+type FiscalCalendar interface {
+	// PeriodStarts returns the first day of each period of the fiscal year,
+	// in increasing order. The periods of a fiscal year must start after
+	// the ones of the previous fiscal year. Only the dates are used.
+	PeriodStarts(year int) []time.Time
+}
+
+// WeekCalendar is a FiscalCalendar of periods made of whole weeks, such as
+// the 4-4-5 calendar. Fiscal year N starts on the first Weekday on or after
+// Month Day of year N, so it has 52 or 53 weeks; the extra week of 53 week
+// years goes to the last period.
+type WeekCalendar struct {
+	Month   time.Month
+	Day     int
+	Weekday time.Weekday
+
+	// Pattern is the number of weeks of consecutive periods, repeated until
+	// it covers 52 weeks, e.g. 4, 4, 5. Without weeks, the fiscal year is a
+	// single period.
+	Pattern []int
+}
+
+// FourFourFive returns the 4-4-5 WeekCalendar whose fiscal years start on
+// the first weekday on or after month day.
+func FourFourFive(month time.Month, day int, weekday time.Weekday) WeekCalendar {
+	return WeekCalendar{month, day, weekday, []int{4, 4, 5}}
+}
+
+// start returns the first day of the fiscal year.
+func (c WeekCalendar) start(year int) time.Time {
+	t := time.Date(year, c.Month, c.Day, 0, 0, 0, 0, time.UTC)
+	return t.AddDate(0, 0, (int(c.Weekday)-int(t.Weekday())+7)%7)
+}
+
+// PeriodStarts returns the first day of each period of the fiscal year.
+func (c WeekCalendar) PeriodStarts(year int) []time.Time {
+	var (
+		total int
+		weeks int
+		day   = c.start(year)
+	)
+	for _, n := range c.Pattern {
+		total += n
+	}
+	if total <= 0 {
+		return []time.Time{day}
+	}
+	starts := []time.Time{day}
+	for i := 0; weeks+c.Pattern[i%len(c.Pattern)] < 52; i++ {
+		weeks += c.Pattern[i%len(c.Pattern)]
+		starts = append(starts, day.AddDate(0, 0, 7*weeks))
+	}
+	return starts
+}
+
+// MonthCalendar is a FiscalCalendar of periods made of whole months. Fiscal
+// year N starts on the first day of FirstMonth of year N.
+type MonthCalendar struct {
+	FirstMonth time.Month
+
+	// Months is the number of months of consecutive periods, e.g. 3, 3, 3,
+	// 3 for quarters. Nil means 12 periods of one month.
+	Months []int
+}
+
+// PeriodStarts returns the first day of each period of the fiscal year.
+func (c MonthCalendar) PeriodStarts(year int) []time.Time {
+	months := c.Months
+	if months == nil {
+		months = []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	}
+	first := c.FirstMonth
+	if first == 0 {
+		first = time.January
+	}
+	var (
+		starts []time.Time
+		offset int
+	)
+	for _, n := range months {
+		if offset >= 12 {
+			break
+		}
+		starts = append(starts, time.Date(year, first+time.Month(offset), 1, 0, 0, 0, 0, time.UTC))
+		offset += n
+	}
+	return starts
+}
+
+// FiscalSchedule activates on the first day of the periods of a fiscal
+// calendar, e.g. for the jobs closing or opening a fiscal period.
+type FiscalSchedule struct {
+	Calendar FiscalCalendar
+
+	// Periods restricts the schedule to the periods with these numbers,
+	// counted from 1 in each fiscal year. Nil means every period.
+	Periods []int
+
+	// Offset is added to the midnight starting the period, e.g. 9 hours
+	// to run at 9am, or 2 days to run on the third day of the period.
+	Offset time.Duration
+
+	// Location is the time zone of the days of the calendar. If nil, the
+	// location of the time passed to Next is used.
+	Location *time.Location
+}
+
+// Next returns the first activation time after t, or the zero time if there
+// is none within five years.
+func (s *FiscalSchedule) Next(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = t.Location()
+	}
+	for year := t.Year() - 1; year <= t.Year()+5; year++ {
+		for i, start := range s.Calendar.PeriodStarts(year) {
+			if !s.includes(i + 1) {
+				continue
+			}
+			at := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc).Add(s.Offset)
+			if at.After(t) {
+				return at.In(t.Location())
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// includes reports whether the schedule activates in the given period.
+func (s *FiscalSchedule) includes(period int) bool {
+	if s.Periods == nil {
+		return true
+	}
+	for _, p := range s.Periods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
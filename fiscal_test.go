@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalSchedule(t *testing.T) {
+	// Fiscal 2012 starts on Sunday, January 1st 2012 and has 53 weeks.
+	retail := FourFourFive(time.January, 1, time.Sunday)
+	quarters := MonthCalendar{FirstMonth: time.February, Months: []int{3, 3, 3, 3}}
+	tests := []struct {
+		time     string
+		schedule *FiscalSchedule
+		expected string
+	}{
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: retail, Offset: 9 * time.Hour}, "Sun Jul 29 09:00 2012"},
+		{"Sun Jul 29 09:00 2012", &FiscalSchedule{Calendar: retail, Offset: 9 * time.Hour}, "Sun Aug 26 09:00 2012"},
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: retail, Periods: []int{1}}, "Sun Jan 6 00:00 2013"},
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: retail, Periods: []int{4, 10}}, "Sun Sep 30 00:00 2012"},
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: quarters}, "Wed Aug 1 00:00 2012"},
+		{"Sat Dec 1 00:00 2012", &FiscalSchedule{Calendar: quarters, Offset: 48 * time.Hour}, "Sun Feb 3 00:00 2013"},
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: MonthCalendar{}}, "Wed Aug 1 00:00 2012"},
+		{"Mon Jul 9 14:45 2012", &FiscalSchedule{Calendar: retail, Periods: []int{13}}, ""},
+	}
+	for _, test := range tests {
+		actual := test.schedule.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, %+v: (expected) %v != %v (actual)", test.time, test.schedule, expected, actual)
+		}
+	}
+
+	if starts := retail.PeriodStarts(2012); len(starts) != 12 || !starts[11].Equal(time.Date(2012, 11, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 12 periods, the last one starting on November 25th, got %v", starts)
+	}
+}
@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WithTagLimit limits the number of jobs tagged with tag running at the same
+// time to n, independently of WithMaxConcurrent. Runs due while the limit
+// is reached wait for another job of the group to finish.
+func WithTagLimit(tag string, n int) Option {
+	return func(c *Cron) {
+		if n <= 0 {
+			return
+		}
+		i := sort.Search(len(c.groups), func(i int) bool { return c.groups[i].tag >= tag })
+		g := tagGroup{tag, make(chan struct{}, n)}
+		if i < len(c.groups) && c.groups[i].tag == tag {
+			c.groups[i] = g
+			return
+		}
+		c.groups = append(c.groups, tagGroup{})
+		copy(c.groups[i+1:], c.groups[i:])
+		c.groups[i] = g
+	}
+}
+
+// WithResources declares the named resources the job of the entry holds
+// while it runs. Jobs sharing a resource never run at the same time: a run
+// due while another job holds one of its resources waits for it.
+func WithResources(names ...string) EntryOption {
+	return func(e *Entry) {
+		e.Resources = sortedResources(names)
+	}
+}
+
+// sortedResources returns a sorted copy of the resource names, without
+// duplicates.
+func sortedResources(names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	out := append([]string(nil), names...)
+	sort.Strings(out)
+	n := 1
+	for _, name := range out[1:] {
+		if name != out[n-1] {
+			out[n] = name
+			n++
+		}
+	}
+	return out[:n]
+}
+
+// tagGroup is a semaphore limiting the runs of the jobs with a tag.
+type tagGroup struct {
+	tag string
+	sem chan struct{}
+}
+
+// slots are what a run waits for before it starts: a slot in the groups of
+// its tags, its resources, and a slot under WithMaxConcurrent shared by
+// namespace.
+type slots struct {
+	tags        []string
+	resources   []string
+	namespace   string
+	priority    int
+	preemptible bool
+	retries     int
+	retryDelay  time.Duration
+	onPanic     func(p Panic) error
+	// ctx is the context of the Cron when the run was dispatched, read once
+	// so that the run does not see the one of a later start.
+	ctx context.Context
+}
+
+// slots returns the slots of a run of e dispatched with the context ctx of
+// the Cron.
+func (e *Entry) slots(ctx context.Context) slots {
+	return slots{e.Tags, e.Resources, e.Namespace, e.Priority, e.Preemptible, e.Retries, e.RetryDelay, e.PanicHandler, ctx}
+}
+
+// context returns the context of the run, defaulting to the background
+// context.
+func (s slots) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// resourceLocks holds a lock per resource name, created on first use.
+type resourceLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+func (r *resourceLocks) lock(name string) chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.locks == nil {
+		r.locks = make(map[string]chan struct{})
+	}
+	l, ok := r.locks[name]
+	if !ok {
+		l = make(chan struct{}, 1)
+		r.locks[name] = l
+	}
+	return l
+}
+
+// acquire waits for the slots of a run. The groups and the resources are
+// taken in sorted order and before the global slot, so that runs needing
+// several of them take them in the same order and can not deadlock.
+func (c *Cron) acquire(s slots, r *queuedRun) {
+	for _, g := range c.groups {
+		if hasTag(s.tags, g.tag) {
+			g.sem <- struct{}{}
+		}
+	}
+	for _, name := range s.resources {
+		c.resources.lock(name) <- struct{}{}
+	}
+	if c.sem != nil {
+		c.sem.wait(r)
+	}
+}
+
+// release frees the slots taken by acquire.
+func (c *Cron) release(s slots, r *queuedRun) {
+	if c.sem != nil {
+		if r.cancel != nil {
+			r.cancel()
+		}
+		c.sem.leave(r)
+	}
+	for _, name := range s.resources {
+		<-c.resources.lock(name)
+	}
+	for _, g := range c.groups {
+		if hasTag(s.tags, g.tag) {
+			<-g.sem
+		}
+	}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithTagLimit(t *testing.T) {
+	c := New(WithTagLimit("db", 2), WithTagLimit("api", 1))
+	var (
+		mu      sync.Mutex
+		running = make(map[string]int)
+		max     = make(map[string]int)
+		wg      sync.WaitGroup
+	)
+	// Counts the jobs running in each group.
+	job := func(groups ...string) func() (string, error) {
+		return func() (string, error) {
+			defer wg.Done()
+			mu.Lock()
+			for _, g := range groups {
+				running[g]++
+				if running[g] > max[g] {
+					max[g] = running[g]
+				}
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			for _, g := range groups {
+				running[g]--
+			}
+			mu.Unlock()
+			return "", nil
+		}
+	}
+	db, _ := c.AddFunc("@yearly", job("db"), WithTags("db"))
+	both, _ := c.AddFunc("@yearly", job("api", "db"), WithTags("api", "db"))
+	free, _ := c.AddFunc("@yearly", job("free"))
+	wg.Add(12)
+	for i := 0; i < 4; i++ {
+		for _, id := range []string{db, both, free} {
+			if err := c.RunNow(id); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	wg.Wait()
+	if max["db"] != 2 || max["api"] != 1 {
+		t.Errorf("expected at most 2 db jobs and 1 api job at a time, got %v", max)
+	}
+	if max["free"] < 2 {
+		t.Errorf("expected untagged jobs not to be limited, got %d at a time", max["free"])
+	}
+}
+
+func TestWithResources(t *testing.T) {
+	c := New()
+	var (
+		mu      sync.Mutex
+		holders = make(map[string]int)
+		overlap bool
+		wg      sync.WaitGroup
+	)
+	job := func(resources ...string) func() (string, error) {
+		return func() (string, error) {
+			defer wg.Done()
+			mu.Lock()
+			for _, r := range resources {
+				holders[r]++
+				overlap = overlap || holders[r] > 1
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			for _, r := range resources {
+				holders[r]--
+			}
+			mu.Unlock()
+			return "", nil
+		}
+	}
+	a, _ := c.AddFunc("@yearly", job("table"), WithResources("table"))
+	b, _ := c.AddFunc("@yearly", job("table", "bucket"), WithResources("bucket", "table", "table"))
+	d, _ := c.AddFunc("@yearly", job("bucket"), WithResources("bucket"))
+	if e, _ := c.Entry(b); len(e.Resources) != 2 || e.Resources[0] != "bucket" {
+		t.Errorf("expected the sorted resources without duplicates, got %v", e.Resources)
+	} else {
+		e.Resources[0] = "changed"
+	}
+	if e, _ := c.Entry(b); e.Resources[0] != "bucket" {
+		t.Errorf("expected the resources of the entry to be left alone, got %v", e.Resources)
+	}
+	wg.Add(15)
+	for i := 0; i < 5; i++ {
+		for _, id := range []string{a, b, d} {
+			c.RunNow(id)
+		}
+	}
+	wg.Wait()
+	if overlap {
+		t.Error("expected jobs sharing a resource not to overlap")
+	}
+}
@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// WithDisableAfter disables the entries whose job fails n times in a row.
+// Disabled entries stay in the Cron but are not run until Enable is called;
+// an EventEntryDisabled is emitted for the operator to follow up.
+func WithDisableAfter(n int) Option {
+	return func(c *Cron) {
+		c.disableAfter = n
+	}
+}
+
+// Enable lets the job with the given id run again after it was disabled for
+// failing too often or quarantined, releasing it from the quarantine.
+func (c *Cron) Enable(id string) (err error) {
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		if h := e.health; h != nil {
+			h.mu.Lock()
+			wasDisabled := h.disabled
+			h.disabled, h.streak, h.quarantined = false, 0, nil
+			h.mu.Unlock()
+			if wasDisabled {
+				c.changed(ChangeStatus, id, e)
+			}
+		}
+	})
+	if err == nil {
+		c.emit(Event{Type: EventEntryEnabled, EntryID: id})
+	}
+	return err
+}
+
+// entryHealth tracks the outcome of the runs of an entry: its failure
+// streak, its circuit breaker and whether it was disabled or quarantined,
+// and why. It is shared by
+// the copies of the entry, and guarded by its own mutex as runs finish
+// outside of the run loop.
+type entryHealth struct {
+	mu       sync.Mutex
+	streak   int
+	disabled bool
+	breaker  *circuitBreaker
+	// quarantined tells why the entry was disabled.
+	quarantined *QuarantineRecord
+}
+
+// healthOf returns the health of e, creating it on first use. It must be
+// called through do.
+func healthOf(e *Entry) *entryHealth {
+	if e.health == nil {
+		e.health = &entryHealth{}
+	}
+	return e.health
+}
+
+// allow reports whether the run due at t may start. Runs are skipped, for
+// the given reason, while the breaker is open; disabled entries skip them
+// silently.
+func (h *entryHealth) allow(t time.Time) (ok bool, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.disabled:
+		return false, ""
+	case h.breaker != nil && !h.breaker.allow(t):
+		return false, "Circuit breaker open"
+	}
+	return true, ""
+}
+
+// skipped records that the run let through by allow was skipped before it
+// started, so that it does not hold the probe of the breaker.
+func (h *entryHealth) skipped() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.breaker != nil {
+		h.breaker.skip()
+	}
+}
+
+// recordHealth updates the health of the job with the given id with the
+// outcome of a run, emitting events as its breaker opens or closes and as
+// it gets disabled.
+func (c *Cron) recordHealth(id string, h *entryHealth, err error) {
+	if h == nil {
+		return
+	}
+	now := c.now()
+	h.mu.Lock()
+	if err != nil {
+		h.streak++
+	} else {
+		h.streak = 0
+	}
+	var breaker BreakerState
+	if h.breaker != nil && h.breaker.record(err, h.streak, now) {
+		breaker = h.breaker.state
+	}
+	var disabled string
+	if c.disableAfter > 0 && !h.disabled && h.streak >= c.disableAfter {
+		disabled = h.quarantineFailures(id, now, err)
+	}
+	h.mu.Unlock()
+
+	switch breaker {
+	case BreakerOpen:
+		c.entryErrorf(id, "cron: circuit breaker of %s opened", id)
+		c.emit(Event{Type: EventBreakerOpened, EntryID: id, Time: now})
+	case BreakerClosed:
+		c.emit(Event{Type: EventBreakerClosed, EntryID: id, Time: now})
+	}
+	if disabled != "" {
+		c.disabled(id)
+		c.entryErrorf(id, "cron: job %s: %s", id, disabled)
+		c.emit(Event{Type: EventEntryDisabled, EntryID: id, Time: now, Msg: disabled, Error: err.Error()})
+	}
+}
+
+// copyTo sets the health related fields of the copy of an entry.
+func (h *entryHealth) copyTo(cp *Entry) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cp.Disabled = h.disabled
+	if h.breaker != nil {
+		cp.Breaker = h.breaker.state
+	}
+}
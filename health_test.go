@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestWithDisableAfter(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC), WithDisableAfter(3))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	runs, fail := 0, true
+	id, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+		runs++
+		if fail && runs != 2 {
+			return "", errors.New("broken")
+		}
+		return "", nil
+	})
+	c.Start()
+	defer c.Stop()
+
+	// A success resets the streak: 1 failure, 1 success, 3 failures.
+	clock.Advance(10 * time.Minute)
+	if runs != 5 {
+		t.Errorf("expected 5 runs before the entry is disabled, got %d", runs)
+	}
+	e, ok := c.Entry(id)
+	if !ok || !e.Disabled || e.Status != EntryDisabled {
+		t.Fatalf("expected the entry to be kept disabled, got %+v", e)
+	}
+	var disabled []Event
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventEntryDisabled {
+			disabled = append(disabled, e)
+		}
+	}
+	if len(disabled) != 1 || disabled[0].Error != "broken" {
+		t.Errorf("expected a single entry_disabled event, got %+v", disabled)
+	}
+
+	fail = false
+	if err := c.Enable(id); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Minute)
+	if runs != 6 {
+		t.Errorf("expected the enabled entry to run, got %d runs", runs)
+	}
+	if e, _ := c.Entry(id); e.Disabled || e.Status != EntryScheduled {
+		t.Errorf("expected the entry to be enabled, got %+v", e)
+	}
+	if err := c.Enable("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
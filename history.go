@@ -0,0 +1,159 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of runs kept per entry.
+const DefaultHistorySize = 20
+
+// RunRecord describes a single run of a job.
+type RunRecord struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Msg   string    `json:"msg,omitempty"`
+	Error string    `json:"error,omitempty"`
+	// Wait is how long the run waited to be started once dispatched.
+	Wait time.Duration `json:"wait,omitempty"`
+}
+
+// Duration returns how long the run took.
+func (r RunRecord) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// EntryStats are the cumulated run statistics of an entry.
+type EntryStats struct {
+	Runs         int64         `json:"runs"`
+	Failures     int64         `json:"failures"`
+	LastRun      time.Time     `json:"last_run"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+	// CPUTime is the CPU time used by all the runs, see Usage.
+	CPUTime   time.Duration `json:"cpu_time"`
+	LastUsage Usage         `json:"last_usage"`
+	// SLAMet and SLAMissed count the runs meeting and missing the SLA of
+	// the entry.
+	SLAMet    int64 `json:"sla_met"`
+	SLAMissed int64 `json:"sla_missed"`
+	// Anomalies counts the runs flagged by WithAnomalyDetection.
+	Anomalies int64 `json:"anomalies"`
+}
+
+// runHistory keeps the latest runs and the stats of each job.
+type runHistory struct {
+	mu    sync.Mutex
+	size  int
+	runs  map[string]*runRing
+	stats map[string]*EntryStats
+}
+
+// runRing is a ring buffer of the latest runs of a job, so recording a run
+// does not allocate once it is full.
+type runRing struct {
+	buf  []RunRecord
+	next int
+}
+
+func (r *runRing) add(rec RunRecord, size int) {
+	if len(r.buf) < size {
+		r.buf = append(r.buf, rec)
+		return
+	}
+	r.buf[r.next] = rec
+	r.next = (r.next + 1) % size
+}
+
+// records returns a copy of the runs, oldest first.
+func (r *runRing) records() []RunRecord {
+	out := make([]RunRecord, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+func newRunHistory(size int) *runHistory {
+	return &runHistory{
+		size:  size,
+		runs:  make(map[string]*runRing),
+		stats: make(map[string]*EntryStats),
+	}
+}
+
+// record adds a finished run of the job with the given id.
+func (h *runHistory) record(id string, start, end time.Time, wait time.Duration, msg string, err error, u Usage) {
+	r := RunRecord{Start: start, End: end, Msg: msg, Wait: wait}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs, ok := h.runs[id]
+	if !ok {
+		runs = &runRing{}
+		h.runs[id] = runs
+	}
+	runs.add(r, h.size)
+
+	s, ok := h.stats[id]
+	if !ok {
+		s = &EntryStats{}
+		h.stats[id] = s
+	}
+	s.Runs++
+	if err != nil {
+		s.Failures++
+	}
+	s.LastRun = start
+	s.LastDuration = r.Duration()
+	s.LastError = r.Error
+	s.CPUTime += u.CPUTime
+	s.LastUsage = u
+}
+
+// recordSLA counts a run of the job with the given id meeting its SLA or
+// not.
+func (h *runHistory) recordSLA(id string, met bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.stats[id]
+	if !ok {
+		s = &EntryStats{}
+		h.stats[id] = s
+	}
+	if met {
+		s.SLAMet++
+	} else {
+		s.SLAMissed++
+	}
+}
+
+// forget drops the history of the job with the given id.
+func (h *runHistory) forget(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.runs, id)
+	delete(h.stats, id)
+}
+
+// History returns the latest runs of the job with the given id, oldest first.
+func (c *Cron) History(id string) []RunRecord {
+	c.history.mu.Lock()
+	defer c.history.mu.Unlock()
+	runs, ok := c.history.runs[id]
+	if !ok {
+		return nil
+	}
+	return runs.records()
+}
+
+// Stats returns the run statistics of the job with the given id.
+func (c *Cron) Stats(id string) EntryStats {
+	c.history.mu.Lock()
+	defer c.history.mu.Unlock()
+	if s, ok := c.history.stats[id]; ok {
+		return *s
+	}
+	return EntryStats{}
+}
@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPJobType is the job type of HTTPRequestJob.
+const HTTPJobType = "http"
+
+// MaxResponseExcerpt is the number of response body bytes reported by an
+// HTTPRequestJob.
+const MaxResponseExcerpt = 1 << 10
+
+// HTTPRequestJob performs an HTTP request and reports the response status
+// and the beginning of its body as the result message.
+type HTTPRequestJob struct {
+	id string
+
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+	// ExpectedStatus lists the status codes counted as success. Any 2xx
+	// status is a success if empty.
+	ExpectedStatus []int
+	// Timeout bounds the whole request. Zero means no timeout.
+	Timeout time.Duration
+	// Client sends the request, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+// NewHTTPRequestJob returns a job with the given id requesting url.
+func NewHTTPRequestJob(id, method, url string) *HTTPRequestJob {
+	return &HTTPRequestJob{id: id, Method: method, URL: url, Header: make(http.Header)}
+}
+
+func init() {
+	RegisterJobType(HTTPJobType, newHTTPRequestJob)
+}
+
+// newHTTPRequestJob builds an HTTPRequestJob from the "method", "url",
+// "header.NAME", "body", "expect" (comma separated status codes) and
+// "timeout" parameters.
+func newHTTPRequestJob(id string, params map[string]string) (Job, error) {
+	method := params["method"]
+	if method == "" {
+		method = http.MethodGet
+	}
+	j := NewHTTPRequestJob(id, method, params["url"])
+	if j.URL == "" {
+		return nil, fmt.Errorf("Missing url parameter")
+	}
+	j.Body = params["body"]
+	for k, v := range params {
+		if strings.HasPrefix(k, "header.") {
+			j.Header.Add(k[len("header."):], v)
+		}
+	}
+	if expect := params["expect"]; expect != "" {
+		for _, s := range strings.Split(expect, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse expected status %s: %s", s, err)
+			}
+			j.ExpectedStatus = append(j.ExpectedStatus, code)
+		}
+	}
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	return j, nil
+}
+
+func (j *HTTPRequestJob) ID() string { return j.id }
+
+func (j *HTTPRequestJob) JobType() string { return HTTPJobType }
+
+func (j *HTTPRequestJob) Params() map[string]string {
+	params := map[string]string{"method": j.Method, "url": j.URL}
+	for k, v := range j.Header {
+		if len(v) > 0 {
+			params["header."+k] = v[0]
+		}
+	}
+	if j.Body != "" {
+		params["body"] = j.Body
+	}
+	if len(j.ExpectedStatus) > 0 {
+		codes := make([]string, len(j.ExpectedStatus))
+		for i, code := range j.ExpectedStatus {
+			codes[i] = strconv.Itoa(code)
+		}
+		sort.Strings(codes)
+		params["expect"] = strings.Join(codes, ",")
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	return params
+}
+
+// Run performs the request. Transport failures and unexpected status codes
+// are reported as errors.
+func (j *HTTPRequestJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext performs the request like Run, aborting it if ctx is done
+// first.
+func (j *HTTPRequestJob) RunContext(ctx context.Context) (msg string, err error) {
+	var body io.Reader
+	if j.Body != "" {
+		body = strings.NewReader(j.Body)
+	}
+	req, err := http.NewRequest(j.Method, j.URL, body)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range j.Header {
+		req.Header[k] = v
+	}
+
+	client := j.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if j.Timeout > 0 {
+		c := *client
+		c.Timeout = j.Timeout
+		client = &c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	excerpt, _ := ioutil.ReadAll(io.LimitReader(resp.Body, MaxResponseExcerpt))
+	msg = resp.Status
+	if len(excerpt) > 0 {
+		msg += ": " + string(excerpt)
+	}
+	if !j.expected(resp.StatusCode) {
+		return msg, fmt.Errorf("%s %s: unexpected status %s", j.Method, j.URL, resp.Status)
+	}
+	return msg, nil
+}
+
+func (j *HTTPRequestJob) expected(code int) bool {
+	if len(j.ExpectedStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range j.ExpectedStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPRequestJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.Header.Get("X-Token") != "t" || string(body) != "ping" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(strings.Repeat("x", 2*MaxResponseExcerpt)))
+	}))
+	defer srv.Close()
+
+	job, err := newJob(HTTPJobType, "ping", map[string]string{
+		"method":         "POST",
+		"url":            srv.URL,
+		"header.X-Token": "t",
+		"body":           "ping",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(msg, "200 OK: xxx") || len(msg) != len("200 OK: ")+MaxResponseExcerpt {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	missing := job.(*HTTPRequestJob)
+	missing.URL = srv.URL + "/missing"
+	if _, err := missing.Run(); err == nil {
+		t.Error("expected an error for a 404")
+	}
+	missing.ExpectedStatus = []int{404}
+	if _, err := missing.Run(); err != nil {
+		t.Errorf("expected 404 to be accepted, got %v", err)
+	}
+}
+
+func TestHTTPRequestJobTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	j := NewHTTPRequestJob("slow", "GET", srv.URL)
+	j.Timeout = 20 * time.Millisecond
+	if _, err := j.Run(); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
+
+func TestHTTPRequestJobParams(t *testing.T) {
+	j := NewHTTPRequestJob("job", "PUT", "http://example.com")
+	j.Header.Set("Accept", "text/plain")
+	j.Body = "{}"
+	j.ExpectedStatus = []int{201}
+	j.Timeout = time.Second
+
+	rebuilt, err := newJob(HTTPJobType, "job", j.Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rebuilt, j) {
+		t.Errorf("(expected) %+v != %+v (actual)", j, rebuilt)
+	}
+}
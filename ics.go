@@ -0,0 +1,541 @@
+package cron
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICSSchedule activates at the start of the events of an iCalendar (.ics)
+// file, so that the run times of a job can be managed in a calendar tool.
+// Recurring events are supported with the DAILY, WEEKLY, MONTHLY and YEARLY
+// frequencies of RRULE and its INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY,
+// BYMONTH and WKST parts, along with EXDATE, RDATE and modified occurrences.
+// Cancelled events are ignored.
+type ICSSchedule struct {
+	events []*icsEvent
+}
+
+// icsEvent is a VEVENT, recurring if it has a rule.
+type icsEvent struct {
+	uid     string
+	start   time.Time
+	rule    *icsRule
+	rdates  []time.Time
+	exdates map[int64]bool
+	// recurrenceID is the occurrence of a recurring event this one
+	// replaces, or the zero time.
+	recurrenceID time.Time
+}
+
+// icsRule is the RRULE of a recurring event.
+type icsRule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []icsWeekday
+	byMonthDay []int
+	byMonth    []int
+	wkst       time.Weekday
+}
+
+// icsWeekday is an item of BYDAY, e.g. -1FR for the last Friday; n is zero
+// for every such day.
+type icsWeekday struct {
+	n   int
+	day time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// maxICSPeriods bounds the periods of a rule searched for an occurrence, for
+// the rules that rarely or never match.
+const maxICSPeriods = 100000
+
+// ParseICS reads the events of an iCalendar document. Floating times, which
+// have no time zone, and dates are interpreted in loc, or in the local time
+// zone if it is nil.
+func ParseICS(r io.Reader, loc *time.Location) (*ICSSchedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events    []*icsEvent
+		event     *icsEvent
+		cancelled bool
+		depth     int
+	)
+	for i, line := range lines {
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event, cancelled, depth = &icsEvent{exdates: make(map[int64]bool)}, false, 0
+			continue
+		case event == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event.start.IsZero() {
+				return nil, fmt.Errorf("Line %d: Event without DTSTART", i+1)
+			}
+			if !cancelled {
+				events = append(events, event)
+			}
+			event = nil
+			continue
+		case depth > 0:
+			// A property of a VALARM or other component of the event.
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.uid = value
+		case "STATUS":
+			cancelled = strings.EqualFold(value, "CANCELLED")
+		case "DTSTART":
+			event.start, err = parseICSTime(value, params, loc)
+		case "RECURRENCE-ID":
+			event.recurrenceID, err = parseICSTime(value, params, loc)
+		case "RRULE":
+			event.rule, err = parseICSRule(value, loc)
+		case "EXDATE", "RDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				if t, err = parseICSTime(v, params, loc); err != nil {
+					break
+				}
+				if name == "EXDATE" {
+					event.exdates[t.Unix()] = true
+				} else {
+					event.rdates = append(event.rdates, t)
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %s: %w", i+1, name, err)
+		}
+	}
+
+	// Modified occurrences replace the ones of the recurring event.
+	recurring := make(map[string]*icsEvent)
+	for _, e := range events {
+		if e.rule != nil && e.recurrenceID.IsZero() {
+			recurring[e.uid] = e
+		}
+	}
+	for _, e := range events {
+		if master, ok := recurring[e.uid]; ok && !e.recurrenceID.IsZero() {
+			master.exdates[e.recurrenceID.Unix()] = true
+		}
+	}
+	return &ICSSchedule{events}, nil
+}
+
+// LoadICS reads the events of the iCalendar file at source, a path or an
+// http, https or webcal URL. See ParseICS.
+func LoadICS(ctx context.Context, source string, loc *time.Location) (*ICSSchedule, error) {
+	if strings.HasPrefix(source, "webcal://") {
+		source = "https://" + source[len("webcal://"):]
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ParseICS(f, loc)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("Failed to fetch calendar %s: %s", source, resp.Status)
+	}
+	return ParseICS(resp.Body, loc)
+}
+
+// Len returns the number of events of the calendar.
+func (s *ICSSchedule) Len() int {
+	return len(s.events)
+}
+
+// Next returns the start of the first occurrence of an event after t, or
+// the zero time if there is none.
+func (s *ICSSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, e := range s.events {
+		if n := e.next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	if next.IsZero() {
+		return next
+	}
+	return next.In(t.Location())
+}
+
+// next returns the first occurrence of the event after t.
+func (e *icsEvent) next(t time.Time) time.Time {
+	var next time.Time
+	candidate := func(o time.Time) {
+		if o.After(t) && !e.exdates[o.Unix()] && (next.IsZero() || o.Before(next)) {
+			next = o
+		}
+	}
+	for _, o := range e.rdates {
+		candidate(o)
+	}
+	if e.rule == nil {
+		candidate(e.start)
+		return next
+	}
+	e.rule.each(e.start, t, func(o time.Time) bool {
+		if !o.After(t) || e.exdates[o.Unix()] {
+			return true
+		}
+		candidate(o)
+		return false
+	})
+	return next
+}
+
+// each calls fn with the occurrences of the rule for an event starting at
+// start, in order, skipping ahead to shortly before from when the rule has
+// no COUNT, until fn returns false.
+func (r *icsRule) each(start, from time.Time, fn func(time.Time) bool) {
+	k := 0
+	if r.count == 0 && from.After(start) {
+		k = r.periodsBefore(start, from) - 1
+		if k < 0 {
+			k = 0
+		}
+	}
+	n := 0
+	for end := k + maxICSPeriods; k < end; k++ {
+		for _, d := range r.period(start, k) {
+			o := time.Date(d.Year(), d.Month(), d.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+			if o.Before(start) {
+				continue
+			}
+			if !r.until.IsZero() && o.After(r.until) {
+				return
+			}
+			n++
+			if r.count > 0 && n > r.count {
+				return
+			}
+			if !fn(o) {
+				return
+			}
+		}
+	}
+}
+
+// periodsBefore returns the number of whole periods of the rule from start
+// to t.
+func (r *icsRule) periodsBefore(start, t time.Time) int {
+	days := int(t.Sub(start).Hours() / 24)
+	months := (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+	switch r.freq {
+	case "DAILY":
+		return days / r.interval
+	case "WEEKLY":
+		return days / (7 * r.interval)
+	case "MONTHLY":
+		return months / r.interval
+	}
+	return (t.Year() - start.Year()) / r.interval
+}
+
+// period returns the days of the occurrences in the k-th period of the rule
+// for an event starting at start, in order.
+func (r *icsRule) period(start time.Time, k int) []time.Time {
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	var days []time.Time
+	switch r.freq {
+	case "DAILY":
+		days = []time.Time{first.AddDate(0, 0, k*r.interval)}
+	case "WEEKLY":
+		d := first.AddDate(0, 0, 7*k*r.interval)
+		if len(r.byDay) == 0 {
+			days = []time.Time{d}
+			break
+		}
+		d = d.AddDate(0, 0, -((int(d.Weekday()) - int(r.wkst) + 7) % 7))
+		for i := 0; i < 7; i++ {
+			if day := d.AddDate(0, 0, i); r.hasWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+	case "MONTHLY":
+		month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, k*r.interval, 0)
+		days = r.monthDays(month, start.Day())
+	case "YEARLY":
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		for _, m := range months {
+			month := time.Date(start.Year()+k*r.interval, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+			days = append(days, r.monthDays(month, start.Day())...)
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		return days
+	}
+
+	// The parts that do not expand the period limit it.
+	kept := days[:0]
+	for _, d := range days {
+		if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(d.Month())) {
+			continue
+		}
+		if r.freq == "DAILY" && len(r.byDay) > 0 && !r.hasWeekday(d.Weekday()) {
+			continue
+		}
+		if (r.freq == "DAILY" || r.freq == "WEEKLY") && len(r.byMonthDay) > 0 && !r.hasMonthDay(d) {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
+
+// monthDays returns the days of the month starting at first selected by
+// BYMONTHDAY and BYDAY, or day without them, in order.
+func (r *icsRule) monthDays(first time.Time, day int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+	var days []time.Time
+	switch {
+	case len(r.byMonthDay) > 0:
+		for d := 1; d <= last; d++ {
+			date := first.AddDate(0, 0, d-1)
+			if r.hasMonthDay(date) && (len(r.byDay) == 0 || r.hasWeekday(date.Weekday())) {
+				days = append(days, date)
+			}
+		}
+	case len(r.byDay) > 0:
+		for d := 1; d <= last; d++ {
+			date := first.AddDate(0, 0, d-1)
+			nth, fromEnd := (d-1)/7+1, -((last-d)/7 + 1)
+			for _, wd := range r.byDay {
+				if wd.day == date.Weekday() && (wd.n == 0 || wd.n == nth || wd.n == fromEnd) {
+					days = append(days, date)
+					break
+				}
+			}
+		}
+	case day <= last:
+		days = []time.Time{first.AddDate(0, 0, day-1)}
+	}
+	return days
+}
+
+func (r *icsRule) hasWeekday(day time.Weekday) bool {
+	for _, wd := range r.byDay {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *icsRule) hasMonthDay(d time.Time) bool {
+	last := d.AddDate(0, 1, -d.Day()).Day()
+	for _, md := range r.byMonthDay {
+		if md == d.Day() || md < 0 && last+md+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// unfoldICS returns the content lines of an iCalendar document, joining
+// the folded ones.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICSLine splits a content line into its upper-cased name, its
+// parameters and its value.
+func splitICSLine(line string) (name string, params map[string]string, value string, ok bool) {
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+	parts := strings.Split(line[:colon], ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if i := strings.IndexByte(p, '='); i > 0 {
+			params[strings.ToUpper(p[:i])] = strings.Trim(p[i+1:], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseICSTime parses a DATE or DATE-TIME value.
+func parseICSTime(value string, params map[string]string, loc *time.Location) (time.Time, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, loc)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("Unknown time zone %s", tzid)
+		}
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// parseICSRule parses the value of an RRULE.
+func parseICSRule(value string, loc *time.Location) (*icsRule, error) {
+	r := &icsRule{interval: 1, wkst: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		i := strings.IndexByte(part, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("Invalid rule part %s", part)
+		}
+		key, val := strings.ToUpper(part[:i]), strings.ToUpper(part[i+1:])
+		var err error
+		switch key {
+		case "FREQ":
+			switch val {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.freq = val
+			default:
+				return nil, fmt.Errorf("Unsupported frequency %s", val)
+			}
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(val); err == nil && r.interval < 1 {
+				err = fmt.Errorf("Interval should be positive")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			if r.until, err = parseICSTime(val, nil, loc); err == nil && len(val) == len("20060102") {
+				// The whole day is included.
+				r.until = r.until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				if len(item) < 2 {
+					return nil, fmt.Errorf("Invalid day %s", item)
+				}
+				day, ok := icsWeekdays[item[len(item)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("Invalid day %s", item)
+				}
+				wd := icsWeekday{day: day}
+				if n := item[:len(item)-2]; n != "" {
+					if wd.n, err = strconv.Atoi(n); err != nil || wd.n == 0 || wd.n < -5 || wd.n > 5 {
+						return nil, fmt.Errorf("Invalid day %s", item)
+					}
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseICSInts(val, -31, 31)
+		case "BYMONTH":
+			r.byMonth, err = parseICSInts(val, 1, 12)
+		case "WKST":
+			var ok bool
+			if r.wkst, ok = icsWeekdays[val]; !ok {
+				err = fmt.Errorf("Invalid day %s", val)
+			}
+		default:
+			return nil, fmt.Errorf("Unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.freq == "" {
+		return nil, fmt.Errorf("Missing FREQ")
+	}
+	if r.freq == "YEARLY" && len(r.byMonth) == 0 && (len(r.byDay) > 0 || len(r.byMonthDay) > 0) {
+		return nil, fmt.Errorf("Unsupported yearly rule without BYMONTH")
+	}
+	return r, nil
+}
+
+// parseICSInts parses a comma separated list of non-zero integers between
+// min and max.
+func parseICSInts(value string, min, max int) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("Invalid value %s", item)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
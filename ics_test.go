@@ -0,0 +1,136 @@
+package cron
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:close
+DTSTART;TZID=America/New_York:20120702T090000
+RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=6
+EXDATE;TZID=America/New_York:20120831T090000
+SUMMARY:Month end close, last Friday
+  of the month
+BEGIN:VALARM
+TRIGGER:-PT15M
+DTSTART:19990101T000000Z
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+DTSTART:20120709T140000Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20120725T235959Z
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID:20120711T140000Z
+DTSTART:20120711T160000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:once
+DTSTART;VALUE=DATE:20120720
+END:VEVENT
+BEGIN:VEVENT
+UID:gone
+STATUS:CANCELLED
+DTSTART:20120710T000000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestICSSchedule(t *testing.T) {
+	s, err := ParseICS(strings.NewReader(strings.Replace(testCalendar, "\n", "\r\n", -1)), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 4 {
+		t.Errorf("expected 4 events, got %d", s.Len())
+	}
+
+	var runs []string
+	for next := s.Next(time.Date(2012, 7, 9, 14, 0, 0, 0, time.UTC)); !next.IsZero(); next = s.Next(next) {
+		runs = append(runs, next.Format("Jan 2 15:04"))
+		if len(runs) > 20 {
+			break
+		}
+	}
+	expected := []string{
+		"Jul 11 16:00", "Jul 16 14:00", "Jul 18 14:00", "Jul 20 00:00", "Jul 23 14:00",
+		"Jul 25 14:00", "Jul 27 13:00", "Sep 28 13:00", "Oct 26 13:00", "Nov 30 14:00", "Dec 28 14:00",
+	}
+	if !equalStrings(runs, expected) {
+		t.Errorf("expected runs %v, got %v", expected, runs)
+	}
+}
+
+func TestICSRules(t *testing.T) {
+	tests := []struct {
+		start, rule string
+		from        time.Time
+		expected    time.Time
+	}{
+		// Every other day, long after the start.
+		{"20000101T080000Z", "FREQ=DAILY;INTERVAL=2", time.Date(2012, 7, 9, 0, 0, 0, 0, time.UTC), time.Date(2012, 7, 10, 8, 0, 0, 0, time.UTC)},
+		{"20120131T080000Z", "FREQ=MONTHLY", time.Date(2012, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2012, 3, 31, 8, 0, 0, 0, time.UTC)},
+		{"20120131T080000Z", "FREQ=MONTHLY;BYMONTHDAY=-1", time.Date(2012, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2012, 2, 29, 8, 0, 0, 0, time.UTC)},
+		{"20120229T080000Z", "FREQ=YEARLY", time.Date(2012, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2016, 2, 29, 8, 0, 0, 0, time.UTC)},
+		{"20120101T080000Z", "FREQ=YEARLY;BYMONTH=3,9;BYDAY=2TU", time.Date(2012, 3, 14, 0, 0, 0, 0, time.UTC), time.Date(2012, 9, 11, 8, 0, 0, 0, time.UTC)},
+		{"20120701T080000Z", "FREQ=WEEKLY;WKST=SU;BYDAY=SU,SA;INTERVAL=2", time.Date(2012, 7, 2, 0, 0, 0, 0, time.UTC), time.Date(2012, 7, 7, 8, 0, 0, 0, time.UTC)},
+		{"20120701T080000Z", "FREQ=DAILY;COUNT=3", time.Date(2012, 7, 3, 9, 0, 0, 0, time.UTC), time.Time{}},
+	}
+	for _, test := range tests {
+		cal := "BEGIN:VEVENT\nDTSTART:" + test.start + "\nRRULE:" + test.rule + "\nEND:VEVENT\n"
+		s, err := ParseICS(strings.NewReader(cal), nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.rule, err)
+			continue
+		}
+		if next := s.Next(test.from); !next.Equal(test.expected) {
+			t.Errorf("%s from %v: (expected) %v != %v (actual)", test.rule, test.from, test.expected, next)
+		}
+	}
+
+	for _, cal := range []string{
+		"BEGIN:VEVENT\nSUMMARY:no start\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20120701T080000Z\nRRULE:FREQ=HOURLY\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20120701T080000Z\nRRULE:FREQ=WEEKLY;BYDAY=XX\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;TZID=Nowhere/Zone:20120701T080000\nEND:VEVENT\n",
+	} {
+		if _, err := ParseICS(strings.NewReader(cal), nil); err == nil {
+			t.Errorf("expected an error for %q", cal)
+		}
+	}
+}
+
+func TestLoadICS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.ics")
+	if err := ioutil.WriteFile(path, []byte(testCalendar), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testCalendar))
+	}))
+	defer srv.Close()
+
+	for _, source := range []string{path, srv.URL + "/jobs.ics"} {
+		if s, err := LoadICS(context.Background(), source, time.UTC); err != nil || s.Len() != 4 {
+			t.Errorf("%s: unexpected calendar %v, %v", source, s, err)
+		}
+	}
+	if _, err := LoadICS(context.Background(), srv.URL+"/missing.ics", time.UTC); err == nil {
+		t.Error("expected an error for a missing calendar")
+	}
+}
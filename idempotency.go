@@ -0,0 +1,28 @@
+package cron
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// IdempotencyKey returns the idempotency key of a run of the entry with
+// the given id due at the given time: the same for every attempt and every
+// delivery of the results of the run, and different for any other run, so
+// that the systems receiving the results more than once, e.g. through
+// retried webhooks or at-least-once queues, can drop the duplicates. The key
+// does not depend on the location of the time.
+func IdempotencyKey(id string, scheduled time.Time) string {
+	sum := sha256.Sum256([]byte(id + "\x00" + scheduled.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:16])
+}
+
+type idempotencyKey struct{}
+
+// RunIdempotencyKey returns the idempotency key of the run given ctx, see
+// IdempotencyKey.
+func RunIdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKey{}).(string)
+	return key, ok
+}
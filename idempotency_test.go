@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// keyJob records the idempotency key and scheduled time of its run.
+type keyJob struct {
+	keys chan string
+	at   chan time.Time
+}
+
+func (j *keyJob) ID() string           { return "keyed" }
+func (j *keyJob) Run() (string, error) { return j.RunContext(context.Background()) }
+
+func (j *keyJob) RunContext(ctx context.Context) (string, error) {
+	key, _ := RunIdempotencyKey(ctx)
+	at, _ := ScheduledTime(ctx)
+	j.keys <- key
+	j.at <- at
+	return "", nil
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := IdempotencyKey("backup", at)
+	if len(key) != 32 {
+		t.Errorf("expected a 32 character key, got %q", key)
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	if got := IdempotencyKey("backup", at.In(paris)); got != key {
+		t.Errorf("expected the key not to depend on the location, got %s and %s", key, got)
+	}
+	for _, other := range []string{
+		IdempotencyKey("backup", at.Add(time.Second)),
+		IdempotencyKey("backup2", at),
+	} {
+		if other == key {
+			t.Errorf("expected distinct keys, got %s twice", key)
+		}
+	}
+}
+
+func TestRunIdempotencyKey(t *testing.T) {
+	c := New()
+	results := make(chan *JobResult, 1)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	job := &keyJob{make(chan string, 1), make(chan time.Time, 1)}
+	c.AddJob("@yearly", job)
+	c.Start()
+	defer c.Stop()
+
+	c.RunNow("keyed")
+	key, at := <-job.keys, <-job.at
+	if key == "" || key != IdempotencyKey("keyed", at) {
+		t.Errorf("expected the key of the run due at %v, got %q", at, key)
+	}
+	if r := <-results; r.IdempotencyKey != key {
+		t.Errorf("expected result key %s, got %s", key, r.IdempotencyKey)
+	}
+}
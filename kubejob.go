@@ -0,0 +1,292 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// KubernetesJobType is the job type of KubernetesJob.
+const KubernetesJobType = "kubernetes"
+
+// serviceAccountDir holds the credentials of pods running in a cluster.
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// KubernetesJob creates a Kubernetes batch/v1 Job from a template on each
+// run and waits for it to complete or fail.
+//
+// Unless the template sets metadata.name, the Job is named after the entry
+// id with a generated suffix. The default server, token and namespace are
+// those of the pod the scheduler runs in.
+type KubernetesJob struct {
+	id string
+
+	// Template is the Job manifest in JSON.
+	Template []byte
+	// Namespace overrides the namespace of the template.
+	Namespace string
+	// Server is the API server URL.
+	Server string
+	// Token is the bearer token used to authenticate.
+	Token string
+	// Client is used to call the API server. It trusts the cluster CA by
+	// default.
+	Client *http.Client
+	// PollInterval is the interval between Job status checks. It defaults to
+	// 5 seconds.
+	PollInterval time.Duration
+	// Timeout fails the run if the Job has not finished in time. Zero means
+	// no timeout.
+	Timeout time.Duration
+	// Delete deletes the Job and its pods once it finished.
+	Delete bool
+}
+
+// NewKubernetesJob returns a job with the given id creating Jobs from the
+// JSON template.
+func NewKubernetesJob(id string, template []byte) *KubernetesJob {
+	return &KubernetesJob{id: id, Template: template}
+}
+
+func init() {
+	RegisterJobType(KubernetesJobType, newKubernetesJob)
+}
+
+// newKubernetesJob builds a KubernetesJob from the "template", "namespace",
+// "server", "poll_interval", "timeout" and "delete" parameters.
+func newKubernetesJob(id string, params map[string]string) (Job, error) {
+	j := NewKubernetesJob(id, []byte(params["template"]))
+	var template map[string]interface{}
+	if err := json.Unmarshal(j.Template, &template); err != nil {
+		return nil, fmt.Errorf("Failed to parse template: %s", err)
+	}
+	j.Namespace = params["namespace"]
+	j.Server = params["server"]
+	j.Delete = params["delete"] == "true"
+	for name, d := range map[string]*time.Duration{"poll_interval": &j.PollInterval, "timeout": &j.Timeout} {
+		if v := params[name]; v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse %s %s: %s", name, v, err)
+			}
+			*d = parsed
+		}
+	}
+	return j, nil
+}
+
+func (j *KubernetesJob) ID() string { return j.id }
+
+func (j *KubernetesJob) JobType() string { return KubernetesJobType }
+
+// Params returns the parameters of the job. The token is left out so it is
+// not written to snapshots.
+func (j *KubernetesJob) Params() map[string]string {
+	params := map[string]string{"template": string(j.Template)}
+	if j.Namespace != "" {
+		params["namespace"] = j.Namespace
+	}
+	if j.Server != "" {
+		params["server"] = j.Server
+	}
+	if j.PollInterval > 0 {
+		params["poll_interval"] = j.PollInterval.String()
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	if j.Delete {
+		params["delete"] = "true"
+	}
+	return params
+}
+
+// kubeJob is the part of a batch/v1 Job read by KubernetesJob.
+type kubeJob struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Status struct {
+		Succeeded  int `json:"succeeded"`
+		Failed     int `json:"failed"`
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// Run creates the Job and waits for it to finish.
+func (j *KubernetesJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext creates the Job and waits for it to finish, or for ctx to be
+// done. The poll interval and the timeout elapse on the clock of the run,
+// see RunClock.
+func (j *KubernetesJob) RunContext(ctx context.Context) (msg string, err error) {
+	k, err := j.client()
+	if err != nil {
+		return "", err
+	}
+
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(j.Template, &manifest); err != nil {
+		return "", fmt.Errorf("Failed to parse template: %s", err)
+	}
+	metadata, _ := manifest["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		manifest["metadata"] = metadata
+	}
+	if metadata["name"] == nil && metadata["generateName"] == nil {
+		metadata["generateName"] = j.id + "-"
+	}
+	namespace := j.Namespace
+	if namespace == "" {
+		namespace, _ = metadata["namespace"].(string)
+	}
+	if namespace == "" {
+		namespace = k.namespace
+	}
+	metadata["namespace"] = namespace
+
+	jobs := "/apis/batch/v1/namespaces/" + url.PathEscape(namespace) + "/jobs"
+	var created kubeJob
+	if err := k.call("POST", jobs, manifest, &created); err != nil {
+		return "", fmt.Errorf("Failed to create job: %s", err)
+	}
+	path := jobs + "/" + url.PathEscape(created.Metadata.Name)
+	if j.Delete {
+		defer k.call("DELETE", path+"?propagationPolicy=Background", nil, nil)
+	}
+
+	interval := j.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	clock := RunClock(ctx)
+	var deadline <-chan time.Time
+	if j.Timeout > 0 {
+		timer := clock.NewTimer(j.Timeout)
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+	poll := clock.NewTimer(interval)
+	defer poll.Stop()
+	for {
+		var job kubeJob
+		if err := k.call("GET", path, nil, &job); err != nil {
+			return "", fmt.Errorf("Failed to get job %s: %s", created.Metadata.Name, err)
+		}
+		for _, c := range job.Status.Conditions {
+			if c.Status != "True" {
+				continue
+			}
+			switch c.Type {
+			case "Complete":
+				return fmt.Sprintf("Job %s/%s succeeded", namespace, job.Metadata.Name), nil
+			case "Failed":
+				return "", fmt.Errorf("Job %s/%s failed: %s", namespace, job.Metadata.Name, c.Message)
+			}
+		}
+		select {
+		case <-poll.C():
+			poll.Reset(interval)
+		case <-deadline:
+			return "", fmt.Errorf("Job %s/%s timed out after %s", namespace, job.Metadata.Name, j.Timeout)
+		case <-ctx.Done():
+			return "", fmt.Errorf("Job %s/%s canceled: %s", namespace, job.Metadata.Name, ctx.Err())
+		}
+	}
+}
+
+// kubeClient calls the Kubernetes API server.
+type kubeClient struct {
+	http      *http.Client
+	server    string
+	token     string
+	namespace string
+}
+
+// client returns a client for the configured server, falling back to the
+// in-cluster service account.
+func (j *KubernetesJob) client() (*kubeClient, error) {
+	k := &kubeClient{http: j.Client, server: j.Server, token: j.Token, namespace: "default"}
+	if ns, err := ioutil.ReadFile(serviceAccountDir + "/namespace"); err == nil {
+		k.namespace = strings.TrimSpace(string(ns))
+	}
+	if k.server == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("No Kubernetes API server configured")
+		}
+		k.server = "https://" + net.JoinHostPort(host, port)
+	}
+	if k.token == "" {
+		if token, err := ioutil.ReadFile(serviceAccountDir + "/token"); err == nil {
+			k.token = strings.TrimSpace(string(token))
+		}
+	}
+	if k.http == nil {
+		k.http = http.DefaultClient
+		if ca, err := ioutil.ReadFile(serviceAccountDir + "/ca.crt"); err == nil {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(ca)
+			k.http = &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			}}
+		}
+	}
+	return k, nil
+}
+
+func (k *kubeClient) call(method, path string, in, out interface{}) error {
+	var body *bytes.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(k.server, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if k.token != "" {
+		req.Header.Set("Authorization", "Bearer "+k.token)
+	}
+	resp, err := k.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var status struct{ Message string }
+		json.NewDecoder(resp.Body).Decode(&status)
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, status.Message)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
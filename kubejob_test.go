@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKubernetesJob(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		polls   int
+		created map[string]interface{}
+		deleted bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/apis/batch/v1/namespaces/batch/jobs":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"metadata":{"name":"backup-x1","namespace":"batch"}}`))
+		case r.Method == "GET" && r.URL.Path == "/apis/batch/v1/namespaces/batch/jobs/backup-x1":
+			polls++
+			status := `{}`
+			if polls > 1 {
+				status = `{"succeeded":1,"conditions":[{"type":"Complete","status":"True"}]}`
+			}
+			w.Write([]byte(`{"metadata":{"name":"backup-x1"},"status":` + status + `}`))
+		case r.Method == "DELETE" && r.URL.Path == "/apis/batch/v1/namespaces/batch/jobs/backup-x1":
+			deleted = r.URL.Query().Get("propagationPolicy") == "Background"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	job, err := newJob(KubernetesJobType, "backup", map[string]string{
+		"template":      `{"apiVersion":"batch/v1","kind":"Job","spec":{}}`,
+		"namespace":     "batch",
+		"server":        srv.URL,
+		"poll_interval": "10ms",
+		"delete":        "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.(*KubernetesJob).Token = "secret"
+
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "Job batch/backup-x1 succeeded" {
+		t.Errorf("msg = %q", msg)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	metadata := created["metadata"].(map[string]interface{})
+	if metadata["generateName"] != "backup-" || metadata["namespace"] != "batch" {
+		t.Errorf("metadata = %v", metadata)
+	}
+	if polls != 2 || !deleted {
+		t.Errorf("polls = %d, deleted = %v", polls, deleted)
+	}
+}
+
+func TestKubernetesJobTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"metadata":{"name":"slow"}}`))
+	}))
+	defer srv.Close()
+
+	job := NewKubernetesJob("slow", []byte(`{}`))
+	job.Server = srv.URL
+	job.PollInterval = 10 * time.Millisecond
+	job.Timeout = 50 * time.Millisecond
+	if _, err := job.Run(); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want timeout", err)
+	}
+	if _, err := newJob(KubernetesJobType, "bad", map[string]string{"template": "{"}); err == nil {
+		t.Error("expected an invalid template to be rejected")
+	}
+}
@@ -0,0 +1,237 @@
+package cron
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LambdaJobType is the job type of LambdaJob.
+const LambdaJobType = "lambda"
+
+// LambdaJob synchronously invokes an AWS Lambda function with a JSON payload
+// and reports the function's response as the result message. Function
+// errors are reported as job errors.
+//
+// Credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables. Functions triggered over plain
+// HTTP, such as Lambda function URLs or other providers' cloud functions,
+// are better run with HTTPRequestJob.
+type LambdaJob struct {
+	id string
+
+	// Function is the function name or ARN.
+	Function string
+	// Qualifier selects a version or alias of the function.
+	Qualifier string
+	Region    string
+	// Payload is the JSON event passed to the function.
+	Payload string
+	// Endpoint overrides the Lambda endpoint of the region.
+	Endpoint string
+	Timeout  time.Duration
+	Client   *http.Client
+}
+
+// NewLambdaJob returns a job with the given id invoking function in region.
+func NewLambdaJob(id, function, region, payload string) *LambdaJob {
+	return &LambdaJob{id: id, Function: function, Region: region, Payload: payload}
+}
+
+func init() {
+	RegisterJobType(LambdaJobType, newLambdaJob)
+}
+
+// newLambdaJob builds a LambdaJob from the "function", "qualifier",
+// "region", "payload", "endpoint" and "timeout" parameters. The region
+// defaults to AWS_REGION.
+func newLambdaJob(id string, params map[string]string) (Job, error) {
+	region := params["region"]
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	j := NewLambdaJob(id, params["function"], region, params["payload"])
+	if j.Function == "" || j.Region == "" {
+		return nil, fmt.Errorf("Missing function or region parameter")
+	}
+	j.Qualifier = params["qualifier"]
+	j.Endpoint = params["endpoint"]
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	return j, nil
+}
+
+func (j *LambdaJob) ID() string { return j.id }
+
+func (j *LambdaJob) JobType() string { return LambdaJobType }
+
+func (j *LambdaJob) Params() map[string]string {
+	params := map[string]string{"function": j.Function, "region": j.Region, "payload": j.Payload}
+	if j.Qualifier != "" {
+		params["qualifier"] = j.Qualifier
+	}
+	if j.Endpoint != "" {
+		params["endpoint"] = j.Endpoint
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	return params
+}
+
+// Run invokes the function and waits for its response.
+func (j *LambdaJob) Run() (msg string, err error) {
+	endpoint := j.Endpoint
+	if endpoint == "" {
+		endpoint = "https://lambda." + j.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Invalid endpoint %s: %s", endpoint, err)
+	}
+	u.RawPath = "/2015-03-31/functions/" + awsURIEncode(j.Function) + "/invocations"
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	if j.Qualifier != "" {
+		u.RawQuery = url.Values{"Qualifier": {j.Qualifier}}.Encode()
+	}
+
+	payload := []byte(j.Payload)
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Amz-Invocation-Type", "RequestResponse")
+	signAWSv4(req, payload, os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		os.Getenv("AWS_SESSION_TOKEN"), j.Region, "lambda", time.Now())
+
+	client := j.Client
+	if client == nil {
+		client = &http.Client{Timeout: j.Timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("Failed to invoke %s: %s %s", j.Function, resp.Status, bytes.TrimSpace(body))
+	}
+	if fnErr := resp.Header.Get("X-Amz-Function-Error"); fnErr != "" {
+		return string(body), fmt.Errorf("Function %s failed (%s): %s", j.Function, fnErr, bytes.TrimSpace(body))
+	}
+	return string(body), nil
+}
+
+// signAWSv4 signs the request with AWS Signature Version 4. The host and
+// every header already set on the request are signed.
+func signAWSv4(req *http.Request, body []byte, accessKey, secretKey, token, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, s := range segments {
+		segments[i] = awsURIEncode(s)
+	}
+	path := strings.Join(segments, "/")
+	if path == "" {
+		path = "/"
+	}
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, awsURIEncode(k)+"="+awsURIEncode(v))
+		}
+	}
+
+	payloadHash := sha256.Sum256(body)
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		strings.Join(pairs, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+
+	date := amzDate[:8]
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsURIEncode percent-encodes every byte except the unreserved characters,
+// as required by Signature Version 4.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignAWSv4 checks the signer against the example of the AWS
+// Signature Version 4 documentation.
+func TestSignAWSv4(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signAWSv4(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "", "us-east-1", "iam", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %s\nwant %s", got, want)
+	}
+}
+
+func TestLambdaJob(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.EscapedPath() != "/2015-03-31/functions/arn%3Aaws%3Alambda%3Aeu-west-1%3A1%3Afunction%3Areport/invocations" ||
+			r.URL.Query().Get("Qualifier") != "live" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if string(body) == `{"fail":true}` {
+			w.Header().Set("X-Amz-Function-Error", "Unhandled")
+			w.Write([]byte(`{"errorMessage":"boom"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	job, err := newJob(LambdaJobType, "report", map[string]string{
+		"function":  "arn:aws:lambda:eu-west-1:1:function:report",
+		"qualifier": "live",
+		"region":    "eu-west-1",
+		"payload":   `{"day":"today"}`,
+		"endpoint":  srv.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := job.Run(); err != nil || msg != `{"ok":true}` {
+		t.Errorf("Run() = %q, %v", msg, err)
+	}
+
+	job.(*LambdaJob).Payload = `{"fail":true}`
+	if _, err := job.Run(); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("err = %v, want function error", err)
+	}
+}
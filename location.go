@@ -0,0 +1,67 @@
+package cron
+
+import "time"
+
+// WithEntryLocation makes the schedule of the entry be evaluated in the
+// given time zone rather than in the one of the Cron, which lets schedules
+// built programmatically, such as a ConstantDelaySchedule or a custom
+// Schedule, run on the wall clock of a specific zone. A CRON_TZ or TZ prefix
+// of the spec takes precedence.
+func WithEntryLocation(loc *time.Location) EntryOption {
+	return func(e *Entry) {
+		e.Location = loc
+	}
+}
+
+// SetLocation changes the time zone the schedules of the entries are
+// evaluated in, except for the entries with a time zone of their own. The
+// next run of every entry is recomputed in the new time zone right away.
+func (c *Cron) SetLocation(loc *time.Location) {
+	c.do(func() {
+		c.locationMu.Lock()
+		c.location = loc
+		c.locationMu.Unlock()
+		if !c.running {
+			return
+		}
+		now := c.now()
+		for _, e := range c.queue {
+			e.Next = nextRun(e, now)
+		}
+		c.requeueAll()
+	})
+}
+
+// locatedSchedule evaluates a schedule in a time zone.
+type locatedSchedule struct {
+	Schedule
+	location *time.Location
+}
+
+// Next returns the next activation time of the schedule evaluated in its
+// time zone, in the time zone of t.
+func (s locatedSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t.In(s.location))
+	if next.IsZero() {
+		return next
+	}
+	return next.In(t.Location())
+}
+
+// locate makes the schedule of e be evaluated in the location of the entry,
+// if it has one.
+func (e *Entry) locate() {
+	s := unlocated(e.Schedule)
+	if e.Location != nil {
+		s = locatedSchedule{s, e.Location}
+	}
+	e.Schedule = s
+}
+
+// unlocated returns the schedule s evaluates in its time zone.
+func unlocated(s Schedule) Schedule {
+	if ls, ok := s.(locatedSchedule); ok {
+		return ls.Schedule
+	}
+	return s
+}
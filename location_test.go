@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWithEntryLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddJob("0 0 9 * * *", &testDescribedJob{"ny", "x"}, WithEntryLocation(ny))
+	c.AddJob("CRON_TZ=Asia/Tokyo 0 0 9 * * *", &testDescribedJob{"tokyo", "x"}, WithEntryLocation(ny))
+	c.Schedule(Every(time.Hour), &testDescribedJob{"every", "x"}, WithEntryLocation(ny))
+	c.Start()
+	defer c.Stop()
+
+	for id, want := range map[string]time.Time{
+		"ny":    time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC),
+		"tokyo": time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC),
+		"every": time.Date(2020, 1, 6, 1, 0, 0, 0, time.UTC),
+	} {
+		e, _ := c.Entry(id)
+		if !e.Next.Equal(want) || e.Next.Location() != time.UTC || e.Location != ny {
+			t.Errorf("%s: expected the next run at %v, got %v in %v", id, want, e.Next, e.Location)
+		}
+	}
+
+	data, err := json.Marshal(c.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc Snapshot
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	restored := New(WithLocation(time.UTC))
+	if err := restored.Restore(&doc); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := restored.Entry("every")
+	if e.Location == nil || e.Location.String() != "America/New_York" || e.Spec != "@every 1h0m0s" {
+		t.Errorf("expected the location restored, got %+v", e)
+	}
+	if next := e.Schedule.Next(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)); next.Location() != time.UTC {
+		t.Errorf("expected the next run in UTC, got %v", next)
+	}
+}
+
+func TestSetLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddJob("0 0 9 * * *", &testDescribedJob{"local", "x"})
+	c.AddJob("0 0 9 * * *", &testDescribedJob{"tokyo", "x"}, WithEntryLocation(tokyo))
+	c.Start()
+	defer c.Stop()
+
+	c.SetLocation(ny)
+	if c.Location() != ny {
+		t.Errorf("expected the location changed, got %v", c.Location())
+	}
+	for id, want := range map[string]time.Time{
+		"local": time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC),
+		"tokyo": time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC),
+	} {
+		if e, _ := c.Entry(id); !e.Next.Equal(want) {
+			t.Errorf("%s: expected the next run at %v, got %v", id, want, e.Next)
+		}
+	}
+
+	clock.Advance(14 * time.Hour)
+	if runs := c.History("local"); len(runs) != 1 || !runs[0].Start.Equal(time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected a run at 9:00 in New York, got %+v", runs)
+	}
+}
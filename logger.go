@@ -0,0 +1,133 @@
+package cron
+
+import (
+	"context"
+	"log"
+)
+
+// WithEntryLogger logs the scheduler messages about the entry, such as its
+// panics, skipped runs or opened circuit breaker, to l instead of the error
+// log of the Cron, and passes l to its job through the context of its runs,
+// see RunLogger. A logger writing to ioutil.Discard silences the entry.
+func WithEntryLogger(l *log.Logger) EntryOption {
+	return func(e *Entry) {
+		e.Logger = l
+	}
+}
+
+// WithLogPrefix prefixes the scheduler messages about the entry, and the
+// messages of the logger passed to its job, with prefix, e.g. "job=backup ",
+// so that they can be told apart and routed. It applies to the logger of
+// WithEntryLogger if any, or else to the error log of the Cron.
+func WithLogPrefix(prefix string) EntryOption {
+	return func(e *Entry) {
+		e.LogPrefix = prefix
+	}
+}
+
+type loggerKey struct{}
+
+// RunLogger returns the logger passed to the run given ctx: the one of
+// WithEntryLogger, or the error log of the Cron, or a logger writing like
+// the standard logger if the Cron has none, with the prefix of
+// WithLogPrefix. Outside of a run, it returns a logger writing like the
+// standard logger.
+func RunLogger(ctx context.Context) *log.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*log.Logger); ok {
+		return l
+	}
+	return standardLogger()
+}
+
+func standardLogger() *log.Logger {
+	return log.New(log.Writer(), log.Prefix(), log.Flags())
+}
+
+// entryLogs are the loggers of the messages about an entry.
+type entryLogs struct {
+	// logger is the logger of the messages, or nil for the error log of
+	// the Cron.
+	logger *log.Logger
+	// errors is the logger of the messages reporting failures, or nil for
+	// logger.
+	errors *log.Logger
+}
+
+// prefixed returns l with the log prefix of the entry added.
+func prefixed(l *log.Logger, e *Entry) *log.Logger {
+	if l == nil || e.LogPrefix == "" {
+		return l
+	}
+	return log.New(l.Writer(), l.Prefix()+e.LogPrefix, l.Flags())
+}
+
+// entryLogs returns the loggers of the entry.
+func (c *Cron) entryLogs(e *Entry) entryLogs {
+	logs := entryLogs{logger: e.Logger, errors: prefixed(e.ErrorLog, e)}
+	if logs.logger == nil && e.LogPrefix != "" {
+		logs.logger = c.ErrorLog
+		if logs.logger == nil {
+			logs.logger = standardLogger()
+		}
+	}
+	logs.logger = prefixed(logs.logger, e)
+	return logs
+}
+
+// setLoggers records the loggers of the entry e with the given id, or
+// forgets them if e is nil.
+func (c *Cron) setLoggers(id string, e *Entry) {
+	var logs entryLogs
+	if e != nil {
+		logs = c.entryLogs(e)
+	}
+	c.loggerMu.Lock()
+	defer c.loggerMu.Unlock()
+	if logs == (entryLogs{}) {
+		delete(c.loggers, id)
+		return
+	}
+	if c.loggers == nil {
+		c.loggers = make(map[string]entryLogs)
+	}
+	c.loggers[id] = logs
+}
+
+// loggersOf returns the loggers of the entry with the given id.
+func (c *Cron) loggersOf(id string) entryLogs {
+	c.loggerMu.RLock()
+	defer c.loggerMu.RUnlock()
+	return c.loggers[id]
+}
+
+// runLogger returns the logger to pass to the runs of the job with the
+// given id.
+func (c *Cron) runLogger(id string) *log.Logger {
+	if l := c.loggersOf(id).logger; l != nil {
+		return l
+	}
+	if c.ErrorLog != nil {
+		return c.ErrorLog
+	}
+	return standardLogger()
+}
+
+// entryLogf logs a message about the entry with the given id to its logger,
+// or to the error log of the Cron.
+func (c *Cron) entryLogf(id string, format string, args ...interface{}) {
+	if l := c.loggersOf(id).logger; l != nil {
+		l.Printf(format, args...)
+		return
+	}
+	c.logf(format, args...)
+}
+
+// entryErrorf logs a failure of the entry with the given id to its error
+// log, or like entryLogf.
+func (c *Cron) entryErrorf(id string, format string, args ...interface{}) {
+	if l := c.loggersOf(id).errors; l != nil {
+		l.Printf(format, args...)
+		return
+	}
+	c.entryLogf(id, format, args...)
+}
@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// loggingJob logs to the logger of its run, then panics.
+type loggingJob struct{ id string }
+
+func (j *loggingJob) ID() string           { return j.id }
+func (j *loggingJob) Run() (string, error) { return j.RunContext(context.Background()) }
+
+func (j *loggingJob) RunContext(ctx context.Context) (string, error) {
+	RunLogger(ctx).Printf("running %s", j.id)
+	panic("boom")
+}
+
+func TestEntryLogger(t *testing.T) {
+	var cronLog, entryLog syncBuffer
+	c := New(WithLogger(log.New(&cronLog, "", 0)))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	c.AddJob("@yearly", &loggingJob{"noisy"}, WithEntryLogger(log.New(&entryLog, "", 0)), WithLogPrefix("job=noisy "))
+	c.AddJob("@yearly", &loggingJob{"prefixed"}, WithLogPrefix("job=prefixed "))
+	c.AddJob("@yearly", &loggingJob{"plain"})
+	c.Start()
+	defer c.Stop()
+	for _, id := range []string{"noisy", "prefixed", "plain"} {
+		c.RunNow(id)
+		for e := range events {
+			if e.Type == EventJobFinished {
+				break
+			}
+		}
+	}
+
+	for _, want := range []string{"job=noisy running noisy\n", "job=noisy cron: panic running job: boom"} {
+		if !strings.Contains(entryLog.String(), want) {
+			t.Errorf("expected the entry log to contain %q, got:\n%s", want, entryLog.String())
+		}
+	}
+	for _, want := range []string{
+		"job=prefixed running prefixed\n",
+		"job=prefixed cron: panic running job: boom",
+		"running plain\n",
+	} {
+		if !strings.Contains(cronLog.String(), want) {
+			t.Errorf("expected the cron log to contain %q, got:\n%s", want, cronLog.String())
+		}
+	}
+	if strings.Contains(cronLog.String(), "noisy") {
+		t.Errorf("expected no message about noisy in the cron log, got:\n%s", cronLog.String())
+	}
+
+	c.RemoveJob("noisy")
+	if c.loggersOf("noisy") != (entryLogs{}) {
+		t.Error("expected the logger of the removed entry to be forgotten")
+	}
+	if RunLogger(context.Background()) == nil {
+		t.Error("expected a logger outside of runs")
+	}
+}
@@ -0,0 +1,200 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// EmailJobType is the job type of EmailJob.
+const EmailJobType = "email"
+
+// sendMail sends a message; it is replaced in tests.
+var sendMail = smtp.SendMail
+
+// Mailer sends plain text email through an SMTP server.
+type Mailer struct {
+	// Addr is the "host:port" of the SMTP server.
+	Addr string
+	// Auth authenticates to the server. Nil means no authentication.
+	Auth smtp.Auth
+	From string
+}
+
+// Send sends a message to the recipients.
+func (m *Mailer) Send(to []string, subject, body string) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+	if err := sendMail(m.Addr, m.Auth, m.From, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("Failed to send mail to %s: %s", strings.Join(to, ", "), err)
+	}
+	return nil
+}
+
+// EmailJob sends an email on each run. Subject and Body are text/template
+// templates executed with the job ID and the time of the run, as in
+// "Report for {{.Time.Format \"2006-01-02\"}}".
+type EmailJob struct {
+	id string
+	Mailer
+	To      []string
+	Subject string
+	Body    string
+
+	// passwordEnv names the environment variable holding the SMTP password.
+	username, passwordEnv string
+}
+
+// NewEmailJob returns a job with the given id sending mail through m.
+func NewEmailJob(id string, m Mailer, to []string, subject, body string) *EmailJob {
+	return &EmailJob{id: id, Mailer: m, To: to, Subject: subject, Body: body}
+}
+
+func init() {
+	RegisterJobType(EmailJobType, newEmailJob)
+}
+
+// newEmailJob builds an EmailJob from the "addr", "from", "to", "subject",
+// "body", "username" and "password_env" parameters. Recipients in "to" are
+// separated by commas. The password is read from the environment variable
+// named by "password_env" so it is not stored with the job.
+func newEmailJob(id string, params map[string]string) (Job, error) {
+	j := NewEmailJob(id, Mailer{Addr: params["addr"], From: params["from"]}, nil, params["subject"], params["body"])
+	for _, to := range strings.Split(params["to"], ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			j.To = append(j.To, to)
+		}
+	}
+	if j.Addr == "" || j.From == "" || len(j.To) == 0 {
+		return nil, fmt.Errorf("Missing addr, from or to parameter")
+	}
+	for _, text := range []string{j.Subject, j.Body} {
+		if _, err := template.New("").Parse(text); err != nil {
+			return nil, fmt.Errorf("Failed to parse template: %s", err)
+		}
+	}
+	j.username, j.passwordEnv = params["username"], params["password_env"]
+	if j.username != "" {
+		host, _, err := net.SplitHostPort(j.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid addr %s: %s", j.Addr, err)
+		}
+		j.Auth = smtp.PlainAuth("", j.username, os.Getenv(j.passwordEnv), host)
+	}
+	return j, nil
+}
+
+func (j *EmailJob) ID() string { return j.id }
+
+func (j *EmailJob) JobType() string { return EmailJobType }
+
+func (j *EmailJob) Params() map[string]string {
+	params := map[string]string{
+		"addr":    j.Addr,
+		"from":    j.From,
+		"to":      strings.Join(j.To, ","),
+		"subject": j.Subject,
+		"body":    j.Body,
+	}
+	if j.username != "" {
+		params["username"] = j.username
+		params["password_env"] = j.passwordEnv
+	}
+	return params
+}
+
+// Run renders the templates and sends the email.
+func (j *EmailJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext renders the templates at the time of the clock of the run, see
+// RunClock, and sends the email.
+func (j *EmailJob) RunContext(ctx context.Context) (msg string, err error) {
+	now := RunClock(ctx).Now()
+	subject, err := executeTemplate(j.id, j.Subject, now)
+	if err != nil {
+		return "", err
+	}
+	body, err := executeTemplate(j.id, j.Body, now)
+	if err != nil {
+		return "", err
+	}
+	if err := j.Send(j.To, subject, body); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Sent %q to %s", subject, strings.Join(j.To, ", ")), nil
+}
+
+// FailureMailer emails recipients when a job fails Threshold times in a row.
+// One email is sent per streak of failures; a successful run resets it.
+type FailureMailer struct {
+	Mailer
+	To []string
+	// Threshold is the number of consecutive failures triggering an email.
+	// Values below 1 mean 1.
+	Threshold int
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewFailureMailer returns a FailureMailer emailing to after threshold
+// consecutive failures.
+func NewFailureMailer(m Mailer, threshold int, to ...string) *FailureMailer {
+	return &FailureMailer{Mailer: m, To: to, Threshold: threshold}
+}
+
+// Attach subscribes the mailer to the results of c. Calling detach stops it.
+func (f *FailureMailer) Attach(c *Cron, buffer int) (detach func()) {
+	results, cancel := c.SubscribeResults(buffer)
+	go func() {
+		for r := range results {
+			f.Notify(r)
+		}
+	}()
+	return cancel
+}
+
+// Notify records a result and sends an email if the job reached the failure
+// threshold.
+func (f *FailureMailer) Notify(r *JobResult) error {
+	threshold := f.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	f.mu.Lock()
+	if f.failures == nil {
+		f.failures = make(map[string]int)
+	}
+	if r.Error == nil {
+		delete(f.failures, r.JobId)
+		f.mu.Unlock()
+		return nil
+	}
+	f.failures[r.JobId]++
+	n := f.failures[r.JobId]
+	f.mu.Unlock()
+	if n != threshold {
+		return nil
+	}
+	subject := fmt.Sprintf("Cron job %s failed %d times in a row", r.JobId, n)
+	body := fmt.Sprintf("Job %s failed %d times in a row.\n\nLast error: %s\n", r.JobId, n, r.Error)
+	if r.Msg != "" {
+		body += "\nOutput:\n" + r.Msg + "\n"
+	}
+	return f.Send(f.To, subject, body)
+}
@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+type sentMail struct {
+	addr string
+	from string
+	to   []string
+	msg  string
+}
+
+// captureMail replaces sendMail for the duration of a test.
+func captureMail(t *testing.T) *[]sentMail {
+	var sent []sentMail
+	sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, sentMail{addr, from, to, string(msg)})
+		return nil
+	}
+	t.Cleanup(func() { sendMail = smtp.SendMail })
+	return &sent
+}
+
+func TestEmailJob(t *testing.T) {
+	sent := captureMail(t)
+	job, err := newJob(EmailJobType, "report", map[string]string{
+		"addr":    "smtp.example.com:25",
+		"from":    "cron@example.com",
+		"to":      "a@example.com, b@example.com",
+		"subject": "Report {{.ID}}",
+		"body":    "line 1\nline 2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := job.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("sent %d mails, want 1", len(*sent))
+	}
+	m := (*sent)[0]
+	if m.addr != "smtp.example.com:25" || len(m.to) != 2 || m.to[1] != "b@example.com" {
+		t.Errorf("sent %+v", m)
+	}
+	if !strings.Contains(m.msg, "Subject: Report report\r\n") || !strings.HasSuffix(m.msg, "\r\n\r\nline 1\r\nline 2") {
+		t.Errorf("msg = %q", m.msg)
+	}
+
+	if _, err := newJob(EmailJobType, "bad", map[string]string{"addr": "x:25", "from": "a", "to": "b", "body": "{{"}); err == nil {
+		t.Error("expected an invalid template to be rejected")
+	}
+}
+
+func TestFailureMailer(t *testing.T) {
+	sent := captureMail(t)
+	f := NewFailureMailer(Mailer{Addr: "smtp:25", From: "cron@example.com"}, 2, "ops@example.com")
+	fail := &JobResult{JobId: "a", Error: errors.New("boom")}
+	for _, r := range []*JobResult{fail, {JobId: "a"}, fail, fail, fail, {JobId: "b", Error: errors.New("x")}} {
+		if err := f.Notify(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0].msg, "Cron job a failed 2 times") {
+		t.Errorf("sent %+v", *sent)
+	}
+}
@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// MisfirePolicy tells what to do with the runs of an entry the scheduler
+// missed, e.g. because the system was suspended or the clock jumped forward.
+type MisfirePolicy string
+
+const (
+	// MisfireRunOnce runs the job once for all its missed runs. Entries
+	// without a policy behave this way.
+	MisfireRunOnce MisfirePolicy = "run_once"
+	// MisfireSkip drops the missed runs: the job next runs on its schedule.
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireRunAll runs the job once for every missed run.
+	MisfireRunAll MisfirePolicy = "run_all"
+)
+
+// DefaultJumpThreshold is how late or early the scheduler may wake up before
+// it considers the clock jumped.
+const DefaultJumpThreshold = time.Minute
+
+// WithJumpThreshold sets how late or early the scheduler may wake up before
+// it considers the clock jumped. When it wakes up late, the runs due more
+// than d before are missed, and handled by the misfire policy of their entry.
+// When it wakes up early, the clock was stepped back and the next run of
+// every entry is computed again.
+func WithJumpThreshold(d time.Duration) Option {
+	return func(c *Cron) {
+		c.jumpThreshold = d
+	}
+}
+
+// SetMisfirePolicy sets the misfire policy of the entry of the job with the
+// given id.
+func (c *Cron) SetMisfirePolicy(id string, p MisfirePolicy) (err error) {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		e.Misfire = p
+	})
+	return err
+}
+
+func (p MisfirePolicy) validate() error {
+	switch p {
+	case "", MisfireRunOnce, MisfireSkip, MisfireRunAll:
+		return nil
+	}
+	return fmt.Errorf("Unknown misfire policy %q", p)
+}
+
+// clockJump returns how much the clock jumped given that the run loop,
+// expecting to wake up at wake, woke up at now. It is zero unless the
+// difference exceeds the jump threshold.
+func (c *Cron) clockJump(wake, now time.Time) time.Duration {
+	jump := now.Sub(wake)
+	var msg string
+	switch {
+	case jump > c.jumpThreshold:
+		msg = fmt.Sprintf("Clock jumped forward by %s", jump)
+	case -jump > c.jumpThreshold:
+		msg = fmt.Sprintf("Clock jumped back by %s", -jump)
+	default:
+		return 0
+	}
+	c.logf("cron: %s", msg)
+	c.emit(Event{Type: EventClockJumped, Time: now, Msg: msg})
+	return jump
+}
+
+// reschedule computes the next run of every entry again, after the clock
+// was stepped back.
+func (c *Cron) reschedule(now time.Time) {
+	for _, e := range c.queue {
+		e.Next = nextRun(e, now)
+	}
+	c.requeueAll()
+}
+
+// nextRun returns the next run of e after now, or the zero time if e will
+// not run again. The runs up to the last one of e are skipped, so that none
+// runs twice if the clock was stepped back.
+func nextRun(e *Entry, now time.Time) time.Time {
+	if e.exhausted() {
+		return time.Time{}
+	}
+	if e.Prev.After(now) {
+		now = e.Prev
+	}
+	if _, ok := unlocated(e.Schedule).(AfterSchedule); ok {
+		return e.nextTrigger(now)
+	}
+	return e.Schedule.Next(now)
+}
+
+// misfire applies the misfire policy of e, whose runs from e.Next to now
+// were missed.
+func (c *Cron) misfire(e *Entry, now time.Time) {
+	switch e.Misfire {
+	case MisfireSkip:
+		c.entryLogf(e.Job.ID(), "cron: skipping the runs of %s missed since %s", e.Job.ID(), e.Next.Format(time.RFC3339))
+	case MisfireRunAll:
+		for t := e.Next; !t.IsZero() && !t.After(now) && !e.exhausted(); t = e.Schedule.Next(t) {
+			c.fire(e, t)
+		}
+	default:
+		c.fire(e, e.Next)
+	}
+}
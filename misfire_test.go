@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestMisfirePolicy(t *testing.T) {
+	tests := []struct {
+		policy MisfirePolicy
+		runs   int
+	}{
+		{"", 1},
+		{MisfireRunOnce, 1},
+		{MisfireSkip, 0},
+		{MisfireRunAll, 61},
+	}
+	for _, test := range tests {
+		start := time.Date(2020, 1, 1, 10, 0, 30, 0, time.UTC)
+		clock := NewFakeClock(start)
+		c := New(WithClock(clock), WithLocation(time.UTC))
+		c.ErrorLog = log.New(ioutil.Discard, "", 0)
+		events, cancel := c.SubscribeEvents(10)
+		runs := 0
+		id, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+			runs++
+			return "", nil
+		})
+		if err := c.SetMisfirePolicy(id, test.policy); err != nil {
+			t.Fatal(err)
+		}
+		c.Start()
+
+		// Suspended for an hour before the run due at 10:01.
+		clock.Jump(time.Hour)
+		clock.Advance(30 * time.Second)
+		if runs != test.runs {
+			t.Errorf("%q: expected %d runs, got %d", test.policy, test.runs, runs)
+		}
+		next, _ := c.NextRun(id)
+		if want := start.Add(time.Hour + 90*time.Second); !next.Equal(want) {
+			t.Errorf("%q: expected the next run at %v, got %v", test.policy, want, next)
+		}
+		var jumps int
+		for len(events) > 0 {
+			if e := <-events; e.Type == EventClockJumped {
+				jumps++
+			}
+		}
+		if jumps != 1 {
+			t.Errorf("%q: expected 1 clock jump, got %d", test.policy, jumps)
+		}
+
+		// Back on schedule.
+		runs = 0
+		clock.Advance(time.Minute)
+		if runs != 1 {
+			t.Errorf("%q: expected 1 run after the jump, got %d", test.policy, runs)
+		}
+		c.Stop()
+		cancel()
+	}
+}
+
+func TestMisfireWithinThreshold(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 10, 0, 30, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC), WithJumpThreshold(time.Hour))
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	runs := 0
+	id, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+		runs++
+		return "", nil
+	})
+	c.SetMisfirePolicy(id, MisfireSkip)
+	c.Start()
+	defer c.Stop()
+
+	clock.Jump(10 * time.Minute)
+	clock.Advance(30 * time.Second)
+	if runs != 1 {
+		t.Errorf("expected 1 run, got %d", runs)
+	}
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventClockJumped {
+			t.Errorf("unexpected event %+v", e)
+		}
+	}
+}
+
+func TestSetMisfirePolicy(t *testing.T) {
+	c := New()
+	if err := c.SetMisfirePolicy("missing", MisfireSkip); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	id, _ := c.AddFunc("@hourly", func() (string, error) { return "", nil })
+	if err := c.SetMisfirePolicy(id, "later"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+	if _, err := (JobConfig{Name: "job", Spec: "@hourly", Type: "shell", Misfire: "later"}).entry(DefaultSpecCache); err == nil {
+		t.Error("expected an unknown policy in a config to be rejected")
+	}
+}
+
+func TestClockSteppedBack(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	var delayed []time.Time
+	c.Schedule(Every(10*time.Minute), FuncJob(func() (string, error) {
+		delayed = append(delayed, clock.Now())
+		return "", nil
+	}))
+	c.Start()
+	defer c.Stop()
+
+	// Stepped back an hour before the run due at 10:10, which now comes
+	// at 9:10. The next run is computed again from there.
+	clock.Advance(5 * time.Minute)
+	clock.Jump(-time.Hour)
+	clock.Advance(15 * time.Minute)
+	want := []time.Time{start.Add(-40 * time.Minute)}
+	if len(delayed) != len(want) || !delayed[0].Equal(want[0]) {
+		t.Errorf("expected runs at %v, got %v", want, delayed)
+	}
+	var jumps int
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventClockJumped {
+			jumps++
+		}
+	}
+	if jumps != 1 {
+		t.Errorf("expected 1 clock jump, got %d", jumps)
+	}
+}
+
+func TestClockSteppedBackRunsOnce(t *testing.T) {
+	start := time.Date(2020, 1, 1, 9, 59, 30, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	runs := 0
+	c.AddFunc("0 0 * * * *", func() (string, error) {
+		runs++
+		return "", nil
+	})
+	c.Start()
+	clock.Advance(30*time.Minute + 30*time.Second)
+	if runs != 1 {
+		t.Fatalf("expected 1 run, got %d", runs)
+	}
+
+	// Stepped back to 9:30 and restarted: the run of 10:00 is not run again.
+	clock.Jump(-time.Hour)
+	c.Stop()
+	c.Start()
+	defer c.Stop()
+	clock.Advance(time.Hour)
+	if runs != 1 {
+		t.Errorf("expected the run of 10:00 not to run again, got %d runs", runs)
+	}
+	clock.Advance(30 * time.Minute)
+	if runs != 2 {
+		t.Errorf("expected the run of 11:00, got %d runs", runs)
+	}
+}
@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"container/heap"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// WithObserver puts the Cron in observer mode, attached to the store shared
+// with the scheduler that runs the jobs, e.g. the leader of a leader-elected
+// deployment. The entries are mirrored from the store when the Cron is
+// started and every interval after that, so their upcoming runs can be
+// displayed and monitored, but jobs are never run and the store is never
+// saved. The job types of the mirrored entries must be registered.
+//
+// Entries added to an observer locally are dropped by the next refresh,
+// unless the store holds them too.
+func WithObserver(s Store, interval time.Duration) Option {
+	return func(c *Cron) {
+		c.store = s
+		c.observer = true
+		c.observeEvery = interval
+	}
+}
+
+// Observer reports whether the Cron is in observer mode.
+func (c *Cron) Observer() bool {
+	return c.observer
+}
+
+// Refresh mirrors the entries of the store of an observer right away. The
+// entries missing from the store are removed, the new ones added and the
+// changed ones replaced; the others only take the last run time found in the
+// store.
+func (c *Cron) Refresh() error {
+	if !c.observer {
+		return fmt.Errorf("Refresh needs observer mode")
+	}
+	s, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		s = &Snapshot{Version: snapshotVersion}
+	}
+	entries, err := c.restoredEntries(s)
+	if err != nil {
+		return err
+	}
+
+	var events []Event
+	c.do(func() {
+		mirrored := make(map[string]bool, len(entries))
+		for _, e := range entries {
+			id := e.Job.ID()
+			mirrored[id] = true
+			old, ok := c.entries[id]
+			if ok && sameDefinition(old, e) {
+				if e.Prev.After(old.Prev) {
+					old.Prev = e.Prev
+				}
+				continue
+			}
+			if err := c.insert(id, e, true); err != nil {
+				c.logf("cron: failed to mirror entry %s: %s", id, err)
+				continue
+			}
+			events = append(events, Event{Type: EventEntryAdded, EntryID: id})
+		}
+		for id, e := range c.entries {
+			if mirrored[id] {
+				continue
+			}
+			if c.running {
+				heap.Remove(&c.queue, e.index)
+			}
+			delete(c.entries, id)
+			c.setLoggers(id, nil)
+			c.changed(ChangeRemoved, id, nil)
+			events = append(events, Event{Type: EventEntryRemoved, EntryID: id})
+		}
+	})
+	for _, e := range events {
+		if e.Type == EventEntryRemoved {
+			c.history.forget(e.EntryID)
+		}
+		c.emit(e)
+	}
+	return nil
+}
+
+// sameDefinition reports whether the entries run the same job on the same
+// spec.
+func sameDefinition(a, b *Entry) bool {
+	typeA, paramsA := describe(a.Job)
+	typeB, paramsB := describe(b.Job)
+	return entrySpec(a) == entrySpec(b) && a.Name == b.Name &&
+		typeA == typeB && reflect.DeepEqual(paramsA, paramsB)
+}
+
+// observe refreshes the entries of an observer every interval until the
+// scheduler stops.
+func (c *Cron) observe(halt chan struct{}) {
+	if c.observeEvery <= 0 {
+		return
+	}
+	timer := c.clock.NewTimer(c.observeEvery)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C():
+			if err := c.Refresh(); err != nil {
+				c.logf("cron: failed to refresh entries from store: %s", err)
+			}
+			timer.Reset(c.observeEvery)
+		case <-halt:
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestObserver(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "cron.json"))
+	prev := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	leader := New()
+	leader.AddJob("0 0 * * * *", &testDescribedJob{"a", "first"})
+	leader.AddJob("0 30 * * * *", &testDescribedJob{"b", "second"})
+	leader.entries["a"].Prev = prev
+	if err := store.Save(leader.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 15, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithObserver(store, 0))
+	c.location = time.UTC
+	if !c.Observer() {
+		t.Fatal("expected observer mode")
+	}
+	c.Start()
+	defer c.Stop()
+
+	e, ok := c.Entry("b")
+	if !ok || !e.Next.Equal(time.Date(2020, 1, 6, 0, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected b mirrored with its next run, got %+v", e)
+	}
+	if e, _ := c.Entry("a"); !e.Prev.Equal(prev) {
+		t.Errorf("expected the last run of a from the store, got %v", e.Prev)
+	}
+
+	clock.Advance(time.Hour)
+	if runs := c.History("a"); len(runs) != 0 {
+		t.Errorf("expected no run, got %+v", runs)
+	}
+	if e, _ := c.Entry("a"); !e.Next.Equal(time.Date(2020, 1, 6, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the schedule of a to advance, got %v", e.Next)
+	}
+	if err := c.RunNow("a"); !errors.Is(err, ErrObserver) {
+		t.Errorf("RunNow() = %v, want ErrObserver", err)
+	}
+
+	// The leader changes a, removes b and adds c.
+	leader.AddJob("0 0 * * * *", &testDescribedJob{"a", "changed"})
+	leader.RemoveJob("b")
+	leader.AddJob("0 45 * * * *", &testDescribedJob{"c", "third"})
+	if err := store.Save(leader.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range c.Entries() {
+		ids = append(ids, e.Job.ID())
+	}
+	if !equalStrings(ids, []string{"c", "a"}) {
+		t.Errorf("expected entries c and a, got %v", ids)
+	}
+	if e, _ := c.Entry("a"); e.Job.(*testDescribedJob).name != "changed" || e.Version != 2 {
+		t.Errorf("expected a replaced, got %+v", e)
+	}
+
+	// Stopping does not overwrite the store of the leader.
+	c.AddJob("@hourly", &testDescribedJob{"local", "x"})
+	c.Stop()
+	s, err := store.Load()
+	if err != nil || len(s.Entries) != 2 {
+		t.Errorf("expected the store untouched, got %+v, %v", s, err)
+	}
+}
+
+func TestRefreshNeedsObserver(t *testing.T) {
+	if err := New().Refresh(); err == nil {
+		t.Error("expected an error outside observer mode")
+	}
+}
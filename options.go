@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"log"
+	"time"
+)
+
+// Option configures a Cron when it is created.
+type Option func(*Cron)
+
+// WithLocation makes the Cron interpret schedules in the given time zone
+// instead of the Local one.
+func WithLocation(location *time.Location) Option {
+	return func(c *Cron) {
+		c.location = location
+	}
+}
+
+// WithLogger makes the Cron log errors to the given logger, like setting
+// ErrorLog.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Cron) {
+		c.ErrorLog = logger
+	}
+}
+
+// ScheduleParser parses the specs given to AddFunc and AddJob. Parser
+// implements it.
+type ScheduleParser interface {
+	Parse(spec string) (Schedule, error)
+}
+
+// WithParser makes the Cron parse specs with the given parser, e.g. one
+// created by NewParser accepting other fields.
+func WithParser(p ScheduleParser) Option {
+	return func(c *Cron) {
+		c.parser = p
+	}
+}
+
+// JobWrapper decorates a job with behavior around its Run, such as logging
+// or locking. The returned job must keep the ID of the job it wraps.
+type JobWrapper func(Job) Job
+
+// WithChain wraps every job run by the Cron with the given wrappers, the
+// first one being the outermost. Entries keep the unwrapped job.
+func WithChain(wrappers ...JobWrapper) Option {
+	return func(c *Cron) {
+		c.wrappers = append(c.wrappers, wrappers...)
+	}
+}
+
+// wrap applies the chain of the Cron to j.
+func (c *Cron) wrap(j Job) Job {
+	for i := len(c.wrappers) - 1; i >= 0; i-- {
+		j = c.wrappers[i](j)
+	}
+	return j
+}
+
+// WithMaxConcurrent limits the number of jobs running at the same time to n.
+// Runs due while the limit is reached wait for another job to finish; see
+// WithNamespaceWeight for the order they then start in.
+func WithMaxConcurrent(n int) Option {
+	return func(c *Cron) {
+		c.maxConcurrent = n
+	}
+}
+
+// WithMaxEntries limits the number of entries of the Cron to n. Adding an
+// entry beyond it fails with ErrQuotaExceeded.
+func WithMaxEntries(n int) Option {
+	return func(c *Cron) {
+		c.maxEntries = n
+	}
+}
+
+// WithWorkers makes the Cron run jobs and result handlers on a pool of n
+// reused goroutines while it is running, instead of starting a goroutine for
+// each, so that no more than n of them run at a time. Up to depth of them
+// wait in a queue for a free worker. The queue being full, a run due is
+// skipped, with an EventJobSkipped event, and a result handler runs in the
+// goroutine of the job that produced the result, slowing the jobs down until
+// the workers catch up.
+func WithWorkers(n, depth int) Option {
+	return func(c *Cron) {
+		if n > 0 {
+			c.pool = newWorkerPool(n, depth)
+		}
+	}
+}
+
+// WithStore makes the Cron restore its entries from the store when it is
+// started and save them to it when it is stopped.
+func WithStore(s Store) Option {
+	return func(c *Cron) {
+		c.store = s
+	}
+}
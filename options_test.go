@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithLocationAndLogger(t *testing.T) {
+	loc := time.FixedZone("test", 3600)
+	var buf bytes.Buffer
+	c := New(WithLocation(loc), WithLogger(log.New(&buf, "", 0)))
+	if c.Location() != loc {
+		t.Errorf("expected location %v, got %v", loc, c.Location())
+	}
+	c.logf("hello")
+	if buf.String() != "hello\n" {
+		t.Errorf("expected the logger to be used, got %q", buf.String())
+	}
+}
+
+func TestWithParser(t *testing.T) {
+	c := New(WithParser(NewParser(Minute | Hour | Dom | Month | Dow)))
+	if _, err := c.AddFunc("30 9 * * *", func() (string, error) { return "", nil }); err != nil {
+		t.Fatalf("expected a five field spec to be accepted, got %s", err)
+	}
+	if _, err := c.AddFunc("0 30 9 * * *", func() (string, error) { return "", nil }); err == nil {
+		t.Error("expected a six field spec to be rejected")
+	}
+}
+
+type prefixJob struct {
+	Job
+	prefix string
+}
+
+func (j prefixJob) Run() (string, error) {
+	msg, err := j.Job.Run()
+	return j.prefix + msg, err
+}
+
+func TestWithChain(t *testing.T) {
+	prefix := func(p string) JobWrapper {
+		return func(j Job) Job { return prefixJob{j, p} }
+	}
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local))
+	c := New(WithClock(clock), WithChain(prefix("a"), prefix("b")))
+	var msg string
+	c.AddResultHandler(func(r *JobResult) { msg = r.Msg })
+	job := NewShellCommandJob("echo", "printf c")
+	c.AddJob("0 * * * * *", job)
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Minute)
+	if msg != "abc" {
+		t.Errorf("expected the first wrapper outermost, got %q", msg)
+	}
+	if e, _ := c.Entry("echo"); e.Job != job {
+		t.Error("expected the entry to keep the unwrapped job")
+	}
+}
+
+func TestWithMaxConcurrent(t *testing.T) {
+	c := New(WithMaxConcurrent(1))
+	c.AddResultHandler(func(*JobResult) {})
+	var (
+		mu              sync.Mutex
+		running, maxRun int
+		wg              sync.WaitGroup
+	)
+	job := func() (string, error) {
+		mu.Lock()
+		running++
+		if running > maxRun {
+			maxRun = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		wg.Done()
+		return "", nil
+	}
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go c.runWithRecovery(FuncJob(job), nil, slots{}, nil)
+	}
+	wg.Wait()
+	if maxRun != 1 {
+		t.Errorf("expected at most 1 job at a time, got %d", maxRun)
+	}
+}
+
+func TestWithStore(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "cron.json"))
+	c := New(WithStore(store))
+	c.AddJob("@hourly", NewShellCommandJob("backup", "true"))
+	c.Start()
+	c.Stop()
+
+	restored := New(WithStore(store))
+	restored.Start()
+	defer restored.Stop()
+	e, ok := restored.Entry("backup")
+	if !ok {
+		t.Fatal("expected the entry to be restored from the store")
+	}
+	if job := e.Job.(*ShellCommandJob); job.Command != "true" || e.Spec != "@hourly" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestFileStoreMissing(t *testing.T) {
+	s, err := FileStore(filepath.Join(t.TempDir(), "missing.json")).Load()
+	if s != nil || err != nil {
+		t.Errorf("expected nothing, got %v, %v", s, err)
+	}
+}
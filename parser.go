@@ -3,6 +3,7 @@ package cron
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,8 @@ const (
 	Dow                                 // Day of week field, default *
 	DowOptional                         // Optional day of week field, default *
 	Descriptor                          // Allow descriptors such as @monthly, @weekly, etc.
+	Year                                // Optional year field after the day of week, default *
+	Week                                // Optional ISO week of the year field after the year, default *
 )
 
 var places = []ParseOption{
@@ -32,6 +35,8 @@ var places = []ParseOption{
 	Dom,
 	Month,
 	Dow,
+	Year,
+	Week,
 }
 
 var defaults = []string{
@@ -41,6 +46,8 @@ var defaults = []string{
 	"*",
 	"*",
 	"*",
+	"*",
+	"*",
 }
 
 // A custom Parser that can be configured.
@@ -63,24 +70,84 @@ type Parser struct {
 //  subsParser := NewParser(Dom | Month | DowOptional)
 //  sched, err := specParser.Parse("15 */3")
 //
+//  // Quartz-style year field, and every odd ISO week on Monday
+//  yearParser := NewParser(Second | Minute | Hour | Dom | Month | Dow | Year | Week)
+//  sched, err := yearParser.Parse("0 0 9 * * * 2025")
+//  sched, err := yearParser.Parse("0 0 9 * * MON * 1-53/2")
+//
+// The Year and Week fields are optional, and must follow a day of week
+// field when given.
 func NewParser(options ParseOption) Parser {
 	optionals := 0
 	if options&DowOptional > 0 {
 		options |= Dow
 		optionals++
 	}
+	if options&Year > 0 {
+		optionals++
+	}
+	if options&Week > 0 {
+		optionals++
+	}
 	return Parser{options, optionals}
 }
 
 // Parse returns a new crontab schedule representing the given spec.
 // It returns a descriptive error if the spec is not valid.
 // It accepts crontab specs and features configured by NewParser.
+//
+// A spec may start with a "CRON_TZ=Zone" or "TZ=Zone" prefix to interpret
+// its fields in that time zone, e.g. "CRON_TZ=Asia/Tokyo 0 0 6 * * *".
+//
+// Invalid specs are reported as a *SpecError.
+//
+// Besides the built-in descriptors, a parser accepting descriptors accepts
+// the ones registered with RegisterDescriptor and RegisterDescriptorSpec.
 func (p Parser) Parse(spec string) (Schedule, error) {
+	return p.parse(spec, 0)
+}
+
+// parse parses the spec, depth being the number of descriptor aliases it
+// was reached through.
+func (p Parser) parse(spec string, depth int) (schedule Schedule, err error) {
+	pos := -1
+	defer func(spec string) {
+		if err != nil {
+			err = &SpecError{Spec: spec, Field: pos, Err: err}
+		}
+	}(spec)
+
+	var loc *time.Location
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("Missing fields after time zone: %s", spec)
+		}
+		name := spec[strings.IndexByte(spec, '=')+1 : i]
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("Provided bad location %s: %s", name, err)
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+
 	if len(spec) == 0 {
 		return nil, fmt.Errorf("Empty spec string")
 	}
 	if spec[0] == '@' && p.options&Descriptor > 0 {
-		return parseDescriptor(spec)
+		if custom, ok, err := p.customDescriptor(spec, depth); ok {
+			if ss, isSpec := custom.(*SpecSchedule); isSpec && err == nil && loc != nil {
+				// Factories may return shared schedules.
+				located := *ss
+				located.Location = loc
+				custom = &located
+			}
+			return custom, err
+		}
+		schedule, err = parseDescriptor(spec)
+		if ss, ok := schedule.(*SpecSchedule); ok {
+			ss.Location = loc
+		}
+		return schedule, err
 	}
 
 	// Figure out how many fields we need
@@ -106,35 +173,73 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 	// Fill in missing fields
 	fields = expandFields(fields, p.options)
 
-	var err error
-	field := func(field string, r bounds) uint64 {
+	// position counts the fields written before the one at i.
+	position := func(i int) int {
+		n := 0
+		for _, place := range places[:i] {
+			if p.options&place > 0 {
+				n++
+			}
+		}
+		return n
+	}
+	field := func(i int, r bounds) uint64 {
 		if err != nil {
 			return 0
 		}
 		var bits uint64
-		bits, err = getField(field, r)
+		if bits, err = getField(fields[i], r); err != nil {
+			pos = position(i)
+		}
 		return bits
 	}
 
 	var (
-		second     = field(fields[0], seconds)
-		minute     = field(fields[1], minutes)
-		hour       = field(fields[2], hours)
-		dayofmonth = field(fields[3], dom)
-		month      = field(fields[4], months)
-		dayofweek  = field(fields[5], dow)
+		second     = field(0, seconds)
+		minute     = field(1, minutes)
+		hour       = field(2, hours)
+		month      = field(4, months)
+		week       = field(7, weeks)
+		dayofmonth uint64
+		dayofweek  uint64
+		lastDow    uint64
+		lastWeek   bool
+		yearList   []int
 	)
+	if err == nil {
+		if dayofmonth, lastWeek, err = getDom(fields[3]); err != nil {
+			pos = position(3)
+		}
+	}
+	if err == nil {
+		if dayofweek, lastDow, err = getDow(fields[5]); err != nil {
+			pos = position(5)
+		}
+	}
+	if err == nil {
+		if yearList, err = getYears(fields[6]); err != nil {
+			pos = position(6)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
+	if week&starBit > 0 {
+		week = 0
+	}
 
 	return &SpecSchedule{
-		Second: second,
-		Minute: minute,
-		Hour:   hour,
-		Dom:    dayofmonth,
-		Month:  month,
-		Dow:    dayofweek,
+		Second:      second,
+		Minute:      minute,
+		Hour:        hour,
+		Dom:         dayofmonth,
+		Month:       month,
+		Dow:         dayofweek,
+		Location:    loc,
+		Week:        week,
+		Years:       yearList,
+		LastWeekday: lastWeek,
+		LastDow:     lastDow,
 	}, nil
 }
 
@@ -175,14 +280,17 @@ var defaultParser = NewParser(
 	Second | Minute | Hour | Dom | Month | DowOptional | Descriptor,
 )
 
-// Parse returns a new crontab schedule representing the given spec.
+// Parse returns a crontab schedule representing the given spec.
 // It returns a descriptive error if the spec is not valid.
 //
 // It accepts
 //   - Full crontab specs, e.g. "* * * * * ?"
 //   - Descriptors, e.g. "@midnight", "@every 1h30m"
+//
+// Schedules are cached by DefaultSpecCache, and so shared by the callers
+// parsing the same spec; they must not be modified.
 func Parse(spec string) (Schedule, error) {
-	return defaultParser.Parse(spec)
+	return DefaultSpecCache.Parse(spec)
 }
 
 // getField returns an Int with the bits set representing all of the times that
@@ -201,27 +309,108 @@ func getField(field string, r bounds) (uint64, error) {
 	return bits, nil
 }
 
+// getDom returns the bits of the day of month field, and whether it holds
+// the LW token, the last weekday of the month.
+func getDom(field string) (uint64, bool, error) {
+	var (
+		bits uint64
+		last bool
+	)
+	for _, expr := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		if strings.EqualFold(expr, "LW") {
+			last = true
+			continue
+		}
+		bit, err := getRange(expr, dom)
+		if err != nil {
+			return bits, last, err
+		}
+		bits |= bit
+	}
+	return bits, last, nil
+}
+
+// getDow returns the bits of the day of week field, and those of the days
+// given with the L suffix, such as 5L or FRIL, the last Friday of the month.
+func getDow(field string) (uint64, uint64, error) {
+	var bits, last uint64
+	for _, expr := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		if n := len(expr); n > 1 && (expr[n-1] == 'L' || expr[n-1] == 'l') {
+			day, err := parseIntOrName(expr[:n-1], dow.names)
+			if err != nil {
+				return bits, last, err
+			}
+			if day > dow.max {
+				return bits, last, fmt.Errorf("Day of week (%d) above maximum (%d): %s", day, dow.max, expr)
+			}
+			last |= 1 << day
+			continue
+		}
+		bit, err := getRange(expr, dow)
+		if err != nil {
+			return bits, last, err
+		}
+		bits |= bit
+	}
+	return bits, last, nil
+}
+
 // getRange returns the bits indicated by the given expression:
 //   number | number "-" number [ "/" number ]
 // or error parsing range.
 func getRange(expr string, r bounds) (uint64, error) {
+	start, end, step, star, err := parseRange(expr, r)
+	if err != nil {
+		return 0, err
+	}
+	var extra uint64
+	if star {
+		extra = starBit
+	}
+	return getBits(start, end, step) | extra, nil
+}
+
+// getYears returns the years indicated by the given field, in increasing
+// order, or nil for every year.
+func getYears(field string) ([]int, error) {
+	if field == "*" || field == "?" {
+		return nil, nil
+	}
+	set := make(map[int]bool)
+	for _, expr := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		start, end, step, _, err := parseRange(expr, years)
+		if err != nil {
+			return nil, err
+		}
+		for y := start; y <= end; y += step {
+			set[int(y)] = true
+		}
+	}
+	list := make([]int, 0, len(set))
+	for y := range set {
+		list = append(list, y)
+	}
+	sort.Ints(list)
+	return list, nil
+}
+
+// parseRange returns the range indicated by the given expression, and
+// whether it is a star.
+func parseRange(expr string, r bounds) (start, end, step uint, star bool, err error) {
 	var (
-		start, end, step uint
-		rangeAndStep     = strings.Split(expr, "/")
-		lowAndHigh       = strings.Split(rangeAndStep[0], "-")
-		singleDigit      = len(lowAndHigh) == 1
-		err              error
+		rangeAndStep = strings.Split(expr, "/")
+		lowAndHigh   = strings.Split(rangeAndStep[0], "-")
+		singleDigit  = len(lowAndHigh) == 1
 	)
 
-	var extra uint64
 	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
 		start = r.min
 		end = r.max
-		extra = starBit
+		star = true
 	} else {
 		start, err = parseIntOrName(lowAndHigh[0], r.names)
 		if err != nil {
-			return 0, err
+			return 0, 0, 0, false, err
 		}
 		switch len(lowAndHigh) {
 		case 1:
@@ -229,10 +418,10 @@ func getRange(expr string, r bounds) (uint64, error) {
 		case 2:
 			end, err = parseIntOrName(lowAndHigh[1], r.names)
 			if err != nil {
-				return 0, err
+				return 0, 0, 0, false, err
 			}
 		default:
-			return 0, fmt.Errorf("Too many hyphens: %s", expr)
+			return 0, 0, 0, false, fmt.Errorf("Too many hyphens: %s", expr)
 		}
 	}
 
@@ -242,7 +431,7 @@ func getRange(expr string, r bounds) (uint64, error) {
 	case 2:
 		step, err = mustParseInt(rangeAndStep[1])
 		if err != nil {
-			return 0, err
+			return 0, 0, 0, false, err
 		}
 
 		// Special handling: "N/step" means "N-max/step".
@@ -250,23 +439,23 @@ func getRange(expr string, r bounds) (uint64, error) {
 			end = r.max
 		}
 	default:
-		return 0, fmt.Errorf("Too many slashes: %s", expr)
+		return 0, 0, 0, false, fmt.Errorf("Too many slashes: %s", expr)
 	}
 
 	if start < r.min {
-		return 0, fmt.Errorf("Beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
+		return 0, 0, 0, false, fmt.Errorf("Beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
 	}
 	if end > r.max {
-		return 0, fmt.Errorf("End of range (%d) above maximum (%d): %s", end, r.max, expr)
+		return 0, 0, 0, false, fmt.Errorf("End of range (%d) above maximum (%d): %s", end, r.max, expr)
 	}
 	if start > end {
-		return 0, fmt.Errorf("Beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+		return 0, 0, 0, false, fmt.Errorf("Beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
 	}
 	if step == 0 {
-		return 0, fmt.Errorf("Step of range should be a positive number: %s", expr)
+		return 0, 0, 0, false, fmt.Errorf("Step of range should be a positive number: %s", expr)
 	}
 
-	return getBits(start, end, step) | extra, nil
+	return start, end, step, star, nil
 }
 
 // parseIntOrName returns the (possibly-named) integer contained in expr.
@@ -367,6 +556,11 @@ func parseDescriptor(descriptor string) (Schedule, error) {
 		}, nil
 	}
 
+	const after = "@after "
+	if strings.HasPrefix(descriptor, after) {
+		return parseAfter(descriptor[len(after):])
+	}
+
 	const every = "@every "
 	if strings.HasPrefix(descriptor, every) {
 		duration, err := time.ParseDuration(descriptor[len(every):])
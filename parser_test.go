@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -203,7 +204,7 @@ func TestStandardSpecSchedule(t *testing.T) {
 	}{
 		{
 			expr:     "5 * * * *",
-			expected: &SpecSchedule{1 << seconds.min, 1 << 5, all(hours), all(dom), all(months), all(dow)},
+			expected: &SpecSchedule{1 << seconds.min, 1 << 5, all(hours), all(dom), all(months), all(dow), nil, 0, nil, false, 0},
 		},
 		{
 			expr:     "@every 5m",
@@ -259,3 +260,105 @@ func TestParseDateSchedule(t *testing.T) {
 
 	t.Log(s, s.Next(time.Now()))
 }
+
+func TestParseTimeZone(t *testing.T) {
+	schedule, err := Parse("CRON_TZ=Asia/Tokyo 0 0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := schedule.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected %v, got %v", want, next)
+	}
+	if next.Location() != time.UTC {
+		t.Errorf("expected the result in UTC, got %v", next.Location())
+	}
+	if _, err := Parse("TZ=Nowhere/Invalid * * * * * *"); err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
+
+func TestParseYearAndWeek(t *testing.T) {
+	p := NewParser(Second | Minute | Hour | Dom | Month | Dow | Year | Week)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * *", "Tue Jul 10 09:00 2012"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * * 2030", "Tue Jan 1 09:00 2030"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 1 Mar ? 2011,2013-2014", "Fri Mar 1 09:00 2013"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * * 2010-2011", ""},
+		// Every odd ISO week on Monday: July 9th 2012 is in week 28.
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * Mon * 1-53/2", "Mon Jul 16 09:00 2012"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * Mon * 28", "Mon Jul 8 09:00 2013"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 * * Mon 2018 1", "Mon Jan 1 09:00 2018"},
+	}
+	for _, c := range runs {
+		sched, err := p.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+
+	if s, _ := p.Parse("0 0 9 * * *"); s.(*SpecSchedule).Week != 0 || s.(*SpecSchedule).Years != nil {
+		t.Errorf("expected no year or week restriction, got %+v", s)
+	}
+	for spec, field := range map[string]int{
+		"0 0 9 * * * 1969":    6,
+		"0 0 9 * * * 2025 54": 7,
+	} {
+		_, err := p.Parse(spec)
+		var se *SpecError
+		if !errors.As(err, &se) || se.Field != field {
+			t.Errorf("%s: expected an error in field %d, got %v", spec, field, err)
+		}
+	}
+}
+
+func TestParseLastDays(t *testing.T) {
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// Last weekday of the month: September 30th 2012 is a Sunday.
+		{"Mon Jul 9 14:45 2012", "0 0 9 LW * ?", "Tue Jul 31 09:00 2012"},
+		{"Tue Jul 31 10:00 2012", "0 0 9 LW * ?", "Fri Aug 31 09:00 2012"},
+		{"Fri Aug 31 10:00 2012", "0 0 9 lw * ?", "Fri Sep 28 09:00 2012"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 15,LW * ?", "Sun Jul 15 09:00 2012"},
+		// Last Friday of the month.
+		{"Mon Jul 9 14:45 2012", "0 0 9 ? * 5L", "Fri Jul 27 09:00 2012"},
+		{"Fri Jul 27 10:00 2012", "0 0 9 ? * FRIL", "Fri Aug 31 09:00 2012"},
+		{"Mon Jul 9 14:45 2012", "0 0 9 ? * 1L,5", "Fri Jul 13 09:00 2012"},
+		{"Fri Jul 27 10:00 2012", "0 0 9 ? * 1L,5", "Mon Jul 30 09:00 2012"},
+	}
+	for _, c := range runs {
+		sched, err := Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+
+	for spec, field := range map[string]int{
+		"0 0 9 L * ?":  3,
+		"0 0 9 ? * 7L": 5,
+		"0 0 9 ? * XL": 5,
+	} {
+		_, err := Parse(spec)
+		var se *SpecError
+		if !errors.As(err, &se) || se.Field != field {
+			t.Errorf("%s: expected an error in field %d, got %v", spec, field, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cron
+
+import "sync"
+
+// workerPool runs tasks on a fixed set of long-lived goroutines, so
+// dispatching jobs and results does not start a goroutine every time, from
+// a queue of bounded depth. Submitting never blocks the scheduler: a task
+// finding the queue full is rejected, for the caller to skip or run itself.
+// While the pool is stopped, a task gets a goroutine of its own.
+type workerPool struct {
+	size  int
+	depth int
+
+	mu    sync.RWMutex
+	tasks chan func() // nil while stopped
+}
+
+func newWorkerPool(size, depth int) *workerPool {
+	return &workerPool{size: size, depth: depth}
+}
+
+// start starts the workers.
+func (p *workerPool) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tasks != nil {
+		return
+	}
+	p.tasks = make(chan func(), p.depth)
+	for i := 0; i < p.size; i++ {
+		go work(p.tasks)
+	}
+}
+
+// stop lets the workers exit once they are done with the queued tasks.
+func (p *workerPool) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tasks != nil {
+		close(p.tasks)
+		p.tasks = nil
+	}
+}
+
+func work(tasks <-chan func()) {
+	for f := range tasks {
+		f()
+	}
+}
+
+// submit queues f for the workers, and returns false if the queue is full.
+// A nil or stopped pool runs f in a new goroutine.
+func (p *workerPool) submit(f func()) bool {
+	if p != nil {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		if p.tasks != nil {
+			select {
+			case p.tasks <- f:
+				return true
+			default:
+				return false
+			}
+		}
+	}
+	go f()
+	return true
+}
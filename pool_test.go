@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"io/ioutil"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	p := newWorkerPool(2, 1)
+	p.start()
+	var wg sync.WaitGroup
+	var ran int32
+	release := make(chan struct{})
+	task := func() {
+		defer wg.Done()
+		atomic.AddInt32(&ran, 1)
+		<-release
+	}
+	for i := int32(1); i <= 2; i++ {
+		wg.Add(1)
+		p.submit(task)
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(&ran) < i {
+			if time.Now().After(deadline) {
+				t.Fatalf("%d of %d tasks started", atomic.LoadInt32(&ran), i)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	// More tasks than workers: one waits in the queue, the next one is
+	// rejected.
+	wg.Add(1)
+	if !p.submit(task) {
+		t.Error("expected a task to be queued")
+	}
+	if p.submit(task) {
+		t.Error("expected a task to be rejected once the queue is full")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := atomic.LoadInt32(&ran); n != 2 {
+		t.Errorf("expected the queued task to wait for a worker, got %d tasks started", n)
+	}
+	close(release)
+	wg.Wait()
+
+	p.stop()
+	done := make(chan struct{})
+	p.submit(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task submitted to a stopped pool did not run")
+	}
+
+	var nilPool *workerPool
+	done = make(chan struct{})
+	nilPool.submit(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task submitted to a nil pool did not run")
+	}
+}
+
+func TestWithWorkers(t *testing.T) {
+	c := New(WithWorkers(4, 16))
+	var wg sync.WaitGroup
+	wg.Add(3)
+	c.AddResultHandler(func(*JobResult) { wg.Done() })
+	c.AddFunc("@yearly", func() (string, error) { return "", nil })
+	c.Start()
+	defer c.Stop()
+	for i := 0; i < 3; i++ {
+		id := c.Entries()[0].ID
+		if err := c.RunNow(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("results were not handled")
+	}
+}
+
+func TestWithWorkersBound(t *testing.T) {
+	c := New(WithWorkers(2, 1))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	var running, max int32
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	id, _ := c.AddFunc("@yearly", func() (string, error) {
+		defer wg.Done()
+		started <- struct{}{}
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+		return "", nil
+	})
+	c.Start()
+	defer c.Stop()
+
+	// Two runs take the workers and one waits in the queue: the fourth is
+	// skipped.
+	wg.Add(3)
+	for i := 0; i < 4; i++ {
+		c.RunNow(id)
+		if i < 2 {
+			<-started
+		}
+	}
+	close(release)
+	wg.Wait()
+	if max != 2 {
+		t.Errorf("expected 2 runs at a time, got %d", max)
+	}
+	for {
+		select {
+		case e := <-events:
+			if e.Type == EventJobSkipped && e.Msg == "Worker queue full" {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a run to be skipped")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WithPriority sets the priority of the entry, 0 by default. When runs wait
+// for a slot under WithMaxConcurrent, the runs with a priority above 0 are
+// given one first, highest priority first, ahead of the namespaces taking
+// turns. A run of a higher priority finding every slot taken also preempts
+// the lowest priority preemptible run, see WithPreemptible.
+func WithPriority(p int) EntryOption {
+	return func(e *Entry) {
+		e.Priority = p
+	}
+}
+
+// WithPreemptible lets the runs of the entry be cancelled to free their slot
+// for a run of a higher priority. The cancellation goes through the context
+// of the run, which the job must be a ContextJob to notice; the slot is
+// freed once it returns. A preempted run that fails does so with an error
+// matching ErrPreempted.
+func WithPreemptible() EntryOption {
+	return func(e *Entry) {
+		e.Preemptible = true
+	}
+}
+
+// queuedRun is a run waiting for, or holding, a slot of a fairQueue.
+type queuedRun struct {
+	id        string
+	namespace string
+	priority  int
+	ready     chan struct{}
+	// cancel cancels the context of a preemptible run, and is nil for the
+	// other runs.
+	cancel    context.CancelFunc
+	preempted int32
+}
+
+// preempt cancels the run.
+func (r *queuedRun) preempt() {
+	atomic.StoreInt32(&r.preempted, 1)
+	r.cancel()
+}
+
+func (r *queuedRun) isPreempted() bool {
+	return r != nil && atomic.LoadInt32(&r.preempted) == 1
+}
+
+// runContext returns the context to run a job with the slots s in, which
+// is cancelled when the run is preempted, and the run to queue for a slot.
+func (c *Cron) runContext(id string, s slots) (context.Context, *queuedRun) {
+	if c.sem == nil {
+		return s.context(), nil
+	}
+	r := &queuedRun{id: id, namespace: s.namespace, priority: s.priority}
+	if !s.preemptible {
+		return s.context(), r
+	}
+	ctx, cancel := context.WithCancel(s.context())
+	r.cancel = cancel
+	return ctx, r
+}
+
+// preempted records the preemption of a run of the job with the given id.
+func (c *Cron) preempted(id string) {
+	c.entryLogf(id, "cron: preempting job %s", id)
+	c.emit(Event{Type: EventJobPreempted, EntryID: id, Time: c.now()})
+}
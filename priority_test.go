@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := newFairQueue(1, nil)
+	q.acquire("")
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	waiters := 0
+	for _, run := range []struct {
+		id       string
+		priority int
+	}{{"low1", 0}, {"high", 2}, {"low2", 0}, {"mid", 1}, {"high2", 2}} {
+		run := run
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := &queuedRun{id: run.id, priority: run.priority}
+			q.wait(r)
+			mu.Lock()
+			order = append(order, run.id)
+			mu.Unlock()
+			q.leave(r)
+		}()
+		waiters++
+		for deadline := time.Now().Add(time.Second); q.queued()+len(q.urgentRuns()) < waiters; {
+			if time.Now().After(deadline) {
+				t.Fatal("run not queued")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	q.release()
+	wg.Wait()
+	if got, want := strings.Join(order, " "), "high high2 mid low1 low2"; got != want {
+		t.Errorf("expected the runs in order %s, got %s", want, got)
+	}
+	if q.free != 1 {
+		t.Errorf("expected the slot to be free, got %d", q.free)
+	}
+}
+
+func (q *fairQueue) urgentRuns() []*queuedRun {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]*queuedRun(nil), q.urgent...)
+}
+
+// preemptJob runs until its context is done or it is released.
+type preemptJob struct {
+	id      string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (j *preemptJob) ID() string           { return j.id }
+func (j *preemptJob) Run() (string, error) { return j.RunContext(context.Background()) }
+
+func (j *preemptJob) RunContext(ctx context.Context) (string, error) {
+	j.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-j.release:
+		return "done", nil
+	}
+}
+
+func TestPreemption(t *testing.T) {
+	c := New(WithMaxConcurrent(1))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	results := make(chan *JobResult, 10)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+
+	low := &preemptJob{"low", make(chan struct{}, 1), make(chan struct{})}
+	high := &preemptJob{"high", make(chan struct{}, 1), make(chan struct{})}
+	c.AddJob("@yearly", low, WithPreemptible())
+	c.AddJob("@yearly", high, WithPriority(1))
+	if e, _ := c.Entry("low"); !e.Preemptible || e.Priority != 0 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	c.RunNow("low")
+	<-low.started
+	c.RunNow("high")
+	<-high.started
+
+	r := <-results
+	if r.JobId != "low" || !errors.Is(r.Error, ErrPreempted) {
+		t.Errorf("expected low preempted, got %+v", r)
+	}
+	close(high.release)
+	if r := <-results; r.JobId != "high" || r.Error != nil {
+		t.Errorf("expected high to succeed, got %+v", r)
+	}
+	for e := range events {
+		if e.Type == EventJobPreempted {
+			if e.EntryID != "low" {
+				t.Errorf("expected low preempted, got %+v", e)
+			}
+			break
+		}
+	}
+
+	// Runs that are not preemptible keep their slot.
+	c.RemoveJob("low")
+	low.release = make(chan struct{})
+	c.AddJob("@yearly", low)
+	high.release = make(chan struct{})
+	c.RunNow("low")
+	<-low.started
+	c.RunNow("high")
+	select {
+	case <-high.started:
+		t.Fatal("expected high to wait for the slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(low.release)
+	<-high.started
+	close(high.release)
+	for i := 0; i < 2; i++ {
+		if r := <-results; r.Error != nil {
+			t.Errorf("unexpected error %v", r.Error)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cron
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeNATS accepts one connection and sends the published messages on the
+// returned channel. Publishing to the "denied" subject fails.
+func fakeNATS(t *testing.T) (addr string, published <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			var subject string
+			var n int
+			switch {
+			case strings.HasPrefix(line, "PUB "):
+				fmt.Sscanf(line, "PUB %s %d", &subject, &n)
+				payload := make([]byte, n+2)
+				io.ReadFull(r, payload)
+				if subject == "denied" {
+					fmt.Fprintf(conn, "-ERR 'Permissions Violation'\r\n")
+					continue
+				}
+				ch <- subject + " " + string(payload[:n])
+			case strings.HasPrefix(line, "PING"):
+				fmt.Fprintf(conn, "PONG\r\n")
+			}
+		}
+	}()
+	return l.Addr().String(), ch
+}
+
+func TestPublishJob(t *testing.T) {
+	addr, published := fakeNATS(t)
+	job, err := newJob(PublishJobType, "tick", map[string]string{
+		"url":     "nats://" + addr,
+		"topic":   "jobs.tick",
+		"message": `{"job":"{{.ID}}"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "Published 14 bytes to jobs.tick" {
+		t.Errorf("msg = %q", msg)
+	}
+	if got := <-published; got != `jobs.tick {"job":"tick"}` {
+		t.Errorf("published %q", got)
+	}
+
+	addr, _ = fakeNATS(t)
+	if _, err := NewPublishJob("denied", "nats://"+addr, "denied", "x").Run(); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("err = %v, want permissions violation", err)
+	}
+}
+
+type testPublisher struct{ topics *[]string }
+
+func (p testPublisher) Publish(ctx context.Context, topic string, message []byte) error {
+	*p.topics = append(*p.topics, topic)
+	return nil
+}
+
+func (p testPublisher) Close() error { return nil }
+
+func TestRegisterPublisher(t *testing.T) {
+	var topics []string
+	RegisterPublisher("test", func(ctx context.Context, u *url.URL) (Publisher, error) {
+		return testPublisher{&topics}, nil
+	})
+	if _, err := NewPublishJob("p", "test://broker", "orders", "").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0] != "orders" {
+		t.Errorf("topics = %v", topics)
+	}
+	if _, err := NewPublishJob("p", "kafka://broker", "orders", "").Run(); err == nil {
+		t.Error("expected an unknown scheme to fail")
+	}
+}
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// QuarantineRecord tells why an entry was quarantined: disabled for failing
+// too often, or for panicking.
+type QuarantineRecord struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+	Error  string    `json:"error,omitempty"`
+	// Stack is the stack trace of the panic, if the job panicked.
+	Stack string `json:"stack,omitempty"`
+}
+
+// WithQuarantine quarantines the jobs that panic, as if they failed too
+// often: they are disabled until Enable is called, and listed with their
+// stack trace by Quarantined.
+func WithQuarantine() Option {
+	return func(c *Cron) {
+		c.quarantine = true
+	}
+}
+
+// Quarantined returns the records of the entries disabled for failing too
+// often or for panicking, oldest first. Enable releases them.
+func (c *Cron) Quarantined() []QuarantineRecord {
+	var records []QuarantineRecord
+	c.do(func() {
+		for _, e := range c.entries {
+			if e.health == nil {
+				continue
+			}
+			e.health.mu.Lock()
+			if r := e.health.quarantined; r != nil {
+				records = append(records, *r)
+			}
+			e.health.mu.Unlock()
+		}
+	})
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records
+}
+
+// quarantinePanic disables the job with the given id, which panicked.
+func (c *Cron) quarantinePanic(id string, h *entryHealth, err error, stack []byte) {
+	if !c.quarantine || h == nil {
+		return
+	}
+	now := c.now()
+	h.mu.Lock()
+	h.disabled = true
+	h.quarantined = &QuarantineRecord{
+		ID:     id,
+		Time:   now,
+		Reason: "Quarantined after a panic",
+		Error:  err.Error(),
+		Stack:  string(stack),
+	}
+	h.mu.Unlock()
+	c.disabled(id)
+	c.entryErrorf(id, "cron: job %s quarantined after a panic", id)
+	c.emit(Event{Type: EventEntryDisabled, EntryID: id, Time: now, Msg: "Quarantined after a panic", Error: err.Error()})
+}
+
+// quarantineFailures records why the job with the given id was disabled.
+// h.mu must be held.
+func (h *entryHealth) quarantineFailures(id string, now time.Time, err error) string {
+	reason := fmt.Sprintf("Disabled after %d consecutive failures", h.streak)
+	h.disabled = true
+	h.quarantined = &QuarantineRecord{
+		ID:     id,
+		Time:   now,
+		Reason: reason,
+		Error:  err.Error(),
+	}
+	return reason
+}
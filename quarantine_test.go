@@ -0,0 +1,57 @@
+package cron
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuarantine(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC), WithDisableAfter(2), WithQuarantine())
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	var panics, failures int
+	panicking, _ := c.AddFunc("0 * * * * *", func() (string, error) {
+		panics++
+		panic("boom")
+	})
+	failing, _ := c.AddFunc("30 * * * * *", func() (string, error) {
+		failures++
+		return "", errors.New("broken")
+	})
+	c.AddFunc("0 * * * * *", func() (string, error) { return "", nil })
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(5 * time.Minute)
+	if panics != 1 || failures != 2 {
+		t.Errorf("expected 1 panic and 2 failures, got %d and %d", panics, failures)
+	}
+	records := c.Quarantined()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 quarantined entries, got %+v", records)
+	}
+	if r := records[0]; r.ID != panicking || r.Error != "panic: boom" || !strings.Contains(r.Stack, "goroutine") {
+		t.Errorf("unexpected record of the panicking job %+v", r)
+	}
+	if r := records[1]; r.ID != failing || r.Error != "broken" || r.Stack != "" {
+		t.Errorf("unexpected record of the failing job %+v", r)
+	}
+	if e, _ := c.Entry(panicking); e.Status != EntryDisabled {
+		t.Errorf("expected the panicking entry to be disabled, got %s", e.Status)
+	}
+
+	if err := c.Enable(panicking); err != nil {
+		t.Fatal(err)
+	}
+	if records := c.Quarantined(); len(records) != 1 || records[0].ID != failing {
+		t.Errorf("expected only the failing entry to stay quarantined, got %+v", records)
+	}
+	clock.Advance(time.Minute)
+	if panics != 2 {
+		t.Errorf("expected the released entry to run again, got %d panics", panics)
+	}
+}
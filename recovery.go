@@ -0,0 +1,35 @@
+package cron
+
+import "log"
+
+// Panic describes a panic of a job.
+type Panic struct {
+	JobID string
+	// Value is the value the job panicked with, formatted with its
+	// secrets masked.
+	Value string
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// WithPanicHandler hands the panics of the job of the entry to f instead of
+// logging them, e.g. to forward them to the alerting of a critical job. The
+// run fails with the error f returns, or with an error describing the panic
+// if it is nil. The panic is otherwise handled as usual, e.g. quarantined
+// under WithQuarantine.
+func WithPanicHandler(f func(p Panic) error) EntryOption {
+	return func(e *Entry) {
+		e.PanicHandler = f
+	}
+}
+
+// WithEntryErrorLog logs the messages reporting failures of the entry, such
+// as the panics of its job, its quarantine, its opened circuit breaker or the
+// failures to notify its escalations, to l, instead of the logger of
+// WithEntryLogger or the error log of the Cron. The prefix of WithLogPrefix
+// applies.
+func WithEntryErrorLog(l *log.Logger) EntryOption {
+	return func(e *Entry) {
+		e.ErrorLog = l
+	}
+}
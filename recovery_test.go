@@ -0,0 +1,53 @@
+package cron
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestPanicHandler(t *testing.T) {
+	var cronLog, alerts syncBuffer
+	c := New(WithLogger(log.New(&cronLog, "", 0)))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	panics := make(chan Panic, 1)
+	c.AddJob("@yearly", &loggingJob{"handled"}, WithPanicHandler(func(p Panic) error {
+		panics <- p
+		return errors.New("critical job panicked")
+	}))
+	c.AddJob("@yearly", &loggingJob{"critical"}, WithEntryErrorLog(log.New(&alerts, "", 0)), WithLogPrefix("job=critical "))
+	c.Start()
+	defer c.Stop()
+
+	finished := func(id string) Event {
+		c.RunNow(id)
+		for e := range events {
+			if e.Type == EventJobFinished {
+				return e
+			}
+		}
+		return Event{}
+	}
+	if e := finished("handled"); e.Error != "critical job panicked" {
+		t.Errorf("expected the error of the panic handler, got %+v", e)
+	}
+	if p := <-panics; p.JobID != "handled" || p.Value != "boom" || !strings.Contains(string(p.Stack), "RunContext") {
+		t.Errorf("unexpected panic %+v", p)
+	}
+
+	if e := finished("critical"); e.Error != "panic: boom" {
+		t.Errorf("expected the run to fail with the panic, got %+v", e)
+	}
+	if !strings.Contains(alerts.String(), "job=critical cron: panic running job: boom") {
+		t.Errorf("expected the panic in the error log of the entry, got:\n%s", alerts.String())
+	}
+	// Only the failures go to the error log of the entry.
+	if strings.Contains(alerts.String(), "running critical") || !strings.Contains(cronLog.String(), "job=critical running critical") {
+		t.Errorf("expected the job log in the cron log, got:\n%s\nand:\n%s", cronLog.String(), alerts.String())
+	}
+	if strings.Contains(cronLog.String(), "panic") {
+		t.Errorf("expected no panic in the cron log, got:\n%s", cronLog.String())
+	}
+}
@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+)
+
+// JobFactory builds a Job with the given id from its serialized parameters.
+type JobFactory func(id string, params map[string]string) (Job, error)
+
+// DescribedJob is a Job that can describe how to rebuild itself. Jobs that
+// implement it can be exported with Snapshot and rebuilt with Restore, given
+// that a factory is registered for their type.
+type DescribedJob interface {
+	Job
+	// JobType returns the name the job's factory is registered under.
+	JobType() string
+	// Params returns the parameters the factory needs to rebuild the job.
+	Params() map[string]string
+}
+
+var (
+	jobTypesMu sync.RWMutex
+	jobTypes   = make(map[string]JobFactory)
+)
+
+// RegisterJobType makes a job factory available under the given type name.
+// Registering a name twice replaces the previous factory.
+func RegisterJobType(name string, factory JobFactory) {
+	if factory == nil {
+		panic("cron: RegisterJobType factory is nil")
+	}
+	jobTypesMu.Lock()
+	defer jobTypesMu.Unlock()
+	jobTypes[name] = factory
+}
+
+// newJob builds a job of the given registered type.
+func newJob(typ, id string, params map[string]string) (Job, error) {
+	jobTypesMu.RLock()
+	factory, ok := jobTypes[typ]
+	jobTypesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown job type %q", typ)
+	}
+	return factory(id, params)
+}
+
+// describe returns the type and parameters of the job, if it has any.
+func describe(j Job) (typ string, params map[string]string) {
+	if dj, ok := j.(DescribedJob); ok {
+		return dj.JobType(), dj.Params()
+	}
+	return "", nil
+}
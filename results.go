@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// resultSubscribers fans job results out to subscribers.
+type resultSubscribers struct {
+	mu   sync.Mutex
+	subs map[chan *JobResult]struct{}
+}
+
+// SubscribeResults returns a channel receiving the result of every job run
+// from now on, until cancel is called. Results are dropped for subscribers
+// that fall more than buffer results behind, so a slow subscriber never
+// delays the scheduler.
+func (c *Cron) SubscribeResults(buffer int) (results <-chan *JobResult, cancel func()) {
+	ch := make(chan *JobResult, buffer)
+	s := &c.subscribers
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[chan *JobResult]struct{})
+	}
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// any reports whether there are subscribers.
+func (s *resultSubscribers) any() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs) > 0
+}
+
+// publish hands the result to every subscriber.
+func (s *resultSubscribers) publish(r *JobResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- r:
+		default:
+		}
+	}
+}
+
+// handle passes the result to the result handler h, recovering from its
+// panics so that a faulty handler can not crash the process. A panic is
+// logged with its stack and reported by an EventHandlerPanicked event.
+func (c *Cron) handle(h func(*JobResult), r *JobResult) {
+	defer func() {
+		if v := recover(); v != nil {
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			c.entryErrorf(r.JobId, "cron: panic in result handler for job %s: %v\n%s", r.JobId, v, buf)
+			c.emit(Event{Type: EventHandlerPanicked, EntryID: r.JobId, Error: fmt.Sprint(v)})
+		}
+	}()
+	h(r)
+}
@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeResults(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	cron := New()
+	cron.AddResultHandler(func(*JobResult) {})
+	cron.Schedule(Every(time.Hour), testChanJob{"job", ran})
+
+	results, cancel := cron.SubscribeResults(1)
+	if err := cron.RunNow("job"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-results:
+		if r.JobId != "job" || r.Msg != "job" {
+			t.Errorf("unexpected result %+v", r)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected a result")
+	}
+
+	cancel()
+	if _, ok := <-results; ok {
+		t.Error("expected the channel to be closed after cancel")
+	}
+	cancel()
+}
+
+func TestResultHandlerPanic(t *testing.T) {
+	c := New()
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	handled := make(chan string, 2)
+	c.AddResultHandler(func(r *JobResult) {
+		if r.JobId == "bad" {
+			panic("handler failed")
+		}
+		handled <- r.JobId
+	})
+	c.AddJob("@yearly", &chainJob{id: "bad"})
+	c.AddJob("@yearly", &chainJob{id: "good"})
+	c.Start()
+	defer c.Stop()
+
+	c.RunNow("bad")
+	timeout := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case e := <-events:
+			if e.Type == EventHandlerPanicked {
+				if e.EntryID != "bad" || e.Error != "handler failed" {
+					t.Errorf("unexpected event %+v", e)
+				}
+				done = true
+			}
+		case <-timeout:
+			t.Fatal("expected a handler_panicked event")
+		}
+	}
+	c.RunNow("good")
+	select {
+	case id := <-handled:
+		if id != "good" {
+			t.Errorf("expected the result of good, got %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the handler to keep receiving results")
+	}
+}
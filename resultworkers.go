@@ -0,0 +1,307 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// WithResultWorkers passes the results to the result handler on at most n
+// goroutines, instead of a goroutine per result, so that a slow handler does
+// not pile up goroutines while many jobs finish, e.g. during a failure
+// storm. The results of an entry are always handled one at a time, in the
+// order the runs finished; the results waiting for a busy handler are
+// queued. The results are not passed to the workers of WithWorkers then.
+func WithResultWorkers(n int) Option {
+	return func(c *Cron) {
+		if n > 0 {
+			c.results = newResultDispatcher(n)
+		}
+	}
+}
+
+// OverflowPolicy is what a bounded result queue does with a result that
+// finds it full, see WithResultQueue.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the run wait until the queue has room, which
+	// slows the scheduling of the entries down to the pace of the handler.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest discards the oldest queued result to make room,
+	// counting it in ResultQueueStats.Dropped.
+	OverflowDropOldest
+
+	// OverflowSpill writes the result to a file of the directory set by
+	// WithResultSpillDir, or of a temporary directory, until the queue has
+	// room again. The results are read back from disk as decoded by
+	// JobResult.UnmarshalJSON. The file, and the temporary directory, are
+	// closed and removed once every spilled result is read back, so they
+	// do not outlive the results, even after Stop.
+	OverflowSpill
+)
+
+// WithResultQueue bounds the queues of results waiting for the result
+// handler to size results per worker of WithResultWorkers, or in total
+// without it, and sets what happens to the results that find a queue full.
+// Without it, the queues grow as long as the handler falls behind.
+func WithResultQueue(size int, policy OverflowPolicy) Option {
+	return func(c *Cron) {
+		c.resultLimit.size = size
+		c.resultLimit.policy = policy
+	}
+}
+
+// WithResultSpillDir sets the directory the results overflowing the result
+// queue are written to under OverflowSpill.
+func WithResultSpillDir(dir string) Option {
+	return func(c *Cron) {
+		c.resultLimit.dir = dir
+	}
+}
+
+// ResultQueueStats describes the results waiting for the result handler.
+type ResultQueueStats struct {
+	// Queued is the number of results queued in memory.
+	Queued int `json:"queued"`
+	// Spilled is the number of results written to disk by OverflowSpill.
+	Spilled int `json:"spilled"`
+	// Dropped is the number of results discarded by OverflowDropOldest, or
+	// that could not be spilled or read back.
+	Dropped uint64 `json:"dropped"`
+}
+
+// ResultQueue returns the state of the queues of WithResultWorkers and
+// WithResultQueue, and zero stats without them.
+func (c *Cron) ResultQueue() ResultQueueStats {
+	if c.results == nil {
+		return ResultQueueStats{}
+	}
+	return c.results.stats()
+}
+
+// resultLimit bounds the lanes of a resultDispatcher.
+type resultLimit struct {
+	size   int
+	policy OverflowPolicy
+	dir    string
+}
+
+// resultDispatcher hands the results of each entry in order to handle on
+// one of a fixed number of lanes, each drained by at most one goroutine.
+type resultDispatcher struct {
+	lanes   []*resultLane
+	limit   resultLimit
+	handle  func(*JobResult)
+	logf    func(format string, args ...interface{})
+	dropped uint64
+}
+
+// resultLane is a queue of results drained by a goroutine while it is not
+// empty. Under OverflowSpill, the results that do not fit are appended to
+// the spill file, and read back in order once the queue is empty.
+type resultLane struct {
+	d        *resultDispatcher
+	index    int
+	mu       sync.Mutex
+	room     *sync.Cond
+	results  []*JobResult
+	busy     bool
+	spill    *os.File
+	spillTmp string // the temporary directory holding spill, if any
+	spilled  int
+	spillPos int64
+	spillEnd int64
+}
+
+func newResultDispatcher(n int) *resultDispatcher {
+	d := &resultDispatcher{lanes: make([]*resultLane, n)}
+	for i := range d.lanes {
+		l := &resultLane{d: d, index: i}
+		l.room = sync.NewCond(&l.mu)
+		d.lanes[i] = l
+	}
+	return d
+}
+
+// setupResults sets up the dispatcher of the results once the options are
+// applied.
+func (c *Cron) setupResults() {
+	if c.results == nil {
+		if c.resultLimit.size <= 0 {
+			return
+		}
+		c.results = newResultDispatcher(1)
+	}
+	c.results.limit = c.resultLimit
+	c.results.logf = c.logf
+	c.results.handle = func(r *JobResult) {
+		c.handle(c.chaos.handler(c.handler()), r)
+	}
+}
+
+// submit queues the result on the lane of its job.
+func (d *resultDispatcher) submit(r *JobResult) {
+	h := fnv.New32a()
+	h.Write([]byte(r.JobId))
+	l := d.lanes[h.Sum32()%uint32(len(d.lanes))]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size := d.limit.size; size > 0 {
+		switch d.limit.policy {
+		case OverflowBlock:
+			for len(l.results) >= size {
+				l.room.Wait()
+			}
+		case OverflowDropOldest:
+			if len(l.results) >= size {
+				l.results[0] = nil
+				l.results = l.results[1:]
+				atomic.AddUint64(&d.dropped, 1)
+			}
+		case OverflowSpill:
+			// Once results are spilled, the later ones follow them to
+			// keep the order.
+			if l.spilled > 0 || len(l.results) >= size {
+				if err := l.write(r); err != nil {
+					atomic.AddUint64(&d.dropped, 1)
+					d.logf("cron: failed to spill the result of job %s: %s", r.JobId, err)
+				}
+				l.start()
+				return
+			}
+		}
+	}
+	l.results = append(l.results, r)
+	l.start()
+}
+
+// start starts draining the lane if it is not already.
+func (l *resultLane) start() {
+	if !l.busy {
+		l.busy = true
+		go l.drain()
+	}
+}
+
+// drain handles the results of the lane until it is empty.
+func (l *resultLane) drain() {
+	for {
+		l.mu.Lock()
+		if len(l.results) == 0 && l.spilled > 0 {
+			l.read()
+		}
+		if len(l.results) == 0 {
+			l.busy = false
+			l.mu.Unlock()
+			return
+		}
+		r := l.results[0]
+		l.results[0] = nil
+		l.results = l.results[1:]
+		l.room.Signal()
+		l.mu.Unlock()
+		l.d.handle(r)
+	}
+}
+
+// write appends the result to the spill file of the lane.
+func (l *resultLane) write(r *JobResult) error {
+	if l.spill == nil {
+		dir := l.d.limit.dir
+		if dir == "" {
+			var err error
+			if dir, err = ioutil.TempDir("", "cron-results"); err != nil {
+				return err
+			}
+			l.spillTmp = dir
+		}
+		f, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf("results-%d.jsonl", l.index)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			l.removeSpill()
+			return err
+		}
+		l.spill = f
+	}
+	data, err := json.Marshal(r)
+	if err == nil {
+		var n int
+		n, err = l.spill.WriteAt(append(data, '\n'), l.spillEnd)
+		l.spillEnd += int64(n)
+	}
+	if err != nil {
+		if l.spilled == 0 {
+			l.removeSpill()
+		}
+		return err
+	}
+	l.spilled++
+	return nil
+}
+
+// removeSpill closes and removes the spill file of the lane, and the
+// temporary directory holding it.
+func (l *resultLane) removeSpill() {
+	if l.spill != nil {
+		l.spill.Close()
+		os.Remove(l.spill.Name())
+	}
+	if l.spillTmp != "" {
+		os.RemoveAll(l.spillTmp)
+	}
+	l.spill, l.spillTmp = nil, ""
+	l.spillPos, l.spillEnd = 0, 0
+}
+
+// read moves up to a queue of spilled results back to memory. The spill
+// file is removed once every result is read back.
+func (l *resultLane) read() {
+	dec := json.NewDecoder(io.NewSectionReader(l.spill, l.spillPos, l.spillEnd-l.spillPos))
+	for l.spilled > 0 && len(l.results) < l.d.limit.size {
+		r := &JobResult{}
+		if err := dec.Decode(r); err != nil {
+			l.d.logf("cron: failed to read back %d spilled results: %s", l.spilled, err)
+			atomic.AddUint64(&l.d.dropped, uint64(l.spilled))
+			l.spilled = 0
+			break
+		}
+		l.results = append(l.results, r)
+		l.spilled--
+	}
+	l.spillPos += dec.InputOffset()
+	if l.spilled == 0 {
+		l.removeSpill()
+	}
+}
+
+// stats sums the state of the lanes.
+func (d *resultDispatcher) stats() ResultQueueStats {
+	s := ResultQueueStats{Dropped: atomic.LoadUint64(&d.dropped)}
+	for _, l := range d.lanes {
+		l.mu.Lock()
+		s.Queued += len(l.results)
+		s.Spilled += l.spilled
+		l.mu.Unlock()
+	}
+	return s
+}
+
+// deliver passes the result to the result handler h in the background, or
+// right away if the worker queue is full. With a resultDispatcher, the results are passed to the result handler set
+// when they are handled.
+func (c *Cron) deliver(h func(*JobResult), r *JobResult) {
+	if c.results != nil {
+		c.results.submit(r)
+		return
+	}
+	if !c.pool.submit(func() { c.handle(h, r) }) {
+		c.handle(h, r)
+	}
+}
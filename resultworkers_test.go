@@ -0,0 +1,226 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResultDispatcher(t *testing.T) {
+	d := newResultDispatcher(2)
+	var (
+		mu      sync.Mutex
+		order   = make(map[string][]int)
+		running int
+		peak    int
+		wg      sync.WaitGroup
+	)
+	d.handle = func(r *JobResult) {
+		defer wg.Done()
+		mu.Lock()
+		if running++; running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		i, _ := strconv.Atoi(r.Msg)
+		order[r.JobId] = append(order[r.JobId], i)
+		mu.Unlock()
+	}
+	for i := 0; i < 20; i++ {
+		for _, id := range []string{"a", "b", "c", "d"} {
+			wg.Add(1)
+			d.submit(&JobResult{JobId: id, Msg: strconv.Itoa(i)})
+		}
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("expected at most 2 handlers at a time, got %d", peak)
+	}
+	for id, runs := range order {
+		for i, n := range runs {
+			if n != i {
+				t.Fatalf("expected the results of %s in order, got %v", id, runs)
+			}
+		}
+	}
+}
+
+// blockedDispatcher returns a dispatcher of one lane bounded to size results
+// whose handler records the results once release is closed.
+func blockedDispatcher(size int, policy OverflowPolicy, dir string) (d *resultDispatcher, release chan struct{}, handled chan string) {
+	d = newResultDispatcher(1)
+	d.limit = resultLimit{size, policy, dir}
+	d.logf = func(string, ...interface{}) {}
+	release = make(chan struct{})
+	handled = make(chan string, 100)
+	d.handle = func(r *JobResult) {
+		<-release
+		handled <- r.Msg
+	}
+	return d, release, handled
+}
+
+func TestResultQueueDropOldest(t *testing.T) {
+	d, release, handled := blockedDispatcher(2, OverflowDropOldest, "")
+	// The first result is taken by the handler, so 1 and 2 are dropped.
+	for i := 0; i < 5; i++ {
+		d.submit(&JobResult{JobId: "a", Msg: strconv.Itoa(i)})
+		for i == 0 && d.stats().Queued != 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if s := d.stats(); s.Queued != 2 || s.Dropped != 2 {
+		t.Errorf("expected 2 queued and 2 dropped results, got %+v", s)
+	}
+	close(release)
+	for _, want := range []string{"0", "3", "4"} {
+		if got := <-handled; got != want {
+			t.Errorf("expected result %s, got %s", want, got)
+		}
+	}
+}
+
+func TestResultQueueBlock(t *testing.T) {
+	d, release, handled := blockedDispatcher(1, OverflowBlock, "")
+	d.submit(&JobResult{JobId: "a", Msg: "0"})
+	for d.stats().Queued != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	d.submit(&JobResult{JobId: "a", Msg: "1"})
+	done := make(chan struct{})
+	go func() {
+		d.submit(&JobResult{JobId: "a", Msg: "2"})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected the submission to block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-done
+	for _, want := range []string{"0", "1", "2"} {
+		if got := <-handled; got != want {
+			t.Errorf("expected result %s, got %s", want, got)
+		}
+	}
+}
+
+func TestResultQueueSpill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	d, release, handled := blockedDispatcher(2, OverflowSpill, dir)
+	d.submit(&JobResult{JobId: "a", Msg: "0"})
+	for d.stats().Queued != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i < 10; i++ {
+		r := &JobResult{JobId: "a", Msg: strconv.Itoa(i)}
+		if i == 7 {
+			r.Error = errors.New("failed")
+		}
+		d.submit(r)
+	}
+	if s := d.stats(); s.Queued != 2 || s.Spilled != 7 || s.Dropped != 0 {
+		t.Errorf("expected 2 queued and 7 spilled results, got %+v", s)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "results-0.jsonl")); err != nil || fi.Size() == 0 {
+		t.Errorf("expected a spill file, got %v %v", fi, err)
+	}
+	close(release)
+	for i := 0; i < 10; i++ {
+		if got := <-handled; got != strconv.Itoa(i) {
+			t.Fatalf("expected result %d, got %s", i, got)
+		}
+	}
+	if s := d.stats(); s.Spilled != 0 {
+		t.Errorf("expected no spilled results, got %+v", s)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "results-0.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("expected the spill file to be removed, got %v", err)
+	}
+}
+
+func TestResultQueueSpillTempDir(t *testing.T) {
+	d, release, handled := blockedDispatcher(1, OverflowSpill, "")
+	for i := 0; i < 3; i++ {
+		d.submit(&JobResult{JobId: "a", Msg: strconv.Itoa(i)})
+		for i == 0 && d.stats().Queued != 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	l := d.lanes[0]
+	l.mu.Lock()
+	tmp := l.spillTmp
+	l.mu.Unlock()
+	if _, err := os.Stat(tmp); tmp == "" || err != nil {
+		t.Fatalf("expected a temporary spill directory, got %q %v", tmp, err)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		<-handled
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary directory to be removed, got %v", err)
+	}
+}
+
+func TestResultQueueStats(t *testing.T) {
+	c := New(WithResultQueue(1, OverflowDropOldest))
+	if c.results == nil {
+		t.Fatal("expected a result dispatcher")
+	}
+	if s := New().ResultQueue(); s != (ResultQueueStats{}) {
+		t.Errorf("expected zero stats, got %+v", s)
+	}
+	release := make(chan struct{})
+	c.AddResultHandler(func(r *JobResult) { <-release })
+	c.deliver(nil, &JobResult{JobId: "a"})
+	for c.ResultQueue().Queued != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.deliver(nil, &JobResult{JobId: "a"})
+	c.deliver(nil, &JobResult{JobId: "a"})
+	if s := c.ResultQueue(); s.Queued != 1 || s.Dropped != 1 {
+		t.Errorf("expected 1 queued and 1 dropped result, got %+v", s)
+	}
+	close(release)
+}
+
+func TestWithResultWorkers(t *testing.T) {
+	c := New(WithResultWorkers(1))
+	results := make(chan string, 10)
+	c.AddResultHandler(func(r *JobResult) { results <- r.JobId })
+	for i := 0; i < 3; i++ {
+		c.AddJob("@yearly", &chainJob{id: fmt.Sprint("job", i)})
+	}
+	c.Start()
+	defer c.Stop()
+	for i := 0; i < 3; i++ {
+		c.RunNow(fmt.Sprint("job", i))
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case id := <-results:
+			seen[id] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected the results to be handled")
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected 3 results, got %v", seen)
+	}
+}
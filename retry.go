@@ -0,0 +1,122 @@
+package cron
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// WithRetries runs the job of the entry again up to n times when it fails,
+// waiting delay before each new attempt. Every attempt is a run of its own,
+// recorded in the history and passed to the result handler, whose JobResult
+// tells the attempt apart from the final one, see JobResult.Final. The run
+// loop schedules the attempts waiting for the delay, which are dropped when
+// the Cron stops. The runs preempted or failing while the Cron is stopping
+// are not retried.
+func WithRetries(n int, delay time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.Retries = n
+		e.RetryDelay = delay
+	}
+}
+
+// Final reports whether the result is the one of the last attempt of the
+// run: it succeeded, or no attempt is left.
+func (r *JobResult) Final() bool {
+	return r.Error == nil || r.Attempt >= r.MaxAttempts
+}
+
+// pendingRetry is the next attempt of a failed run, waiting in the run loop
+// for its retry delay.
+type pendingRetry struct {
+	job     Job
+	health  *entryHealth
+	slots   slots
+	data    *TemplateData
+	attempt int
+	due     time.Time
+	// inLoop tells whether the attempt runs in the run loop, like the run
+	// that failed.
+	inLoop bool
+}
+
+// runQueued runs j like runWithRecovery, for a run queued at the given time,
+// or now if it is zero, retrying it as set by WithRetries.
+func (c *Cron) runQueued(j Job, h *entryHealth, s slots, d *TemplateData, queuedAt time.Time) error {
+	c.loop.dispatch()
+	r, err := c.runAttempts(j, h, s, d, queuedAt, 1, false)
+	if r != nil {
+		c.do(func() { c.queueRetry(r) })
+	}
+	return err
+}
+
+// runInLoop runs j in the run loop like runQueued, for a run due now.
+func (c *Cron) runInLoop(j Job, h *entryHealth, s slots, d *TemplateData) error {
+	c.loop.dispatch()
+	r, err := c.runAttempts(j, h, s, d, time.Time{}, 1, true)
+	if r != nil {
+		c.queueRetry(r)
+	}
+	return err
+}
+
+// runAttempts runs the attempts of a run of j from the given one, in the
+// run loop or not, retrying the failed ones right away, until one succeeds
+// or no attempt is left. If the next attempt has to wait for the retry
+// delay, it is returned instead, for the run loop to schedule.
+func (c *Cron) runAttempts(j Job, h *entryHealth, s slots, d *TemplateData, queuedAt time.Time, attempt int, inLoop bool) (*pendingRetry, error) {
+	for ; ; attempt++ {
+		err := c.runAttempt(j, h, s, d, queuedAt, attempt, inLoop)
+		if err == nil || attempt > s.retries || errors.Is(err, ErrPreempted) || s.context().Err() != nil {
+			return nil, err
+		}
+		if s.retryDelay > 0 {
+			return &pendingRetry{
+				job:     j,
+				health:  h,
+				slots:   s,
+				data:    d,
+				attempt: attempt + 1,
+				due:     c.now().Add(s.retryDelay),
+				inLoop:  inLoop,
+			}, err
+		}
+		queuedAt = time.Time{}
+	}
+}
+
+// queueRetry adds r to the retries waiting in the run loop, in the order
+// they are due. It must be called through do.
+func (c *Cron) queueRetry(r *pendingRetry) {
+	i := sort.Search(len(c.retries), func(i int) bool {
+		return c.retries[i].due.After(r.due)
+	})
+	c.retries = append(c.retries, nil)
+	copy(c.retries[i+1:], c.retries[i:])
+	c.retries[i] = r
+}
+
+// retryDue runs the attempts of the retries due by now, in the run loop or
+// in the background like the runs that failed.
+func (c *Cron) retryDue(now time.Time) {
+	for len(c.retries) > 0 && !c.retries[0].due.After(now) {
+		r := c.retries[0]
+		c.retries[0] = nil
+		c.retries = c.retries[1:]
+		if r.inLoop || c.synchronous() {
+			if next, _ := c.runAttempts(r.job, r.health, r.slots, r.data, time.Time{}, r.attempt, true); next != nil {
+				c.queueRetry(next)
+			}
+			continue
+		}
+		queued := c.pool.submit(func() {
+			if next, _ := c.runAttempts(r.job, r.health, r.slots, r.data, time.Time{}, r.attempt, false); next != nil {
+				c.do(func() { c.queueRetry(next) })
+			}
+		})
+		if !queued {
+			c.skipRun(r.job, now, "Worker queue full")
+		}
+	}
+}
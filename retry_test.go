@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetries(t *testing.T) {
+	c := New(WithResultWorkers(1))
+	results := make(chan *JobResult, 10)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	c.AddJob("@yearly", &flakyJob{failures: 2}, WithRetries(3, time.Millisecond))
+	c.AddJob("@yearly", &chainJob{id: "broken", err: errors.New("broken")}, WithRetries(1, 0))
+	c.AddJob("@yearly", &chainJob{id: "once"})
+	if e, _ := c.Entry("flaky"); e.Retries != 3 || e.RetryDelay != time.Millisecond {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	c.Start()
+	defer c.Stop()
+
+	c.RunNow("flaky")
+	for i := 1; i <= 3; i++ {
+		r := <-results
+		if (r.Error == nil) != (i == 3) || r.Attempt != i || r.MaxAttempts != 4 || r.Retry != (i > 1) || r.Final() != (i == 3) {
+			t.Errorf("unexpected result of attempt %d: %+v", i, r)
+		}
+	}
+
+	c.RunNow("broken")
+	for i := 1; i <= 2; i++ {
+		if r := <-results; r.Error == nil || r.Attempt != i || r.Final() != (i == 2) {
+			t.Errorf("unexpected result of attempt %d: %+v", i, r)
+		}
+	}
+
+	c.RunNow("once")
+	if r := <-results; r.Attempt != 1 || r.MaxAttempts != 1 || r.Retry || !r.Final() {
+		t.Errorf("unexpected result %+v", r)
+	}
+	select {
+	case r := <-results:
+		t.Errorf("unexpected result %+v", r)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestRetryDelayFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	var results []*JobResult
+	c.AddResultHandler(func(r *JobResult) { results = append(results, r) })
+	c.AddJob("@hourly", &flakyJob{failures: 1}, WithRetries(2, 10*time.Minute))
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(time.Hour)
+	if len(results) != 1 || results[0].Error == nil || results[0].Attempt != 1 {
+		t.Fatalf("expected the first attempt to fail, got %+v", results)
+	}
+	clock.Advance(9 * time.Minute)
+	if len(results) != 1 {
+		t.Fatalf("expected the retry to wait for its delay, got %d results", len(results))
+	}
+	clock.Advance(time.Minute)
+	if len(results) != 2 {
+		t.Fatalf("expected the retry to run, got %d results", len(results))
+	}
+	if r := results[1]; r.Error != nil || r.Attempt != 2 || !r.Retry || !r.Final() {
+		t.Errorf("unexpected result of the retry %+v", r)
+	}
+}
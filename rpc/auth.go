@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ringtail/go-cron/admin"
+	"github.com/ringtail/go-cron/rpc/cronpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodRoles are the roles required by the CronService methods. Other
+// methods require admin.RoleAdmin.
+var methodRoles = map[string]admin.Role{
+	cronpb.CronService_ListEntries_FullMethodName:    admin.RoleReader,
+	cronpb.CronService_StreamResults_FullMethodName:  admin.RoleReader,
+	cronpb.CronService_StreamUpcoming_FullMethodName: admin.RoleReader,
+	cronpb.CronService_RunNow_FullMethodName:         admin.RoleOperator,
+	cronpb.CronService_AddJob_FullMethodName:         admin.RoleAdmin,
+	cronpb.CronService_RemoveJob_FullMethodName:      admin.RoleAdmin,
+}
+
+// AuthInterceptors return server options requiring calls to be
+// authenticated by one of auths, with the roles of the admin HTTP API.
+// Tokens are read from the "authorization: Bearer" or "x-api-key" metadata
+// and certificates from the verified TLS chain.
+//
+//	srv := grpc.NewServer(rpc.AuthInterceptors(admin.APIKeyAuth{...})...)
+func AuthInterceptors(auths ...admin.Authenticator) []grpc.ServerOption {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, info.FullMethod, auths)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), info.FullMethod, auths)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ss, ctx})
+	}
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream)}
+}
+
+// authorize authenticates the caller of method and returns a context
+// carrying its principal.
+func authorize(ctx context.Context, method string, auths []admin.Authenticator) (context.Context, error) {
+	var creds admin.Credentials
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 && strings.HasPrefix(v[0], "Bearer ") {
+		creds.Token = strings.TrimSpace(v[0][len("Bearer "):])
+	} else if v := md.Get("x-api-key"); len(v) > 0 {
+		creds.Token = v[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			creds.Certificates = info.State.VerifiedChains[0]
+		}
+	}
+
+	p, err := admin.Authenticate(ctx, creds, auths...)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	need, ok := methodRoles[method]
+	if !ok {
+		need = admin.RoleAdmin
+	}
+	if p.Role < need {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires the %s role", p.Name, need)
+	}
+	return admin.WithPrincipal(ctx, p), nil
+}
+
+// authStream overrides the context of a server stream.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
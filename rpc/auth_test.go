@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ringtail/go-cron/admin"
+	"github.com/ringtail/go-cron/rpc/cronpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptors(t *testing.T) {
+	client := newTestClient(t, AuthInterceptors(admin.APIKeyAuth{
+		"reader": {Name: "ro", Role: admin.RoleReader},
+		"admin":  {Name: "root", Role: admin.RoleAdmin},
+	})...)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+	}
+
+	if _, err := client.ListEntries(context.Background(), &cronpb.ListEntriesRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+	if _, err := client.ListEntries(withKey("reader"), &cronpb.ListEntriesRequest{}); err != nil {
+		t.Errorf("expected reader to list entries, got %v", err)
+	}
+	add := &cronpb.AddJobRequest{Name: "job", Spec: "@hourly", Type: "noop"}
+	if _, err := client.AddJob(withKey("reader"), add); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+	if _, err := client.AddJob(withKey("admin"), add); err != nil {
+		t.Errorf("expected admin to add a job, got %v", err)
+	}
+
+	upcoming, err := client.StreamUpcoming(withKey("reader"), &cronpb.StreamUpcomingRequest{})
+	if err == nil {
+		_, err = upcoming.Recv()
+	}
+	if err != nil {
+		t.Errorf("expected reader to stream the upcoming firings, got %v", err)
+	}
+
+	stream, err := client.StreamResults(context.Background(), &cronpb.StreamResultsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated stream, got %v", err)
+	}
+}
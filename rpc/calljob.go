@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// CallJobType is the job type of CallJob.
+const CallJobType = "grpc"
+
+// CallJob invokes a unary gRPC method with a JSON request and reports the
+// JSON response as the result message. The method is described either by a
+// precompiled FileDescriptorSet or, if none is given, by the server's
+// reflection service.
+type CallJob struct {
+	id string
+
+	// Target is the server address, e.g. "localhost:50051".
+	Target string
+	// Method is the full method name, e.g. "pkg.Service/Method".
+	Method string
+	// Payload is the request message in protobuf JSON form.
+	Payload string
+	// DescriptorSet is the path of a FileDescriptorSet (as produced by
+	// protoc --descriptor_set_out --include_imports) describing the method.
+	DescriptorSet string
+	// Insecure disables transport security.
+	Insecure bool
+	// Timeout bounds the call, including the reflection lookup.
+	Timeout time.Duration
+	// Metadata is sent along with the call.
+	Metadata map[string]string
+}
+
+// NewCallJob returns a job with the given id calling method on target.
+func NewCallJob(id, target, method, payload string) *CallJob {
+	return &CallJob{id: id, Target: target, Method: method, Payload: payload}
+}
+
+func init() {
+	cron.RegisterJobType(CallJobType, newCallJob)
+}
+
+// newCallJob builds a CallJob from the "target", "method", "payload",
+// "descriptor_set", "insecure", "timeout" and "metadata.KEY" parameters.
+func newCallJob(id string, params map[string]string) (cron.Job, error) {
+	j := NewCallJob(id, params["target"], params["method"], params["payload"])
+	if j.Target == "" || j.Method == "" {
+		return nil, fmt.Errorf("Missing target or method parameter")
+	}
+	j.DescriptorSet = params["descriptor_set"]
+	j.Insecure = params["insecure"] == "true"
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	for k, v := range params {
+		if strings.HasPrefix(k, "metadata.") {
+			if j.Metadata == nil {
+				j.Metadata = make(map[string]string)
+			}
+			j.Metadata[k[len("metadata."):]] = v
+		}
+	}
+	return j, nil
+}
+
+func (j *CallJob) ID() string { return j.id }
+
+func (j *CallJob) JobType() string { return CallJobType }
+
+func (j *CallJob) Params() map[string]string {
+	params := map[string]string{"target": j.Target, "method": j.Method}
+	if j.Payload != "" {
+		params["payload"] = j.Payload
+	}
+	if j.DescriptorSet != "" {
+		params["descriptor_set"] = j.DescriptorSet
+	}
+	if j.Insecure {
+		params["insecure"] = "true"
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	for k, v := range j.Metadata {
+		params["metadata."+k] = v
+	}
+	return params
+}
+
+// Run performs the call.
+func (j *CallJob) Run() (msg string, err error) {
+	ctx := context.Background()
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	if len(j.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(j.Metadata))
+	}
+
+	creds := credentials.NewTLS(&tls.Config{})
+	if j.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(j.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	method, err := j.resolve(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+	req := dynamicpb.NewMessage(method.Input())
+	if j.Payload != "" {
+		if err := protojson.Unmarshal([]byte(j.Payload), req); err != nil {
+			return "", fmt.Errorf("Failed to parse payload: %s", err)
+		}
+	}
+	resp := dynamicpb.NewMessage(method.Output())
+	if err := conn.Invoke(ctx, "/"+j.Method, req, resp); err != nil {
+		return "", err
+	}
+	out, err := protojson.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// resolve finds the descriptor of the method.
+func (j *CallJob) resolve(ctx context.Context, conn *grpc.ClientConn) (protoreflect.MethodDescriptor, error) {
+	i := strings.LastIndexByte(j.Method, '/')
+	if i <= 0 {
+		return nil, fmt.Errorf("Invalid method %q, expected \"pkg.Service/Method\"", j.Method)
+	}
+	service, name := j.Method[:i], j.Method[i+1:]
+
+	var (
+		set *descriptorpb.FileDescriptorSet
+		err error
+	)
+	if j.DescriptorSet != "" {
+		set, err = readDescriptorSet(j.DescriptorSet)
+	} else {
+		set, err = reflectDescriptors(ctx, conn, service)
+	}
+	if err != nil {
+		return nil, err
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("Service %s: %s", service, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("Service %s has no method %s", service, name)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("Method %s is not unary", j.Method)
+	}
+	return md, nil
+}
+
+func readDescriptorSet(path string) (*descriptorpb.FileDescriptorSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("Failed to decode %s: %s", path, err)
+	}
+	return set, nil
+}
+
+// reflectDescriptors asks the server's reflection service for the file
+// defining the service and its dependencies.
+func reflectDescriptors(ctx context.Context, conn *grpc.ClientConn, service string) (*descriptorpb.FileDescriptorSet, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("Reflection lookup of %s failed: %s", service, e.ErrorMessage)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, fd); err != nil {
+			return nil, err
+		}
+		if !seen[fd.GetName()] {
+			seen[fd.GetName()] = true
+			set.File = append(set.File, fd)
+		}
+	}
+	// Well-known dependencies may have been left out by the server.
+	for _, fd := range set.File {
+		for _, dep := range fd.GetDependency() {
+			if seen[dep] {
+				continue
+			}
+			if d, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				seen[dep] = true
+				set.File = append(set.File, protodesc.ToFileDescriptorProto(d))
+			}
+		}
+	}
+	return set, nil
+}
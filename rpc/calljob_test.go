@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/rpc/cronpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// listen serves the management service of a Cron with one entry, with or
+// without reflection.
+func listen(t *testing.T, withReflection bool) string {
+	c := cron.New()
+	if err := c.AddJobConfig(cron.JobConfig{Name: "job", Spec: "@hourly", Type: "noop"}); err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	cronpb.RegisterCronServiceServer(srv, NewServer(c))
+	if withReflection {
+		reflection.Register(srv)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestCallJobReflection(t *testing.T) {
+	target := listen(t, true)
+	job, err := newCallJob("call", map[string]string{
+		"target":   target,
+		"method":   "cron.v1.CronService/ListEntries",
+		"payload":  "{}",
+		"insecure": "true",
+		"timeout":  "5s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg, `"id":"job"`) && !strings.Contains(msg, `"id": "job"`) {
+		t.Errorf("unexpected response %s", msg)
+	}
+
+	j := job.(*CallJob)
+	j.Method = "cron.v1.CronService/Missing"
+	if _, err := j.Run(); err == nil {
+		t.Error("expected an error for a missing method")
+	}
+	j.Method = "cron.v1.CronService/StreamResults"
+	if _, err := j.Run(); err == nil {
+		t.Error("expected an error for a streaming method")
+	}
+}
+
+func TestCallJobDescriptorSet(t *testing.T) {
+	target := listen(t, false)
+
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		protodesc.ToFileDescriptorProto(cronpb.File_cron_proto),
+	}}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cron.pb")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewCallJob("call", target, "cron.v1.CronService/RunNow", `{"id": "missing"}`)
+	j.Insecure = true
+	j.Timeout = 5 * time.Second
+	if _, err := j.Run(); err == nil || !strings.Contains(err.Error(), "unknown service") {
+		t.Errorf("expected reflection to be unavailable, got %v", err)
+	}
+	j.DescriptorSet = path
+	if _, err := j.Run(); err == nil || !strings.Contains(err.Error(), "NotFound") {
+		t.Errorf("expected the call to reach the server, got %v", err)
+	}
+}
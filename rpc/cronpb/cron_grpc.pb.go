@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: cron.proto
+
+package cronpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	CronService_ListEntries_FullMethodName    = "/cron.v1.CronService/ListEntries"
+	CronService_AddJob_FullMethodName         = "/cron.v1.CronService/AddJob"
+	CronService_RemoveJob_FullMethodName      = "/cron.v1.CronService/RemoveJob"
+	CronService_RunNow_FullMethodName         = "/cron.v1.CronService/RunNow"
+	CronService_StreamResults_FullMethodName  = "/cron.v1.CronService/StreamResults"
+	CronService_StreamUpcoming_FullMethodName = "/cron.v1.CronService/StreamUpcoming"
+)
+
+// CronServiceClient is the client API for CronService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CronService manages the entries of a running scheduler.
+type CronServiceClient interface {
+	// ListEntries returns every entry, ordered by next activation time.
+	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
+	// AddJob adds a job built by a registered job type.
+	AddJob(ctx context.Context, in *AddJobRequest, opts ...grpc.CallOption) (*Entry, error)
+	// RemoveJob removes an entry.
+	RemoveJob(ctx context.Context, in *RemoveJobRequest, opts ...grpc.CallOption) (*RemoveJobResponse, error)
+	// RunNow runs an entry's job right away.
+	RunNow(ctx context.Context, in *RunNowRequest, opts ...grpc.CallOption) (*RunNowResponse, error)
+	// StreamResults streams the result of every job run until cancelled.
+	StreamResults(ctx context.Context, in *StreamResultsRequest, opts ...grpc.CallOption) (CronService_StreamResultsClient, error)
+	// StreamUpcoming streams the next run of the entries each time it is
+	// computed, starting with the next run of every entry, until cancelled.
+	StreamUpcoming(ctx context.Context, in *StreamUpcomingRequest, opts ...grpc.CallOption) (CronService_StreamUpcomingClient, error)
+}
+
+type cronServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCronServiceClient(cc grpc.ClientConnInterface) CronServiceClient {
+	return &cronServiceClient{cc}
+}
+
+func (c *cronServiceClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEntriesResponse)
+	err := c.cc.Invoke(ctx, CronService_ListEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cronServiceClient) AddJob(ctx context.Context, in *AddJobRequest, opts ...grpc.CallOption) (*Entry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, CronService_AddJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cronServiceClient) RemoveJob(ctx context.Context, in *RemoveJobRequest, opts ...grpc.CallOption) (*RemoveJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveJobResponse)
+	err := c.cc.Invoke(ctx, CronService_RemoveJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cronServiceClient) RunNow(ctx context.Context, in *RunNowRequest, opts ...grpc.CallOption) (*RunNowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunNowResponse)
+	err := c.cc.Invoke(ctx, CronService_RunNow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cronServiceClient) StreamResults(ctx context.Context, in *StreamResultsRequest, opts ...grpc.CallOption) (CronService_StreamResultsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CronService_ServiceDesc.Streams[0], CronService_StreamResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cronServiceStreamResultsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CronService_StreamResultsClient interface {
+	Recv() (*JobResult, error)
+	grpc.ClientStream
+}
+
+type cronServiceStreamResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cronServiceStreamResultsClient) Recv() (*JobResult, error) {
+	m := new(JobResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cronServiceClient) StreamUpcoming(ctx context.Context, in *StreamUpcomingRequest, opts ...grpc.CallOption) (CronService_StreamUpcomingClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CronService_ServiceDesc.Streams[1], CronService_StreamUpcoming_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cronServiceStreamUpcomingClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CronService_StreamUpcomingClient interface {
+	Recv() (*Firing, error)
+	grpc.ClientStream
+}
+
+type cronServiceStreamUpcomingClient struct {
+	grpc.ClientStream
+}
+
+func (x *cronServiceStreamUpcomingClient) Recv() (*Firing, error) {
+	m := new(Firing)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CronServiceServer is the server API for CronService service.
+// All implementations must embed UnimplementedCronServiceServer
+// for forward compatibility
+//
+// CronService manages the entries of a running scheduler.
+type CronServiceServer interface {
+	// ListEntries returns every entry, ordered by next activation time.
+	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
+	// AddJob adds a job built by a registered job type.
+	AddJob(context.Context, *AddJobRequest) (*Entry, error)
+	// RemoveJob removes an entry.
+	RemoveJob(context.Context, *RemoveJobRequest) (*RemoveJobResponse, error)
+	// RunNow runs an entry's job right away.
+	RunNow(context.Context, *RunNowRequest) (*RunNowResponse, error)
+	// StreamResults streams the result of every job run until cancelled.
+	StreamResults(*StreamResultsRequest, CronService_StreamResultsServer) error
+	// StreamUpcoming streams the next run of the entries each time it is
+	// computed, starting with the next run of every entry, until cancelled.
+	StreamUpcoming(*StreamUpcomingRequest, CronService_StreamUpcomingServer) error
+	mustEmbedUnimplementedCronServiceServer()
+}
+
+// UnimplementedCronServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCronServiceServer struct {
+}
+
+func (UnimplementedCronServiceServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedCronServiceServer) AddJob(context.Context, *AddJobRequest) (*Entry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddJob not implemented")
+}
+func (UnimplementedCronServiceServer) RemoveJob(context.Context, *RemoveJobRequest) (*RemoveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveJob not implemented")
+}
+func (UnimplementedCronServiceServer) RunNow(context.Context, *RunNowRequest) (*RunNowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunNow not implemented")
+}
+func (UnimplementedCronServiceServer) StreamResults(*StreamResultsRequest, CronService_StreamResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamResults not implemented")
+}
+func (UnimplementedCronServiceServer) StreamUpcoming(*StreamUpcomingRequest, CronService_StreamUpcomingServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUpcoming not implemented")
+}
+func (UnimplementedCronServiceServer) mustEmbedUnimplementedCronServiceServer() {}
+
+// UnsafeCronServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CronServiceServer will
+// result in compilation errors.
+type UnsafeCronServiceServer interface {
+	mustEmbedUnimplementedCronServiceServer()
+}
+
+func RegisterCronServiceServer(s grpc.ServiceRegistrar, srv CronServiceServer) {
+	s.RegisterService(&CronService_ServiceDesc, srv)
+}
+
+func _CronService_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServiceServer).ListEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CronService_ListEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServiceServer).ListEntries(ctx, req.(*ListEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CronService_AddJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServiceServer).AddJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CronService_AddJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServiceServer).AddJob(ctx, req.(*AddJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CronService_RemoveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServiceServer).RemoveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CronService_RemoveJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServiceServer).RemoveJob(ctx, req.(*RemoveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CronService_RunNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServiceServer).RunNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CronService_RunNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServiceServer).RunNow(ctx, req.(*RunNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CronService_StreamResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CronServiceServer).StreamResults(m, &cronServiceStreamResultsServer{ServerStream: stream})
+}
+
+type CronService_StreamResultsServer interface {
+	Send(*JobResult) error
+	grpc.ServerStream
+}
+
+type cronServiceStreamResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cronServiceStreamResultsServer) Send(m *JobResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CronService_StreamUpcoming_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUpcomingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CronServiceServer).StreamUpcoming(m, &cronServiceStreamUpcomingServer{ServerStream: stream})
+}
+
+type CronService_StreamUpcomingServer interface {
+	Send(*Firing) error
+	grpc.ServerStream
+}
+
+type cronServiceStreamUpcomingServer struct {
+	grpc.ServerStream
+}
+
+func (x *cronServiceStreamUpcomingServer) Send(m *Firing) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// CronService_ServiceDesc is the grpc.ServiceDesc for CronService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CronService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cron.v1.CronService",
+	HandlerType: (*CronServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListEntries",
+			Handler:    _CronService_ListEntries_Handler,
+		},
+		{
+			MethodName: "AddJob",
+			Handler:    _CronService_AddJob_Handler,
+		},
+		{
+			MethodName: "RemoveJob",
+			Handler:    _CronService_RemoveJob_Handler,
+		},
+		{
+			MethodName: "RunNow",
+			Handler:    _CronService_RunNow_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamResults",
+			Handler:       _CronService_StreamResults_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamUpcoming",
+			Handler:       _CronService_StreamUpcoming_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cron.proto",
+}
@@ -0,0 +1,5 @@
+// Package cronpb contains the protobuf messages and gRPC stubs of the cron
+// management service.
+package cronpb
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cron.proto
@@ -0,0 +1,20 @@
+module github.com/ringtail/go-cron/rpc
+
+go 1.21
+
+require (
+	github.com/ringtail/go-cron v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/satori/go.uuid v1.2.0 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
+
+replace github.com/ringtail/go-cron => ../
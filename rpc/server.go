@@ -0,0 +1,150 @@
+// Package rpc provides a gRPC service managing a cron.Cron.
+//
+//	srv := grpc.NewServer()
+//	cronpb.RegisterCronServiceServer(srv, rpc.NewServer(c))
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/rpc/cronpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// resultBuffer is the number of results buffered per StreamResults call.
+const resultBuffer = 64
+
+// upcomingBuffer is the number of firings buffered per StreamUpcoming call.
+const upcomingBuffer = 64
+
+// Server implements cronpb.CronServiceServer on top of a Cron.
+type Server struct {
+	cronpb.UnimplementedCronServiceServer
+	cron *cron.Cron
+}
+
+// NewServer returns a gRPC management service for c.
+func NewServer(c *cron.Cron) *Server {
+	return &Server{cron: c}
+}
+
+// ListEntries returns every entry.
+func (s *Server) ListEntries(ctx context.Context, req *cronpb.ListEntriesRequest) (*cronpb.ListEntriesResponse, error) {
+	entries := s.cron.Entries()
+	resp := &cronpb.ListEntriesResponse{Entries: make([]*cronpb.Entry, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, entryProto(e))
+	}
+	return resp, nil
+}
+
+// AddJob adds a job built by a registered job type.
+func (s *Server) AddJob(ctx context.Context, req *cronpb.AddJobRequest) (*cronpb.Entry, error) {
+	err := s.cron.AddJobConfig(cron.JobConfig{
+		Name:    req.Name,
+		Spec:    req.Spec,
+		Type:    req.Type,
+		Params:  req.Params,
+		Timeout: req.Timeout,
+		Retries: int(req.Retries),
+	})
+	if errors.Is(err, cron.ErrDuplicateJob) {
+		return nil, status.Errorf(codes.AlreadyExists, "entry %s already exists", req.Name)
+	} else if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	e, ok := s.cron.Entry(req.Name)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "entry %s vanished", req.Name)
+	}
+	return entryProto(e), nil
+}
+
+// RemoveJob removes an entry.
+func (s *Server) RemoveJob(ctx context.Context, req *cronpb.RemoveJobRequest) (*cronpb.RemoveJobResponse, error) {
+	if err := s.cron.Remove(req.Id); errors.Is(err, cron.ErrJobNotFound) {
+		return nil, status.Errorf(codes.NotFound, "entry %s not found", req.Id)
+	}
+	return &cronpb.RemoveJobResponse{}, nil
+}
+
+// RunNow runs an entry's job right away.
+func (s *Server) RunNow(ctx context.Context, req *cronpb.RunNowRequest) (*cronpb.RunNowResponse, error) {
+	if err := s.cron.RunNow(req.Id); errors.Is(err, cron.ErrJobNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &cronpb.RunNowResponse{}, nil
+}
+
+// StreamResults streams job results until the client cancels.
+func (s *Server) StreamResults(req *cronpb.StreamResultsRequest, stream cronpb.CronService_StreamResultsServer) error {
+	results, cancel := s.cron.SubscribeResults(resultBuffer)
+	defer cancel()
+	for {
+		select {
+		case r := <-results:
+			if err := stream.Send(resultProto(r)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// StreamUpcoming streams the next run of the entries until the client
+// cancels.
+func (s *Server) StreamUpcoming(req *cronpb.StreamUpcomingRequest, stream cronpb.CronService_StreamUpcomingServer) error {
+	ids := make(map[string]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		ids[id] = true
+	}
+	firings, cancel := s.cron.SubscribeUpcoming(upcomingBuffer)
+	defer cancel()
+	for {
+		select {
+		case f := <-firings:
+			if len(ids) > 0 && !ids[f.Entry.ID] {
+				continue
+			}
+			if err := stream.Send(&cronpb.Firing{Entry: entryProto(f.Entry), Time: timestamppb.New(f.Time)}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func entryProto(e *cron.Entry) *cronpb.Entry {
+	pb := &cronpb.Entry{
+		Id:     e.Job.ID(),
+		Name:   e.Name,
+		Spec:   e.Spec,
+		Paused: e.Paused,
+	}
+	if dj, ok := e.Job.(cron.DescribedJob); ok {
+		pb.Type, pb.Params = dj.JobType(), dj.Params()
+	}
+	if !e.Prev.IsZero() {
+		pb.Prev = timestamppb.New(e.Prev)
+	}
+	if !e.Next.IsZero() {
+		pb.Next = timestamppb.New(e.Next)
+	}
+	return pb
+}
+
+func resultProto(r *cron.JobResult) *cronpb.JobResult {
+	pb := &cronpb.JobResult{JobId: r.JobId, Msg: r.Msg}
+	if r.Error != nil {
+		pb.Error = r.Error.Error()
+	}
+	return pb
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"github.com/ringtail/go-cron/rpc/cronpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type noopJob struct{ id string }
+
+func (j *noopJob) ID() string                { return j.id }
+func (j *noopJob) Run() (string, error)      { return "ok", nil }
+func (j *noopJob) JobType() string           { return "noop" }
+func (j *noopJob) Params() map[string]string { return nil }
+
+func init() {
+	cron.RegisterJobType("noop", func(id string, params map[string]string) (cron.Job, error) {
+		return &noopJob{id}, nil
+	})
+}
+
+func newTestClient(t *testing.T, opts ...grpc.ServerOption) cronpb.CronServiceClient {
+	c := cron.New()
+	c.AddResultHandler(func(*cron.JobResult) {})
+	c.Start()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(opts...)
+	cronpb.RegisterCronServiceServer(srv, NewServer(c))
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+		c.Stop()
+	})
+	return cronpb.NewCronServiceClient(conn)
+}
+
+func TestManageEntries(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: "job", Spec: "bogus", Type: "noop"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	e, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: "job", Spec: "@hourly", Type: "noop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Id != "job" || e.Type != "noop" || e.Next == nil {
+		t.Errorf("unexpected entry %v", e)
+	}
+	if _, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: "job", Spec: "@hourly", Type: "noop"}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists, got %v", err)
+	}
+
+	list, err := client.ListEntries(ctx, &cronpb.ListEntriesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(list.Entries))
+	}
+
+	if _, err := client.RemoveJob(ctx, &cronpb.RemoveJobRequest{Id: "job"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RemoveJob(ctx, &cronpb.RemoveJobRequest{Id: "job"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestStreamUpcoming(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for _, name := range []string{"a", "b"} {
+		if _, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: name, Spec: "@yearly", Type: "noop"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream, err := client.StreamUpcoming(ctx, &cronpb.StreamUpcomingRequest{Ids: []string{"b", "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Entry.Id != "b" || !f.Time.AsTime().Equal(f.Entry.Next.AsTime()) {
+		t.Errorf("unexpected firing %v", f)
+	}
+
+	if _, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: "c", Spec: "@monthly", Type: "noop"}); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if f.Entry.Id != "c" || f.Time.AsTime().Day() != 1 {
+		t.Errorf("unexpected firing %v", f)
+	}
+}
+
+func TestStreamResults(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := client.AddJob(ctx, &cronpb.AddJobRequest{Name: "job", Spec: "@yearly", Type: "noop"}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.StreamResults(ctx, &cronpb.StreamResultsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the subscription to be in place before running the job.
+	time.Sleep(50 * time.Millisecond)
+	if _, err := client.RunNow(ctx, &cronpb.RunNowRequest{Id: "job"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.JobId != "job" || r.Msg != "ok" {
+		t.Errorf("unexpected result %v", r)
+	}
+}
@@ -0,0 +1,94 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// SchedulerStats describes the scheduler as a whole, complementing the
+// statistics of each entry, e.g. for capacity and health dashboards.
+type SchedulerStats struct {
+	// Started is when the scheduler was last started, and Uptime how long
+	// it has been running since. Both are zero while it is stopped.
+	Started time.Time     `json:"started"`
+	Uptime  time.Duration `json:"uptime"`
+	// Wakeups is the number of times the run loop woke up to run the
+	// entries due.
+	Wakeups uint64 `json:"wakeups"`
+	// Dispatches is the number of runs started, retries excluded.
+	Dispatches uint64 `json:"dispatches"`
+	// Entries is the number of entries, and Running the number of runs in
+	// progress.
+	Entries int `json:"entries"`
+	Running int `json:"running"`
+	// Waiting is the number of runs waiting for a slot of
+	// WithMaxConcurrent, and Queued the number of runs waiting in the
+	// queue of WithBatchDispatch.
+	Waiting int `json:"waiting"`
+	Queued  int `json:"queued"`
+	// Results is the state of the queues of the results waiting for the
+	// result handler.
+	Results ResultQueueStats `json:"results"`
+	// LastWakeup is when the run loop last woke up, and LastLateness how
+	// long after it expected to.
+	LastWakeup   time.Time     `json:"last_wakeup"`
+	LastLateness time.Duration `json:"last_lateness"`
+}
+
+// loopStats counts what the run loop does.
+type loopStats struct {
+	mu         sync.Mutex
+	started    time.Time
+	wakeups    uint64
+	dispatches uint64
+	lastWakeup time.Time
+	lateness   time.Duration
+}
+
+func (s *loopStats) start(now time.Time) {
+	s.mu.Lock()
+	s.started = now
+	s.mu.Unlock()
+}
+
+func (s *loopStats) wakeup(wake, now time.Time) {
+	s.mu.Lock()
+	s.wakeups++
+	s.lastWakeup, s.lateness = now, now.Sub(wake)
+	s.mu.Unlock()
+}
+
+func (s *loopStats) dispatch() {
+	s.mu.Lock()
+	s.dispatches++
+	s.mu.Unlock()
+}
+
+// SchedulerStats returns the statistics of the scheduler.
+func (c *Cron) SchedulerStats() SchedulerStats {
+	c.loop.mu.Lock()
+	s := SchedulerStats{
+		Wakeups:      c.loop.wakeups,
+		Dispatches:   c.loop.dispatches,
+		LastWakeup:   c.loop.lastWakeup,
+		LastLateness: c.loop.lateness,
+	}
+	started := c.loop.started
+	c.loop.mu.Unlock()
+
+	c.do(func() {
+		s.Entries = len(c.entries)
+		if c.running {
+			s.Started, s.Uptime = started, c.now().Sub(started)
+		}
+	})
+	s.Running = c.active.count()
+	if c.sem != nil {
+		s.Waiting = c.sem.pending()
+	}
+	if c.batch != nil {
+		s.Queued = c.batch.pending()
+	}
+	s.Results = c.ResultQueue()
+	return s
+}
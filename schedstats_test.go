@@ -0,0 +1,31 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerStats(t *testing.T) {
+	start := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddFunc("0 0 * * * *", func() (string, error) { return "", nil })
+	c.AddFunc("@yearly", func() (string, error) { return "", nil })
+	if s := c.SchedulerStats(); !s.Started.IsZero() || s.Uptime != 0 || s.Entries != 2 {
+		t.Errorf("unexpected stats before starting %+v", s)
+	}
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(3 * time.Hour)
+	s := c.SchedulerStats()
+	if !s.Started.Equal(start) || s.Uptime != 3*time.Hour {
+		t.Errorf("expected 3h of uptime since %v, got %+v", start, s)
+	}
+	if s.Wakeups != 3 || s.Dispatches != 3 || s.Entries != 2 || s.Running != 0 {
+		t.Errorf("expected 3 wakeups and dispatches, got %+v", s)
+	}
+	if want := start.Add(3 * time.Hour); !s.LastWakeup.Equal(want) || s.LastLateness != 0 {
+		t.Errorf("expected a wakeup on time at %v, got %+v", want, s)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/ringtail/go-cron/script
+
+go 1.21
+
+replace github.com/ringtail/go-cron => ../
+
+require (
+	github.com/ringtail/go-cron v0.0.0-00010101000000-000000000000
+	go.starlark.net v0.0.0-20260210143700-b62fd896b91b
+)
+
+require (
+	github.com/satori/go.uuid v1.2.0 // indirect
+	golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
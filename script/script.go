@@ -0,0 +1,224 @@
+// Package script provides a cron job type running Starlark scripts, so jobs
+// can be added or changed at runtime without recompiling the program.
+//
+// Scripts run in a restricted environment: there is no file system or load()
+// access, only the json, time and http modules and a log function.
+//
+//	resp = http.get("https://example.com/health")
+//	if resp.status != 200:
+//	    fail("unhealthy: %d" % resp.status)
+//	log("healthy")
+//	result = json.decode(resp.body)["version"]
+package script
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ringtail/go-cron"
+	"go.starlark.net/lib/json"
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// ScriptJobType is the job type of ScriptJob.
+const ScriptJobType = "starlark"
+
+// MaxResponseBody is the maximum number of bytes of an HTTP response body
+// made available to scripts.
+const MaxResponseBody = 1 << 20
+
+// ScriptJob runs a Starlark script. The result message is the value of the
+// global "result" if the script sets it, or else the lines passed to log.
+// A script fails by calling fail() or by raising any other error.
+type ScriptJob struct {
+	id string
+
+	Source string
+	// Timeout cancels the script if it runs longer. Zero means no timeout.
+	Timeout time.Duration
+	// Client is used by the http module. It defaults to a client with a
+	// 30 second timeout.
+	Client *http.Client
+
+	program *starlark.Program
+}
+
+// NewScriptJob compiles source and returns a job with the given id running
+// it.
+func NewScriptJob(id, source string) (*ScriptJob, error) {
+	_, program, err := starlark.SourceProgramOptions(fileOptions, id+".star", source, predeclared.Has)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compile script: %s", err)
+	}
+	return &ScriptJob{id: id, Source: source, program: program}, nil
+}
+
+func init() {
+	cron.RegisterJobType(ScriptJobType, newScriptJob)
+}
+
+// newScriptJob builds a ScriptJob from the "source" and "timeout" parameters.
+func newScriptJob(id string, params map[string]string) (cron.Job, error) {
+	j, err := NewScriptJob(id, params["source"])
+	if err != nil {
+		return nil, err
+	}
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	return j, nil
+}
+
+func (j *ScriptJob) ID() string { return j.id }
+
+func (j *ScriptJob) JobType() string { return ScriptJobType }
+
+func (j *ScriptJob) Params() map[string]string {
+	params := map[string]string{"source": j.Source}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	return params
+}
+
+// fileOptions enables the language features scripts may use.
+var fileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+	Recursion:       true,
+}
+
+// predeclared lists the names available to scripts; the values of "http"
+// and "log" are bound per run.
+var predeclared = starlark.StringDict{
+	"json": json.Module,
+	"time": startime.Module,
+	"http": starlark.None,
+	"log":  starlark.None,
+}
+
+// Run executes the script.
+func (j *ScriptJob) Run() (msg string, err error) {
+	var logs []string
+	thread := &starlark.Thread{
+		Name:  j.id,
+		Print: func(_ *starlark.Thread, msg string) { logs = append(logs, msg) },
+		Load: func(*starlark.Thread, string) (starlark.StringDict, error) {
+			return nil, fmt.Errorf("load is not allowed")
+		},
+	}
+	if j.Timeout > 0 {
+		timer := time.AfterFunc(j.Timeout, func() {
+			thread.Cancel(fmt.Sprintf("timed out after %s", j.Timeout))
+		})
+		defer timer.Stop()
+	}
+
+	env := starlark.StringDict{}
+	for name, v := range predeclared {
+		env[name] = v
+	}
+	env["http"] = j.httpModule()
+	env["log"] = starlark.NewBuiltin("log", func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			if s, ok := starlark.AsString(arg); ok {
+				parts[i] = s
+			} else {
+				parts[i] = arg.String()
+			}
+		}
+		thread.Print(thread, strings.Join(parts, " "))
+		return starlark.None, nil
+	})
+
+	globals, err := j.program.Init(thread, env)
+	out := strings.Join(logs, "\n")
+	if err != nil {
+		if evalErr, ok := err.(*starlark.EvalError); ok {
+			return out, fmt.Errorf("%s", evalErr.Backtrace())
+		}
+		return out, err
+	}
+	if result, ok := globals["result"]; ok {
+		if s, ok := starlark.AsString(result); ok {
+			return s, nil
+		}
+		return result.String(), nil
+	}
+	return out, nil
+}
+
+// httpModule returns the http module with get and post functions. Both
+// return a struct with status, headers and body fields.
+func (j *ScriptJob) httpModule() *starlarkstruct.Module {
+	client := j.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	request := func(method string) *starlark.Builtin {
+		return starlark.NewBuiltin("http."+strings.ToLower(method), func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var (
+				url     string
+				body    string
+				headers *starlark.Dict
+			)
+			if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &url, "body?", &body, "headers?", &headers); err != nil {
+				return nil, err
+			}
+			var reader io.Reader
+			if method == "POST" {
+				reader = strings.NewReader(body)
+			}
+			req, err := http.NewRequest(method, url, reader)
+			if err != nil {
+				return nil, err
+			}
+			if headers != nil {
+				for _, item := range headers.Items() {
+					k, _ := starlark.AsString(item[0])
+					v, _ := starlark.AsString(item[1])
+					req.Header.Set(k, v)
+				}
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			data, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxResponseBody))
+			if err != nil {
+				return nil, err
+			}
+			respHeaders := starlark.NewDict(len(resp.Header))
+			for k := range resp.Header {
+				respHeaders.SetKey(starlark.String(k), starlark.String(resp.Header.Get(k)))
+			}
+			return starlarkstruct.FromStringDict(starlark.String("response"), starlark.StringDict{
+				"status":  starlark.MakeInt(resp.StatusCode),
+				"headers": respHeaders,
+				"body":    starlark.String(data),
+			}), nil
+		})
+	}
+	return &starlarkstruct.Module{
+		Name: "http",
+		Members: starlark.StringDict{
+			"get":  request("GET"),
+			"post": request("POST"),
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package script
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScriptJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "t" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"version": "1.2"}`))
+	}))
+	defer srv.Close()
+
+	source := strings.Replace(`
+resp = http.get("URL", headers={"X-Token": "t"})
+if resp.status != 200:
+    fail("status %d" % resp.status)
+log("ok", resp.status)
+`, "URL", srv.URL, 1)
+	job, err := newScriptJob("health", map[string]string{"source": source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := job.Run(); err != nil || msg != "ok 200" {
+		t.Errorf("Run() = %q, %v", msg, err)
+	}
+
+	job, _ = newScriptJob("version", map[string]string{"source": source + `result = json.decode(resp.body)["version"]`})
+	if msg, err := job.Run(); err != nil || msg != "1.2" {
+		t.Errorf("Run() = %q, %v", msg, err)
+	}
+
+	job, _ = newScriptJob("forbidden", map[string]string{"source": strings.Replace(source, `"t"`, `"x"`, 1)})
+	if _, err := job.Run(); err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("err = %v, want status 403", err)
+	}
+}
+
+func TestScriptJobRestrictions(t *testing.T) {
+	job, err := newScriptJob("loop", map[string]string{
+		"source":  "while True:\n    pass\n",
+		"timeout": "50ms",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := job.Run(); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want timeout", err)
+	}
+	if _, err := newScriptJob("undefined", map[string]string{"source": `open("/etc/passwd")`}); err == nil {
+		t.Error("expected undefined names to be rejected")
+	}
+	job, err = newScriptJob("load", map[string]string{"source": `load("x.star", "y")`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := job.Run(); err == nil {
+		t.Error("expected load to fail")
+	}
+}
@@ -0,0 +1,220 @@
+package cron
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SecretProvider resolves the secrets referenced in job parameters as
+// ${secret:name}. The references are resolved every time the job runs: the
+// entries, their configs and snapshots keep the references only, and the
+// secret values are masked in the results and history of the runs.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// WithSecrets resolves the secrets referenced in the parameters of described
+// jobs (see DescribedJob) with p. A job referencing secrets fails to run
+// without a provider.
+func WithSecrets(p SecretProvider) Option {
+	return func(c *Cron) {
+		c.secrets = p
+	}
+}
+
+// secretRef matches a reference to a secret in a job parameter.
+var secretRef = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// secretMask replaces the secret values in run results.
+const secretMask = "******"
+
+// holdSecrets replaces the secrets referenced in params by placeholders
+// unique to the run, before the templates in params are expanded, so that
+// only the references written in the parameters are resolved: a reference
+// in the text a template expands to, such as the output of a run, is kept
+// as it is. It returns the names of the secrets by placeholder.
+func holdSecrets(params map[string]string) (map[string]string, error) {
+	var (
+		held  map[string]string
+		nonce string
+	)
+	for k, v := range params {
+		if !secretRef.MatchString(v) {
+			continue
+		}
+		if held == nil {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				return nil, fmt.Errorf("Failed to hold secrets: %s", err)
+			}
+			held, nonce = make(map[string]string), hex.EncodeToString(b)
+		}
+		params[k] = secretRef.ReplaceAllStringFunc(v, func(ref string) string {
+			name := secretRef.FindStringSubmatch(ref)[1]
+			p := "${" + nonce + ":" + name + "}"
+			held[p] = name
+			return p
+		})
+	}
+	return held, nil
+}
+
+// resolveSecrets replaces the placeholders of the secrets held by
+// holdSecrets in params by their values, which it returns to be masked in
+// the results.
+func (c *Cron) resolveSecrets(ctx context.Context, id string, params map[string]string, held map[string]string) ([]string, error) {
+	if len(held) == 0 {
+		return nil, nil
+	}
+	if c.secrets == nil {
+		return nil, fmt.Errorf("Job %s references secrets but no SecretProvider is set", id)
+	}
+
+	placeholders := make([]string, 0, len(held))
+	for p := range held {
+		placeholders = append(placeholders, p)
+	}
+	sort.Strings(placeholders)
+	values := make(map[string]string, len(held))
+	pairs := make([]string, 0, 2*len(held))
+	for _, p := range placeholders {
+		name := held[p]
+		v, err := c.secrets.Secret(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to resolve secret %s: %s", name, err)
+		}
+		values[name] = v
+		pairs = append(pairs, p, v)
+	}
+	r := strings.NewReplacer(pairs...)
+	for k, v := range params {
+		params[k] = r.Replace(v)
+	}
+
+	secrets := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	// Mask the longest values first, in case one contains another.
+	sort.Slice(secrets, func(a, b int) bool { return len(secrets[a]) > len(secrets[b]) })
+	return secrets, nil
+}
+
+// maskSecrets replaces the secret values in the message and error of a run.
+func maskSecrets(secrets []string, msg string, err error) (string, error) {
+	if len(secrets) == 0 {
+		return msg, err
+	}
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, s := range secrets {
+		pairs = append(pairs, s, secretMask)
+	}
+	r := strings.NewReplacer(pairs...)
+	msg = r.Replace(msg)
+	if err != nil {
+		if masked := r.Replace(err.Error()); masked != err.Error() {
+			err = errors.New(masked)
+		}
+	}
+	return msg, err
+}
+
+// EnvSecrets resolves secrets from the environment variables named after
+// them, with Prefix.
+type EnvSecrets struct {
+	Prefix string
+}
+
+func (s EnvSecrets) Secret(ctx context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(s.Prefix + name)
+	if !ok {
+		return "", fmt.Errorf("%s%s is not set", s.Prefix, name)
+	}
+	return v, nil
+}
+
+// FileSecrets resolves secrets from the files named after them in Dir, such
+// as the secrets mounted in a container. A trailing newline is ignored.
+type FileSecrets struct {
+	Dir string
+}
+
+func (s FileSecrets) Secret(ctx context.Context, name string) (string, error) {
+	if name != filepath.Clean(name) || filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+		return "", fmt.Errorf("Invalid secret name %q", name)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// VaultSecrets resolves secrets from the KV version 2 secrets engine of
+// HashiCorp Vault. A secret name is the path of a Vault secret and the key
+// of its value, as "path#key"; the key defaults to "value".
+type VaultSecrets struct {
+	// Addr is the address of the Vault server, e.g. https://vault:8200.
+	Addr string
+	// Token authenticates the requests.
+	Token string
+	// Mount is the path of the secrets engine, "secret" by default.
+	Mount string
+	// Client sends the requests, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s VaultSecrets) Secret(ctx context.Context, name string) (string, error) {
+	path, key := name, "value"
+	if i := strings.LastIndexByte(name, '#'); i >= 0 {
+		path, key = name[:i], name[i+1:]
+	}
+	mount := s.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(s.Addr, "/")+"/v1/"+mount+"/data/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", s.Token)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vault returned %s for %s", resp.Status, path)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("Invalid Vault response for %s: %s", path, err)
+	}
+	v, ok := body.Data.Data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault secret %s has no %s string", path, key)
+	}
+	return v, nil
+}
@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWithSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	os.Setenv("CRON_SECRET_token", "s3cr3t")
+	defer os.Unsetenv("CRON_SECRET_token")
+
+	c := New(WithSecrets(EnvSecrets{Prefix: "CRON_SECRET_"}))
+	err := c.AddJobConfig(JobConfig{Name: "leak", Spec: "@yearly", Type: CommandJobType, Params: map[string]string{
+		commandParam: "echo token=${secret:token}; [ ${#TOKEN} = 6 ] && echo ok",
+		"env.TOKEN":  "${secret:token}",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := c.Entry("leak")
+	c.runWithRecovery(e.Job, nil, slots{}, nil)
+	runs := c.History("leak")
+	if len(runs) != 1 || runs[0].Msg != "token=******\nok\n" || runs[0].Error != "" {
+		t.Errorf("expected the secret to be resolved and masked, got %+v", runs)
+	}
+	if _, params := describe(e.Job); params["env.TOKEN"] != "${secret:token}" {
+		t.Errorf("expected the job to keep the reference, got %q", params["env.TOKEN"])
+	}
+
+	c.secrets = EnvSecrets{Prefix: "CRON_MISSING_"}
+	c.runWithRecovery(e.Job, nil, slots{}, nil)
+	if runs := c.History("leak"); !strings.Contains(runs[1].Error, "Failed to resolve secret token") {
+		t.Errorf("expected a resolution error, got %q", runs[1].Error)
+	}
+	c.secrets = nil
+	c.runWithRecovery(e.Job, nil, slots{}, nil)
+	if runs := c.History("leak"); !strings.Contains(runs[2].Error, "no SecretProvider") {
+		t.Errorf("expected a missing provider error, got %q", runs[2].Error)
+	}
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t\n"), 0600)
+
+	s := FileSecrets{Dir: dir}
+	if v, err := s.Secret(context.Background(), "token"); err != nil || v != "s3cr3t" {
+		t.Errorf("expected s3cr3t, got %q (%v)", v, err)
+	}
+	for _, name := range []string{"../token", "/etc/passwd", "a/../../b"} {
+		if _, err := s.Secret(context.Background(), name); err == nil {
+			t.Errorf("expected %q to be refused", name)
+		}
+	}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.URL.Path != "/v1/kv/data/db/prod" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"value": "default", "password": "hunter2"}}}`))
+	}))
+	defer srv.Close()
+
+	s := VaultSecrets{Addr: srv.URL, Token: "root", Mount: "kv"}
+	for name, want := range map[string]string{"db/prod": "default", "db/prod#password": "hunter2"} {
+		if v, err := s.Secret(context.Background(), name); err != nil || v != want {
+			t.Errorf("%s: expected %q, got %q (%v)", name, want, v, err)
+		}
+	}
+	if _, err := s.Secret(context.Background(), "db/prod#user"); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+	if _, err := s.Secret(context.Background(), "db/dev"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,510 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCommandOutput is the number of output bytes kept from a command run.
+const MaxCommandOutput = 64 << 10
+
+// ShellCommandJob runs a command through the shell and reports its combined
+// stdout and stderr as the result message. It is registered as the
+// CommandJobType job type.
+type ShellCommandJob struct {
+	id string
+
+	// Command is the shell command line.
+	Command string
+	// Args are passed to the command as positional parameters ($1, $2, ...).
+	Args []string
+	// Env holds "KEY=value" pairs added to the environment of the process.
+	Env []string
+	// EnvPolicy selects the variables of the environment of the scheduler
+	// the command inherits, all of them by default.
+	EnvPolicy EnvPolicy
+	// EnvAllow names the variables inherited with EnvAllowlist.
+	EnvAllow []string
+	// Dir is the working directory, or the current one if empty.
+	Dir string
+	// User runs the command as the given user name or uid instead of the
+	// user of the scheduler, which must be privileged to do so. HOME, USER
+	// and LOGNAME are set to those of the user, before Env.
+	User string
+	// Group is the group name or gid the command runs with, by default the
+	// primary group of User.
+	Group string
+	// Umask is the octal file mode creation mask of the command, e.g. "022",
+	// or empty to keep the one of the scheduler. It is set with the umask
+	// builtin, so it needs a POSIX shell.
+	Umask string
+	// Timeout kills the command if it runs longer, on the clock of the run,
+	// see RunClock. Zero means no timeout.
+	Timeout time.Duration
+	// GracePeriod is how long a command timed out or canceled is given to
+	// exit after SIGTERM before its process group is killed with SIGKILL.
+	// Zero kills it right away.
+	GracePeriod time.Duration
+	// Shell runs the command, defaulting to /bin/sh (cmd on Windows).
+	Shell string
+	// Stdin is written to the standard input of the command.
+	Stdin string
+	// MailTo receives the output of every run producing any, through
+	// CommandMailer.
+	MailTo []string
+	// CPULimit caps the CPU used by the command, in CPUs, e.g. 0.5 for half
+	// of one. Zero means no limit.
+	CPULimit float64
+	// MemoryLimit caps the memory used by the command in bytes, the kernel
+	// killing it when it needs more. Zero means no limit.
+	MemoryLimit int64
+}
+
+// EnvPolicy selects the environment variables of the scheduler a
+// ShellCommandJob inherits.
+type EnvPolicy string
+
+const (
+	// EnvInherit passes all the variables of the scheduler, then Env.
+	EnvInherit EnvPolicy = "inherit"
+	// EnvAllowlist passes the variables of the scheduler named in EnvAllow,
+	// then Env.
+	EnvAllowlist EnvPolicy = "allowlist"
+	// EnvExplicit passes Env only, like cron which runs commands with the
+	// variables set in the crontab and a few defaults.
+	EnvExplicit EnvPolicy = "explicit"
+)
+
+// CommandMailer sends the output of ShellCommandJobs to their MailTo
+// recipients, as cron does. Output is not mailed if it is nil.
+var CommandMailer *Mailer
+
+// CgroupRoot is the cgroup v2 directory in which a cgroup is created for
+// every run of a ShellCommandJob with a CPULimit or a MemoryLimit. The
+// process must be allowed to create cgroups in it, with the cpu and memory
+// controllers enabled. The limits are only supported on Linux; elsewhere a
+// command with limits fails to run.
+var CgroupRoot = "/sys/fs/cgroup"
+
+// NewShellCommandJob returns a job with the given id running the command.
+func NewShellCommandJob(id, command string, args ...string) *ShellCommandJob {
+	return &ShellCommandJob{id: id, Command: command, Args: args}
+}
+
+func init() {
+	RegisterJobType(CommandJobType, newShellCommandJob)
+}
+
+// newShellCommandJob builds a ShellCommandJob from the "command", "arg.N",
+// "env.KEY", "env_policy", "env_allow", "dir", "user", "group", "umask",
+// "timeout", "grace_period", "shell", "stdin", "mailto", "cpu_limit" and
+// "memory_limit" parameters.
+func newShellCommandJob(id string, params map[string]string) (Job, error) {
+	j := NewShellCommandJob(id, params[commandParam])
+	if j.Command == "" {
+		return nil, fmt.Errorf("Missing %s parameter", commandParam)
+	}
+	j.Dir = params["dir"]
+	j.User = params["user"]
+	j.Group = params["group"]
+	j.Umask = params["umask"]
+	j.EnvPolicy = EnvPolicy(params["env_policy"])
+	for _, name := range strings.Split(params["env_allow"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			j.EnvAllow = append(j.EnvAllow, name)
+		}
+	}
+	j.Shell = params["shell"]
+	j.Stdin = params["stdin"]
+	for _, to := range strings.Split(params["mailto"], ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			j.MailTo = append(j.MailTo, to)
+		}
+	}
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	if g := params["grace_period"]; g != "" {
+		grace, err := time.ParseDuration(g)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse grace_period %s: %s", g, err)
+		}
+		j.GracePeriod = grace
+	}
+	if l := params["cpu_limit"]; l != "" {
+		cpus, err := strconv.ParseFloat(l, 64)
+		if err != nil || cpus < 0 {
+			return nil, fmt.Errorf("Invalid cpu_limit %s", l)
+		}
+		j.CPULimit = cpus
+	}
+	if l := params["memory_limit"]; l != "" {
+		memory, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || memory < 0 {
+			return nil, fmt.Errorf("Invalid memory_limit %s", l)
+		}
+		j.MemoryLimit = memory
+	}
+	for i := 0; ; i++ {
+		arg, ok := params["arg."+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		j.Args = append(j.Args, arg)
+	}
+	var keys []string
+	for k := range params {
+		if strings.HasPrefix(k, envParam) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		j.Env = append(j.Env, k[len(envParam):]+"="+params[k])
+	}
+	if err := j.validate(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// validate checks the environment policy and the umask of the job.
+func (j *ShellCommandJob) validate() error {
+	switch j.EnvPolicy {
+	case "", EnvInherit, EnvAllowlist, EnvExplicit:
+	default:
+		return fmt.Errorf("Unknown environment policy %q", j.EnvPolicy)
+	}
+	if j.Umask != "" {
+		if _, err := strconv.ParseUint(j.Umask, 8, 32); err != nil {
+			return fmt.Errorf("Invalid umask %q", j.Umask)
+		}
+	}
+	return nil
+}
+
+func (j *ShellCommandJob) ID() string { return j.id }
+
+func (j *ShellCommandJob) JobType() string { return CommandJobType }
+
+func (j *ShellCommandJob) Params() map[string]string {
+	params := map[string]string{commandParam: j.Command}
+	for i, arg := range j.Args {
+		params["arg."+strconv.Itoa(i)] = arg
+	}
+	for _, kv := range j.Env {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			params[envParam+kv[:i]] = kv[i+1:]
+		}
+	}
+	if j.Dir != "" {
+		params["dir"] = j.Dir
+	}
+	if j.User != "" {
+		params["user"] = j.User
+	}
+	if j.Group != "" {
+		params["group"] = j.Group
+	}
+	if j.Umask != "" {
+		params["umask"] = j.Umask
+	}
+	if j.EnvPolicy != "" {
+		params["env_policy"] = string(j.EnvPolicy)
+	}
+	if len(j.EnvAllow) > 0 {
+		params["env_allow"] = strings.Join(j.EnvAllow, ",")
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	if j.GracePeriod > 0 {
+		params["grace_period"] = j.GracePeriod.String()
+	}
+	if j.Shell != "" {
+		params["shell"] = j.Shell
+	}
+	if j.Stdin != "" {
+		params["stdin"] = j.Stdin
+	}
+	if j.CPULimit > 0 {
+		params["cpu_limit"] = strconv.FormatFloat(j.CPULimit, 'g', -1, 64)
+	}
+	if j.MemoryLimit > 0 {
+		params["memory_limit"] = strconv.FormatInt(j.MemoryLimit, 10)
+	}
+	if len(j.MailTo) > 0 {
+		params["mailto"] = strings.Join(j.MailTo, ",")
+	}
+	return params
+}
+
+// Run runs the command, returning its output. A non-zero exit status or a
+// timeout is reported as an error.
+func (j *ShellCommandJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext runs the command like Run, killing it if ctx is done first. The
+// CPU time and memory used by the command are reported with ReportUsage.
+func (j *ShellCommandJob) RunContext(ctx context.Context) (msg string, err error) {
+	msg, err = j.run(ctx)
+	if msg != "" && len(j.MailTo) > 0 && CommandMailer != nil {
+		subject := fmt.Sprintf("Cron <%s> %s", j.id, j.Command)
+		if merr := CommandMailer.Send(j.MailTo, subject, msg); merr != nil && err == nil {
+			err = merr
+		}
+	}
+	return msg, err
+}
+
+func (j *ShellCommandJob) run(ctx context.Context) (msg string, err error) {
+	if err := j.validate(); err != nil {
+		return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+	}
+	cmd := exec.Command(j.shell()[0], j.shellArgs()...)
+	// Run the command in its own process group, so a timeout kills the
+	// processes it started as well.
+	setProcessGroup(cmd)
+	cmd.Dir = j.Dir
+	if j.Stdin != "" {
+		cmd.Stdin = strings.NewReader(j.Stdin)
+	}
+	var u *user.User
+	if j.User != "" {
+		if u, err = j.credential(cmd); err != nil {
+			return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+		}
+	}
+	cmd.Env = j.environ(u)
+	out := &limitedBuffer{max: MaxCommandOutput}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	var (
+		cg      *cgroup
+		release func()
+	)
+	if j.CPULimit > 0 || j.MemoryLimit > 0 {
+		if cg, err = newCgroup(j.CPULimit, j.MemoryLimit); err != nil {
+			return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+		}
+		defer cg.remove()
+		if release, err = cg.hold(cmd); err != nil {
+			return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		if release != nil {
+			release()
+		}
+		return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	// The command is held until it is moved into its cgroup, so the limits
+	// apply to every process it starts.
+	if cg != nil {
+		err := cg.add(cmd.Process.Pid)
+		if err != nil {
+			killProcessGroup(cmd)
+		}
+		release()
+		if err != nil {
+			<-done
+			return "", fmt.Errorf("Command %q failed: %s", j.Command, err)
+		}
+	}
+
+	clock := RunClock(ctx)
+	var timeout <-chan time.Time
+	if j.Timeout > 0 {
+		timer := clock.NewTimer(j.Timeout)
+		defer timer.Stop()
+		timeout = timer.C()
+	}
+	// The usage of the process is known once it has been waited for.
+	defer func() {
+		if cmd.ProcessState != nil {
+			ReportUsage(ctx, processUsage(cmd.ProcessState))
+		}
+	}()
+	select {
+	case err = <-done:
+	case <-timeout:
+		sig := j.terminate(clock, cmd, done)
+		return out.String(), &TerminationError{j.Command, fmt.Sprintf("timed out after %s", j.Timeout), sig}
+	case <-ctx.Done():
+		sig := j.terminate(clock, cmd, done)
+		return out.String(), &TerminationError{j.Command, fmt.Sprintf("canceled: %s", ctx.Err()), sig}
+	}
+
+	msg = out.String()
+	if err != nil && cg != nil && cg.oomKilled() {
+		return msg, fmt.Errorf("Command %q exceeded its memory limit of %d bytes", j.Command, j.MemoryLimit)
+	}
+	if err != nil {
+		return msg, fmt.Errorf("Command %q failed: %s", j.Command, err)
+	}
+	return msg, nil
+}
+
+// terminate ends the process group of the command, with SIGTERM and then
+// SIGKILL if it is still running after the grace period on clock, and waits
+// for the command. It returns the signal that ended the command.
+func (j *ShellCommandJob) terminate(clock Clock, cmd *exec.Cmd, done <-chan error) os.Signal {
+	if j.GracePeriod > 0 {
+		terminateProcessGroup(cmd)
+		timer := clock.NewTimer(j.GracePeriod)
+		defer timer.Stop()
+		select {
+		case <-done:
+			// Kill the processes that ignored SIGTERM, not to leave them
+			// behind.
+			killProcessGroup(cmd)
+			return terminateSignal
+		case <-timer.C():
+		}
+	}
+	killProcessGroup(cmd)
+	<-done
+	return os.Kill
+}
+
+// TerminationError is returned by a ShellCommandJob ended on a timeout or a
+// cancellation.
+type TerminationError struct {
+	// Command is the command that was ended.
+	Command string
+	// Cause tells why it was, e.g. "timed out after 1m0s".
+	Cause string
+	// Signal is the signal that ended the command: SIGTERM if it exited
+	// within its grace period, or SIGKILL.
+	Signal os.Signal
+}
+
+func (e *TerminationError) Error() string {
+	name := e.Signal.String()
+	switch e.Signal {
+	case os.Kill:
+		name = "SIGKILL"
+	case terminateSignal:
+		name = "SIGTERM"
+	}
+	return fmt.Sprintf("Command %q %s, ended by %s", e.Command, e.Cause, name)
+}
+
+func (j *ShellCommandJob) shell() []string {
+	switch {
+	case j.Shell != "":
+		return []string{j.Shell, "-c"}
+	case runtime.GOOS == "windows":
+		return []string{"cmd", "/C"}
+	}
+	return []string{"/bin/sh", "-c"}
+}
+
+// credential sets the command to run as User and Group, returning the user.
+func (j *ShellCommandJob) credential(cmd *exec.Cmd) (*user.User, error) {
+	u, err := user.Lookup(j.User)
+	if err != nil {
+		if u, err = user.LookupId(j.User); err != nil {
+			return nil, fmt.Errorf("Unknown user %s", j.User)
+		}
+	}
+	gid := u.Gid
+	if j.Group != "" {
+		g, err := user.LookupGroup(j.Group)
+		if err != nil {
+			if g, err = user.LookupGroupId(j.Group); err != nil {
+				return nil, fmt.Errorf("Unknown group %s", j.Group)
+			}
+		}
+		gid = g.Gid
+	}
+	return u, setCredential(cmd, u, gid)
+}
+
+// environ returns the environment of the command run as u if not nil, nil
+// meaning the one of the scheduler.
+func (j *ShellCommandJob) environ(u *user.User) []string {
+	// An empty environment, not a nil one which would be inherited.
+	env := make([]string, 0, len(j.Env))
+	switch j.EnvPolicy {
+	case EnvAllowlist:
+		for _, kv := range os.Environ() {
+			for _, name := range j.EnvAllow {
+				if strings.HasPrefix(kv, name+"=") {
+					env = append(env, kv)
+					break
+				}
+			}
+		}
+	case EnvExplicit:
+	default:
+		if len(j.Env) == 0 && u == nil {
+			return nil
+		}
+		env = os.Environ()
+	}
+	if u != nil {
+		env = append(env, "HOME="+u.HomeDir, "USER="+u.Username, "LOGNAME="+u.Username)
+	}
+	return append(env, j.Env...)
+}
+
+// shellArgs returns the arguments of the shell process.
+func (j *ShellCommandJob) shellArgs() []string {
+	shell := j.shell()
+	command := j.Command
+	if j.Umask != "" {
+		command = "umask " + j.Umask + "; " + command
+	}
+	args := append(shell[1:], command)
+	if len(j.Args) > 0 {
+		// The first positional parameter of "sh -c" is $0.
+		args = append(args, shell[0])
+		args = append(args, j.Args...)
+	}
+	return args
+}
+
+// limitedBuffer keeps the first max bytes written to it.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room <= 0 {
+			return n, nil
+		}
+		p = p[:room]
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[output truncated]"
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,175 @@
+package cron
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"reflect"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShellCommandJob(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	j := NewShellCommandJob("greet", `echo "$GREETING $1"; echo oops >&2`, "world")
+	j.Env = []string{"GREETING=hello"}
+	msg, err := j.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "hello world\noops\n" {
+		t.Errorf("unexpected output %q", msg)
+	}
+
+	if _, err := NewShellCommandJob("fail", "exit 3").Run(); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("expected exit status error, got %v", err)
+	}
+
+	slow := NewShellCommandJob("slow", "sleep 5")
+	slow.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	if _, err := slow.Run(); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("expected the command to be killed on timeout")
+	}
+}
+
+func TestShellCommandJobEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	os.Setenv("CRON_TEST_INHERITED", "yes")
+	os.Setenv("CRON_TEST_OTHER", "yes")
+	defer os.Unsetenv("CRON_TEST_INHERITED")
+	defer os.Unsetenv("CRON_TEST_OTHER")
+
+	for _, test := range []struct {
+		policy EnvPolicy
+		want   string
+	}{
+		{"", "yes yes set\n"},
+		{EnvAllowlist, "yes - set\n"},
+		{EnvExplicit, "- - set\n"},
+	} {
+		j := NewShellCommandJob("env", `echo ${CRON_TEST_INHERITED:--} ${CRON_TEST_OTHER:--} $SET`)
+		j.Env = []string{"SET=set"}
+		j.EnvPolicy = test.policy
+		j.EnvAllow = []string{"CRON_TEST_INHERITED"}
+		if msg, err := j.Run(); err != nil || msg != test.want {
+			t.Errorf("%q: expected %q, got %q (%v)", test.policy, test.want, msg, err)
+		}
+	}
+
+	j := NewShellCommandJob("umask", "umask")
+	j.Umask = "027"
+	if msg, err := j.Run(); err != nil || msg != "0027\n" {
+		t.Errorf("expected umask 0027, got %q (%v)", msg, err)
+	}
+	j.Umask = "022; echo injected"
+	if _, err := j.Run(); err == nil || !strings.Contains(err.Error(), "Invalid umask") {
+		t.Errorf("expected an invalid umask error, got %v", err)
+	}
+	if _, err := newJob(CommandJobType, "env", map[string]string{commandParam: "true", "env_policy": "none"}); err == nil {
+		t.Error("expected an unknown environment policy error")
+	}
+}
+
+func TestShellCommandJobUser(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	j := NewShellCommandJob("user", "id -u; echo $HOME")
+	j.User = "no such user"
+	if _, err := j.Run(); err == nil || !strings.Contains(err.Error(), "Unknown user") {
+		t.Errorf("expected an unknown user error, got %v", err)
+	}
+	if os.Getuid() != 0 {
+		t.Skip("requires root")
+	}
+	u, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("requires the nobody user")
+	}
+	j.User = "nobody"
+	if msg, err := j.Run(); err != nil || msg != u.Uid+"\n"+u.HomeDir+"\n" {
+		t.Errorf("expected to run as nobody, got %q (%v)", msg, err)
+	}
+}
+
+func TestShellCommandJobGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	j := NewShellCommandJob("polite", `trap 'echo bye; exit 0' TERM; sleep 5 & wait`)
+	j.Timeout = 50 * time.Millisecond
+	j.GracePeriod = 5 * time.Second
+	msg, err := j.Run()
+	var te *TerminationError
+	if !errors.As(err, &te) || te.Signal != syscall.SIGTERM {
+		t.Fatalf("expected the command to end on SIGTERM, got %v", err)
+	}
+	if msg != "bye\n" || !strings.Contains(err.Error(), "timed out after 50ms, ended by SIGTERM") {
+		t.Errorf("unexpected output %q and error %v", msg, err)
+	}
+
+	j = NewShellCommandJob("stubborn", `trap '' TERM; sleep 5`)
+	j.Timeout = 50 * time.Millisecond
+	j.GracePeriod = 100 * time.Millisecond
+	start := time.Now()
+	if _, err = j.Run(); !errors.As(err, &te) || te.Signal != syscall.SIGKILL {
+		t.Errorf("expected the command to be killed, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("expected the command to be killed after the grace period")
+	}
+}
+
+func TestShellCommandJobOutputLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	msg, err := NewShellCommandJob("big", "head -c 100000 /dev/zero").Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg) > MaxCommandOutput+100 || !strings.HasSuffix(msg, "[output truncated]") {
+		t.Errorf("expected truncated output, got %d bytes", len(msg))
+	}
+}
+
+func TestShellCommandJobParams(t *testing.T) {
+	j := &ShellCommandJob{
+		id:      "job",
+		Command: "run.sh",
+		Args:    []string{"a", "b"},
+		Env:     []string{"A=1", "B=2"},
+		Dir:     "/tmp",
+		Timeout: time.Minute,
+
+		EnvPolicy: EnvAllowlist,
+		EnvAllow:  []string{"HOME", "PATH"},
+		Umask:     "027",
+		User:      "nobody",
+		Group:     "nogroup",
+
+		GracePeriod: 10 * time.Second,
+		CPULimit:    0.5,
+		MemoryLimit: 64 << 20,
+	}
+	rebuilt, err := newJob(CommandJobType, "job", j.Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rebuilt, j) {
+		t.Errorf("(expected) %+v != %+v (actual)", j, rebuilt)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !windows
+// +build !windows
+
+package cron
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup makes the command the leader of a new process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateSignal asks a process to exit.
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// terminateProcessGroup sends SIGTERM to the process group led by the
+// command.
+func terminateProcessGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// killProcessGroup kills the process group led by the command.
+func killProcessGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// setCredential makes the command run as the user u, with the primary group
+// gid and the supplementary groups of u.
+func setCredential(cmd *exec.Cmd, u *user.User, gid string) error {
+	cred := &syscall.Credential{}
+	id, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return err
+	}
+	cred.Uid = uint32(id)
+	if id, err = strconv.ParseUint(gid, 10, 32); err != nil {
+		return err
+	}
+	cred.Gid = uint32(id)
+	groups, err := u.GroupIds()
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if id, err := strconv.ParseUint(g, 10, 32); err == nil {
+			cred.Groups = append(cred.Groups, uint32(id))
+		}
+	}
+	cmd.SysProcAttr.Credential = cred
+	return nil
+}
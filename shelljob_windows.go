@@ -0,0 +1,29 @@
+package cron
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/user"
+)
+
+// setProcessGroup is a no-op: Windows has no process groups to kill.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateSignal is os.Kill: Windows can not ask a process to exit.
+var terminateSignal = os.Kill
+
+// terminateProcessGroup kills the command process.
+func terminateProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// killProcessGroup kills the command process.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// setCredential fails: Windows has no setuid.
+func setCredential(cmd *exec.Cmd, u *user.User, gid string) error {
+	return errors.New("Running commands as another user is not supported on Windows")
+}
@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultDrainTimeout is how long NotifyShutdown waits for the running jobs,
+// unless the Cron was created with WithDrainTimeout.
+const DefaultDrainTimeout = 30 * time.Second
+
+// WithDrainTimeout sets how long NotifyShutdown waits for the running jobs
+// to finish.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(c *Cron) {
+		c.drainTimeout = d
+	}
+}
+
+// Drain stops the Cron, so that no more runs are started, and waits until
+// the jobs still running have finished. If ctx is done first, Drain returns
+// its error; the jobs keep running.
+func (c *Cron) Drain(ctx context.Context) error {
+	c.Stop()
+	return c.active.wait(ctx)
+}
+
+// NotifyShutdown drains c once one of the signals, SIGINT and SIGTERM by
+// default, is received, waiting for the running jobs up to the drain timeout.
+// The returned channel is closed when the jobs have finished or the timeout
+// expired, so that a main function can exit gracefully with
+//
+//	c.Start()
+//	<-cron.NotifyShutdown(c)
+//
+// A second signal stops waiting for the jobs.
+func NotifyShutdown(c *Cron, sig ...os.Signal) <-chan struct{} {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	timeout := c.drainTimeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, sig...)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer signal.Stop(ch)
+		s := <-ch
+		c.logf("cron: received %s, draining %d running jobs", s, c.active.count())
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		go func() {
+			select {
+			case <-ch:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if err := c.Drain(ctx); err != nil {
+			c.logf("cron: stopped waiting for %d running jobs: %v", c.active.count(), err)
+		}
+	}()
+	return done
+}
+
+// count returns the number of runs in progress.
+func (a *activeJobs) count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
+
+// wait waits until no job runs or ctx is done.
+func (a *activeJobs) wait(ctx context.Context) error {
+	a.mu.Lock()
+	if a.total <= 0 {
+		a.mu.Unlock()
+		return nil
+	}
+	if a.idle == nil {
+		a.idle = make(chan struct{})
+	}
+	idle := a.idle
+	a.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,57 @@
+package cron
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	c := New()
+	job := &preemptJob{"slow", make(chan struct{}, 1), make(chan struct{})}
+	c.AddJob("@yearly", job)
+	c.Start()
+	c.RunNow("slow")
+	<-job.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the drain to time out, got %v", err)
+	}
+	close(job.release)
+	if err := c.Drain(context.Background()); err != nil {
+		t.Errorf("expected the drain to succeed, got %v", err)
+	}
+	if n := c.active.count(); n != 0 {
+		t.Errorf("expected no running job, got %d", n)
+	}
+}
+
+func TestNotifyShutdown(t *testing.T) {
+	c := New(WithDrainTimeout(time.Second))
+	job := &preemptJob{"slow", make(chan struct{}, 1), make(chan struct{})}
+	c.AddJob("@yearly", job)
+	c.Start()
+	c.RunNow("slow")
+	<-job.started
+
+	done := NotifyShutdown(c, syscall.SIGHUP)
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("can not send SIGHUP:", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("expected the shutdown to wait for the running job")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(job.release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the shutdown to finish once the job is done")
+	}
+}
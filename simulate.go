@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"sort"
+	"time"
+)
+
+// Firing is a run of the job of an entry at a given time.
+type Firing struct {
+	Entry *Entry
+	Time  time.Time
+}
+
+// Simulate returns every run the scheduler would perform between from,
+// inclusive, and to, exclusive, ordered by time. Like the scheduler, it
+// leaves out the paused and disabled entries, the runs after an entry
+// expires or reaches its MaxRuns, and the runs in maintenance windows,
+// which are deferred to the end of the window for WindowDefer. Each Firing
+// refers to a snapshot of its entry.
+//
+// The result holds one Firing per run, so a long window over frequent
+// schedules can be large.
+func (c *Cron) Simulate(from, to time.Time) []Firing {
+	var (
+		entries []*Entry
+		windows []*window
+	)
+	c.do(func() {
+		for id, e := range c.entries {
+			if cp := c.copyEntry(id, e); !cp.Paused && !cp.Disabled {
+				entries = append(entries, cp)
+			}
+		}
+		windows = append(windows, c.windows...)
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	var firings []Firing
+	start := from.In(c.Location()).Add(-time.Nanosecond)
+	for _, e := range entries {
+		runs := e.Runs
+		for t := e.Schedule.Next(start); !t.IsZero() && t.Before(to); t = e.Schedule.Next(t) {
+			if e.expired(t) || e.MaxRuns > 0 && runs >= e.MaxRuns {
+				break
+			}
+			w, end := blackout(windows, e, t)
+			for w != nil && w.Policy == WindowDefer {
+				// Due again when the window ends.
+				t = end
+				w, end = blackout(windows, e, t)
+			}
+			if !t.Before(to) || e.expired(t) {
+				break
+			}
+			if w == nil {
+				firings = append(firings, Firing{Entry: e, Time: t})
+				runs++
+			}
+		}
+	}
+	sort.SliceStable(firings, func(i, j int) bool {
+		return firings[i].Time.Before(firings[j].Time)
+	})
+	return firings
+}
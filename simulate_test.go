@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulate(t *testing.T) {
+	c := NewWithLocation(time.UTC)
+	c.AddJob("0 0 */6 * * *", NewShellCommandJob("quarterly", "true"))
+	c.AddJob("0 30 9 * * *", NewShellCommandJob("morning", "true"))
+	c.AddJob("* * * * * *", NewShellCommandJob("paused", "true"))
+	c.Pause("paused")
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	firings := c.Simulate(from, from.Add(24*time.Hour))
+	var got []string
+	for _, f := range firings {
+		got = append(got, f.Entry.Job.ID()+"@"+f.Time.Format("15:04"))
+	}
+	want := []string{
+		"quarterly@00:00", "quarterly@06:00", "morning@09:30",
+		"quarterly@12:00", "quarterly@18:00",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestSimulateEligibility(t *testing.T) {
+	// A Sunday.
+	from := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+	c := NewWithLocation(time.UTC)
+	c.AddJob("0 */30 * * * *", NewShellCommandJob("db", "true"), WithTags("db"))
+	c.AddJob("0 0 * * * *", NewShellCommandJob("limited", "true"))
+	c.AddJob("0 0 * * * *", NewShellCommandJob("expiring", "true"))
+	c.AddJob("0 0 * * * *", NewShellCommandJob("disabled", "true"))
+	c.entries["limited"].MaxRuns = 2
+	c.entries["expiring"].Expires = from.Add(90 * time.Minute)
+	healthOf(c.entries["disabled"]).disabled = true
+	c.AddWindow(Window{Name: "all", Spec: "0 0 2 * * SUN", Duration: 2 * time.Hour})
+	c.AddWindow(Window{Name: "db", Spec: "0 0 5 * * SUN", Duration: 90 * time.Minute, Tags: []string{"db"}, Policy: WindowDefer})
+
+	got := make(map[string][]string)
+	for _, f := range c.Simulate(from, from.Add(7*time.Hour+time.Minute)) {
+		got[f.Entry.ID] = append(got[f.Entry.ID], f.Time.Format("1504"))
+	}
+	want := map[string]string{
+		"db":       "0000 0030 0100 0130 0400 0430 0630 0700",
+		"limited":  "0000 0100",
+		"expiring": "0000 0100",
+		"disabled": "",
+	}
+	for id, w := range want {
+		if g := strings.Join(got[id], " "); g != w {
+			t.Errorf("expected %s to run at %q, got %q", id, w, g)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// WithSLA sets the SLA of the entry: each run should be done within d of
+// the time it was due. The runs meeting and missing it are counted in the
+// stats of the entry, and the misses reported to the SLA handler.
+func WithSLA(d time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.SLA = d
+	}
+}
+
+// SLABreach describes a run missing the SLA of its entry.
+type SLABreach struct {
+	ID string `json:"id"`
+	// Scheduled is the time the run was due, and Deadline the time it
+	// should have been done by.
+	Scheduled time.Time `json:"scheduled"`
+	Deadline  time.Time `json:"deadline"`
+	// Finished is the time the run ended, or the zero time if the breach
+	// is reported while the run is still going.
+	Finished time.Time `json:"finished,omitempty"`
+}
+
+// WithSLAHandler calls f in its own goroutine for every run missing the SLA
+// of its entry, as soon as the deadline passes or, for a run started after
+// its deadline, once it finishes. The breach is also emitted as an
+// EventSLAMissed.
+func WithSLAHandler(f func(SLABreach)) Option {
+	return func(c *Cron) {
+		c.slaHandler = f
+	}
+}
+
+// slaWatch watches a run for its SLA.
+type slaWatch struct {
+	c      *Cron
+	breach SLABreach
+	once   sync.Once
+	done   chan struct{}
+}
+
+// watchSLA starts watching the run with the given id and template data, if
+// its entry has an SLA.
+func (c *Cron) watchSLA(id string, d *TemplateData) *slaWatch {
+	if d == nil || d.Deadline.IsZero() {
+		return nil
+	}
+	w := &slaWatch{
+		c:      c,
+		breach: SLABreach{ID: id, Scheduled: d.ScheduledTime, Deadline: d.Deadline},
+		done:   make(chan struct{}),
+	}
+	now := c.now()
+	if now.After(d.Deadline) {
+		// Late already: the breach is reported with the end of the run.
+		return w
+	}
+	timer := c.clock.NewTimer(d.Deadline.Sub(now))
+	go func() {
+		select {
+		case <-timer.C():
+			w.report(w.breach)
+		case <-w.done:
+			timer.Stop()
+		}
+	}()
+	return w
+}
+
+// finish records whether the run ending at end met its SLA.
+func (w *slaWatch) finish(end time.Time) {
+	if w == nil {
+		return
+	}
+	close(w.done)
+	met := !end.After(w.breach.Deadline)
+	w.c.history.recordSLA(w.breach.ID, met)
+	if !met {
+		b := w.breach
+		b.Finished = end
+		w.report(b)
+	}
+}
+
+// report reports the breach, once per run.
+func (w *slaWatch) report(b SLABreach) {
+	w.once.Do(func() {
+		c := w.c
+		c.emit(Event{Type: EventSLAMissed, EntryID: b.ID, Time: b.Deadline})
+		if f := c.slaHandler; f != nil {
+			if !c.pool.submit(func() { f(b) }) {
+				f(b)
+			}
+		}
+	})
+}
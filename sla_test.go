@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLA(t *testing.T) {
+	breaches := make(chan SLABreach, 10)
+	c := New(WithSLAHandler(func(b SLABreach) { breaches <- b }))
+	events, cancel := c.SubscribeEvents(100)
+	defer cancel()
+	sleep := 0 * time.Millisecond
+	id, _ := c.AddFunc("@yearly", func() (string, error) {
+		time.Sleep(sleep)
+		return "", nil
+	}, WithSLA(50*time.Millisecond))
+	e := c.entries[id]
+	run := func(due time.Time) {
+		c.runWithRecovery(e.Job, nil, slots{}, templateData(e, due))
+	}
+
+	run(time.Now())
+	select {
+	case b := <-breaches:
+		t.Errorf("expected the SLA to be met, got %+v", b)
+	default:
+	}
+
+	// Reported at the deadline, while the run is still going.
+	sleep = 300 * time.Millisecond
+	due := time.Now()
+	done := make(chan struct{})
+	go func() {
+		run(due)
+		close(done)
+	}()
+	select {
+	case b := <-breaches:
+		if !b.Finished.IsZero() || !b.Deadline.Equal(due.Add(50*time.Millisecond)) {
+			t.Errorf("expected a breach of the running job, got %+v", b)
+		}
+	case <-done:
+		t.Error("expected the breach before the end of the run")
+	}
+	<-done
+
+	// Reported at the end of a run started after its deadline.
+	sleep = 0
+	run(time.Now().Add(-time.Hour))
+	if b := <-breaches; b.Finished.IsZero() || b.ID != id {
+		t.Errorf("expected a breach of the finished run, got %+v", b)
+	}
+
+	if s := c.Stats(id); s.SLAMet != 1 || s.SLAMissed != 2 {
+		t.Errorf("expected 1 run meeting the SLA and 2 missing it, got %d and %d", s.SLAMet, s.SLAMissed)
+	}
+	missed := 0
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == EventSLAMissed {
+			missed++
+		}
+	}
+	if missed != 2 {
+		t.Errorf("expected 2 %s events, got %d", EventSLAMissed, missed)
+	}
+}
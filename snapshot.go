@@ -0,0 +1,211 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// snapshotVersion is the version of the Snapshot document format.
+const snapshotVersion = 1
+
+// Snapshot is a serializable document describing every entry of a Cron. It
+// can be encoded (e.g. as JSON), handed to another process and fed to
+// Restore to rebuild the scheduler there.
+type Snapshot struct {
+	Version int          `json:"version"`
+	Taken   time.Time    `json:"taken"`
+	Entries []EntryState `json:"entries"`
+}
+
+// EntryState is the serializable state of a single entry.
+type EntryState struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name,omitempty"`
+	Spec   string            `json:"spec"`
+	Type   string            `json:"type,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+	Prev   time.Time         `json:"prev"`
+	Next   time.Time         `json:"next"`
+	// Location is the name of the time zone of the entry, if it has one.
+	Location  string   `json:"location,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	Paused    bool     `json:"paused,omitempty"`
+	Runs      int      `json:"runs,omitempty"`
+	MaxRuns   int      `json:"max_runs,omitempty"`
+	// Expires is when the entry is removed, if it has a time to live.
+	Expires *time.Time `json:"expires,omitempty"`
+	// Disabled entries are restored disabled, with their quarantine
+	// record if they were quarantined.
+	Disabled   bool              `json:"disabled,omitempty"`
+	Quarantine *QuarantineRecord `json:"quarantine,omitempty"`
+}
+
+// Snapshot returns a document describing the current entries.
+//
+// Entries added with a Schedule other than a ConstantDelaySchedule or an
+// AlignedDelaySchedule have no spec, and jobs that do not implement
+// DescribedJob have no type; both are included in the document but can not
+// be restored.
+func (c *Cron) Snapshot() *Snapshot {
+	entries := c.Entries()
+	quarantined := make(map[string]QuarantineRecord)
+	for _, r := range c.Quarantined() {
+		quarantined[r.ID] = r
+	}
+	s := &Snapshot{
+		Version: snapshotVersion,
+		Taken:   c.now(),
+		Entries: make([]EntryState, 0, len(entries)),
+	}
+	for _, e := range entries {
+		typ, params := describe(e.Job)
+		state := EntryState{
+			ID:     e.Job.ID(),
+			Name:   e.Name,
+			Spec:   entrySpec(e),
+			Type:   typ,
+			Params: params,
+			Prev:   e.Prev,
+			Next:   e.Next,
+
+			Tags:      e.Tags,
+			Resources: e.Resources,
+			Namespace: e.Namespace,
+			Paused:    e.Paused,
+			Runs:      e.Runs,
+			MaxRuns:   e.MaxRuns,
+			Disabled:  e.Disabled,
+		}
+		if e.Location != nil {
+			state.Location = e.Location.String()
+		}
+		if !e.Expires.IsZero() {
+			expires := e.Expires
+			state.Expires = &expires
+		}
+		if r, ok := quarantined[e.ID]; ok {
+			state.Quarantine = &r
+		}
+		s.Entries = append(s.Entries, state)
+	}
+	return s
+}
+
+// Restore adds the entries described by the snapshot to the Cron, rebuilding
+// each job with the factory registered for its type. The last run time of
+// every entry is preserved, as well as whether it is paused or disabled and
+// how many runs it has left. Nothing is added if any entry can not be
+// restored, or if they do not all fit in the quota set by WithMaxEntries.
+func (c *Cron) Restore(s *Snapshot) (err error) {
+	entries, err := c.restoredEntries(s)
+	if err != nil {
+		return err
+	}
+	c.do(func() {
+		added := 0
+		for _, e := range entries {
+			if _, exists := c.entries[e.Job.ID()]; !exists {
+				added++
+			}
+		}
+		if c.maxEntries > 0 && len(c.entries)+added > c.maxEntries {
+			err = ErrQuotaExceeded
+			return
+		}
+		for _, e := range entries {
+			if err = c.insert(e.Job.ID(), e, true); err != nil {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		c.emit(Event{Type: EventEntryAdded, EntryID: e.Job.ID()})
+	}
+	return nil
+}
+
+// restoredEntries builds the entries described by the snapshot.
+func (c *Cron) restoredEntries(s *Snapshot) ([]*Entry, error) {
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version %d", s.Version)
+	}
+
+	entries := make([]*Entry, 0, len(s.Entries))
+	ids := make(map[string]bool, len(s.Entries))
+	for _, state := range s.Entries {
+		if ids[state.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateJob, state.ID)
+		}
+		ids[state.ID] = true
+		if state.Spec == "" {
+			return nil, fmt.Errorf("Entry %s has no spec", state.ID)
+		}
+		if state.Type == "" {
+			return nil, fmt.Errorf("Entry %s has no job type", state.ID)
+		}
+		schedule, err := c.parser.Parse(state.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("Entry %s: %s", state.ID, err)
+		}
+		job, err := newJob(state.Type, state.ID, state.Params)
+		if err != nil {
+			return nil, fmt.Errorf("Entry %s: %s", state.ID, err)
+		}
+		if state.Runs < 0 || state.MaxRuns < 0 {
+			return nil, fmt.Errorf("Entry %s: Negative runs (%d of %d) not allowed", state.ID, state.Runs, state.MaxRuns)
+		}
+		e := &Entry{
+			Schedule:  schedule,
+			Job:       job,
+			Spec:      state.Spec,
+			Name:      state.Name,
+			Prev:      state.Prev,
+			Tags:      state.Tags,
+			Resources: sortedResources(state.Resources),
+			Namespace: state.Namespace,
+			Paused:    state.Paused,
+			Runs:      state.Runs,
+			MaxRuns:   state.MaxRuns,
+		}
+		if state.Expires != nil {
+			e.Expires = *state.Expires
+		}
+		if state.Disabled || state.Quarantine != nil {
+			e.health = &entryHealth{disabled: true, quarantined: state.Quarantine}
+		}
+		if state.Location != "" {
+			if e.Location, err = time.LoadLocation(state.Location); err != nil {
+				return nil, fmt.Errorf("Entry %s: %s", state.ID, err)
+			}
+			e.locate()
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// entrySpec returns the spec of the entry, deriving it from the schedule
+// when possible.
+func entrySpec(e *Entry) string {
+	if e.Spec != "" {
+		return e.Spec
+	}
+	switch s := unlocated(e.Schedule).(type) {
+	case ConstantDelaySchedule:
+		return "@every " + s.Delay.String()
+	case AlignedDelaySchedule:
+		// Restored aligned with WithAlignedIntervals only.
+		return "@every " + s.Delay.String()
+	case AfterSchedule:
+		if !s.Always {
+			return s.String()
+		}
+	}
+	return ""
+}
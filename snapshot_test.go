@@ -0,0 +1,129 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testDescribedJob struct {
+	id   string
+	name string
+}
+
+func (j *testDescribedJob) ID() string                   { return j.id }
+func (j *testDescribedJob) Run() (msg string, err error) { return j.name, nil }
+func (j *testDescribedJob) JobType() string              { return "test" }
+func (j *testDescribedJob) Params() map[string]string    { return map[string]string{"name": j.name} }
+
+func init() {
+	RegisterJobType("test", func(id string, params map[string]string) (Job, error) {
+		return &testDescribedJob{id: id, name: params["name"]}, nil
+	})
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	prev := time.Date(2012, 7, 9, 14, 45, 0, 0, time.UTC)
+
+	src := New()
+	src.AddJob("0 30 * * * *", &testDescribedJob{"a", "first"})
+	src.Schedule(Every(5*time.Minute), &testDescribedJob{"b", "second"})
+	src.entries["a"].Prev = prev
+
+	data, err := json.Marshal(src.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc Snapshot
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := New()
+	if err := dst.Restore(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.entries) != 2 {
+		t.Fatalf("expected 2 restored entries, got %d", len(dst.entries))
+	}
+	a := dst.entries["a"]
+	if a.Spec != "0 30 * * * *" || !a.Prev.Equal(prev) {
+		t.Errorf("unexpected entry a: spec %q, prev %v", a.Spec, a.Prev)
+	}
+	if name := a.Job.(*testDescribedJob).name; name != "first" {
+		t.Errorf("expected job param first, got %s", name)
+	}
+	if b := dst.entries["b"]; b.Spec != "@every 5m0s" {
+		t.Errorf("expected derived @every spec, got %q", b.Spec)
+	}
+}
+
+func TestRestoreRejectsUnknownType(t *testing.T) {
+	c := New()
+	err := c.Restore(&Snapshot{
+		Version: snapshotVersion,
+		Entries: []EntryState{
+			{ID: "a", Spec: "@hourly", Type: "test", Params: map[string]string{"name": "a"}},
+			{ID: "b", Spec: "@hourly", Type: "missing"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown job type")
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("expected no entries after a failed restore, got %d", len(c.entries))
+	}
+}
+
+func TestSnapshotRestoreState(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := New()
+	src.AddJob("@hourly", &testDescribedJob{"a", "first"}, WithTags("db"), WithResources("disk"), WithNamespace("team"))
+	src.AddJob("@hourly", &testDescribedJob{"b", "second"})
+	src.Pause("a")
+	src.entries["a"].MaxRuns, src.entries["a"].Runs, src.entries["a"].Expires = 5, 2, expires
+	healthOf(src.entries["b"]).disabled = true
+	healthOf(src.entries["b"]).quarantined = &QuarantineRecord{ID: "b", Reason: "Panicked"}
+
+	data, err := json.Marshal(src.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc Snapshot
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	dst := New()
+	if err := dst.Restore(&doc); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := dst.Entry("a")
+	if !a.Paused || a.Tags[0] != "db" || a.Resources[0] != "disk" || a.Namespace != "team" || a.Runs != 2 || a.MaxRuns != 5 || !a.Expires.Equal(expires) {
+		t.Errorf("unexpected restored entry a %+v", a)
+	}
+	if b, _ := dst.Entry("b"); !b.Disabled {
+		t.Error("expected b to be restored disabled")
+	}
+	if q := dst.Quarantined(); len(q) != 1 || q[0].Reason != "Panicked" {
+		t.Errorf("expected the quarantine of b to be restored, got %v", q)
+	}
+}
+
+func TestRestoreQuota(t *testing.T) {
+	c := New(WithMaxEntries(2))
+	c.AddJob("@hourly", &testDescribedJob{"a", "first"})
+	err := c.Restore(&Snapshot{
+		Version: snapshotVersion,
+		Entries: []EntryState{
+			{ID: "b", Spec: "@hourly", Type: "test"},
+			{ID: "c", Spec: "@hourly", Type: "test"},
+		},
+	})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected the quota to be exceeded, got %v", err)
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("expected no entry to be restored, got %d entries", len(c.entries))
+	}
+}
@@ -1,11 +1,34 @@
 package cron
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // SpecSchedule specifies a duty cycle (to the second granularity), based on a
 // traditional crontab specification. It is computed initially and stored as bit sets.
 type SpecSchedule struct {
 	Second, Minute, Hour, Dom, Month, Dow uint64
+
+	// Location is the time zone the fields are interpreted in. If nil, the
+	// location of the time passed to Next is used.
+	Location *time.Location
+
+	// Week is the set of ISO weeks of the year, from 1 to 53, the schedule
+	// is restricted to. Zero means every week.
+	Week uint64
+
+	// Years are the years the schedule is restricted to, in increasing
+	// order. Nil means every year.
+	Years []int
+
+	// LastWeekday adds the last weekday, Monday to Friday, of the month to
+	// the days of the month, as the LW token does.
+	LastWeekday bool
+
+	// LastDow is the set of days of the week whose last occurrence in the
+	// month is added to the days of the week, as tokens such as 5L do.
+	LastDow uint64
 }
 
 // bounds provides a range of acceptable values (plus a map of name to value).
@@ -43,6 +66,8 @@ var (
 		"fri": 5,
 		"sat": 6,
 	}}
+	years = bounds{1970, 2099, nil}
+	weeks = bounds{1, 53, nil}
 )
 
 const (
@@ -61,20 +86,42 @@ func (s *SpecSchedule) Next(t time.Time) time.Time {
 	// of the field list (since it is necessary to re-verify previous field
 	// values)
 
+	// Convert to the schedule's time zone, and back when returning.
+	origLocation := t.Location()
+	if s.Location != nil {
+		t = t.In(s.Location)
+	}
+
 	// Start at the earliest possible time (the upcoming second).
 	t = t.Add(1*time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
 
 	// This flag indicates whether a field has been incremented.
 	added := false
 
-	// If no time is found within five years, return zero.
+	// If no time is found within five years, or the last year of the
+	// schedule, return zero.
 	yearLimit := t.Year() + 5
+	if n := len(s.Years); n > 0 && s.Years[n-1] > yearLimit {
+		yearLimit = s.Years[n-1]
+	}
 
 WRAP:
 	if t.Year() > yearLimit {
 		return time.Time{}
 	}
 
+	// Find the first applicable year.
+	if len(s.Years) > 0 {
+		i := sort.SearchInts(s.Years, t.Year())
+		if i == len(s.Years) {
+			return time.Time{}
+		}
+		if s.Years[i] != t.Year() {
+			added = true
+			t = time.Date(s.Years[i], time.January, 1, 0, 0, 0, 0, t.Location())
+		}
+	}
+
 	// Find the first applicable month.
 	// If it's this month, then do nothing.
 	for 1<<uint(t.Month())&s.Month == 0 {
@@ -141,18 +188,38 @@ WRAP:
 		}
 	}
 
-	return t
+	return t.In(origLocation)
 }
 
 // dayMatches returns true if the schedule's day-of-week and day-of-month
 // restrictions are satisfied by the given time.
 func dayMatches(s *SpecSchedule, t time.Time) bool {
+	if s.Week != 0 {
+		if _, week := t.ISOWeek(); 1<<uint(week)&s.Week == 0 {
+			return false
+		}
+	}
 	var (
-		domMatch bool = 1<<uint(t.Day())&s.Dom > 0
-		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0
+		domMatch bool = 1<<uint(t.Day())&s.Dom > 0 || s.LastWeekday && isLastWeekday(t)
+		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0 ||
+			1<<uint(t.Weekday())&s.LastDow > 0 && t.AddDate(0, 0, 7).Month() != t.Month()
 	)
 	if s.Dom&starBit > 0 || s.Dow&starBit > 0 {
 		return domMatch && dowMatch
 	}
 	return domMatch || dowMatch
 }
+
+// isLastWeekday reports whether t is the last day of its month falling on
+// Monday to Friday.
+func isLastWeekday(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	for d := t.AddDate(0, 0, 1); d.Month() == t.Month(); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultSpecCacheSize is the number of schedules kept by DefaultSpecCache.
+const DefaultSpecCacheSize = 1024
+
+// DefaultSpecCache caches the schedules parsed by Parse, and by a Cron not
+// given another parser.
+var DefaultSpecCache = NewSpecCache(defaultParser, DefaultSpecCacheSize)
+
+// SpecCache is a ScheduleParser remembering the schedules parsed by another
+// one, so that specs shared by many entries are parsed once. Specs differing
+// only by whitespace share a schedule. The schedules it returns are shared
+// and must not be modified.
+type SpecCache struct {
+	parser ScheduleParser
+	size   int
+
+	mu        sync.Mutex
+	schedules map[string]Schedule
+	stats     SpecCacheStats
+}
+
+// SpecCacheStats tells how well a SpecCache performs.
+type SpecCacheStats struct {
+	// Hits and Misses count the specs found in the cache or parsed.
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	// Evictions counts the times the cache was full and emptied.
+	Evictions uint64 `json:"evictions"`
+	// Size is the number of schedules in the cache.
+	Size int `json:"size"`
+}
+
+// NewSpecCache returns a SpecCache parsing specs with p and keeping up to
+// size schedules, or any number if size is not positive. The cache is
+// emptied when it is full.
+func NewSpecCache(p ScheduleParser, size int) *SpecCache {
+	return &SpecCache{
+		parser:    p,
+		size:      size,
+		schedules: make(map[string]Schedule),
+	}
+}
+
+// Parse returns the schedule of the spec, parsing it if it is not cached.
+// Invalid specs are not cached.
+func (c *SpecCache) Parse(spec string) (Schedule, error) {
+	key := strings.Join(strings.Fields(spec), " ")
+	c.mu.Lock()
+	schedule, ok := c.schedules[key]
+	if ok {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+	c.mu.Unlock()
+	if ok {
+		return schedule, nil
+	}
+
+	schedule, err := c.parser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size > 0 && len(c.schedules) >= c.size {
+		c.schedules = make(map[string]Schedule)
+		c.stats.Evictions++
+	}
+	c.schedules[key] = schedule
+	return schedule, nil
+}
+
+// Stats returns the statistics of the cache.
+func (c *SpecCache) Stats() SpecCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = len(c.schedules)
+	return stats
+}
+
+// purge empties the cache, e.g. once the time zones of the schedules are
+// reloaded.
+func (c *SpecCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedules = make(map[string]Schedule)
+}
@@ -0,0 +1,41 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecCache(t *testing.T) {
+	cache := NewSpecCache(defaultParser, 2)
+	a, err := cache.Parse("0 30 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cache.Parse("  0 30\t* * * * ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("expected specs differing by whitespace to share a schedule")
+	}
+	if _, err := cache.Parse("0 61 * * * *"); err == nil {
+		t.Error("expected an invalid spec to fail")
+	}
+	if _, err := cache.Parse("0 61 * * * *"); err == nil {
+		t.Error("expected an invalid spec to fail again")
+	}
+	want := SpecCacheStats{Hits: 1, Misses: 3, Size: 1}
+	if got := cache.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	cache.Parse("@hourly")
+	cache.Parse("@every 1m")
+	want = SpecCacheStats{Hits: 1, Misses: 5, Evictions: 1, Size: 1}
+	if got := cache.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if next := b.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)); next.Minute() != 30 {
+		t.Errorf("expected the cached schedule to run at minute 30, got %v", next)
+	}
+}
@@ -0,0 +1,112 @@
+package cron
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SQLJobType is the job type of SQLJob.
+const SQLJobType = "sql"
+
+// SQLJob executes a SQL statement and reports the number of affected rows.
+// The database/sql driver must be registered by the program, usually by
+// importing it for its side effects.
+type SQLJob struct {
+	id string
+
+	Driver string
+	// DSN is the data source name passed to sql.Open.
+	DSN       string
+	Statement string
+	Args      []interface{}
+	// Timeout cancels the statement if it runs longer. Zero means no
+	// timeout.
+	Timeout time.Duration
+	// DB is used instead of opening Driver and DSN on each run if set.
+	DB *sql.DB
+
+	// dsnEnv names the environment variable the DSN was read from.
+	dsnEnv string
+}
+
+// NewSQLJob returns a job with the given id executing statement on db.
+func NewSQLJob(id string, db *sql.DB, statement string, args ...interface{}) *SQLJob {
+	return &SQLJob{id: id, DB: db, Statement: statement, Args: args}
+}
+
+func init() {
+	RegisterJobType(SQLJobType, newSQLJob)
+}
+
+// newSQLJob builds a SQLJob from the "driver", "dsn", "dsn_env", "statement"
+// and "timeout" parameters. "dsn_env" names an environment variable holding
+// the DSN, which keeps credentials out of the job definition.
+func newSQLJob(id string, params map[string]string) (Job, error) {
+	j := &SQLJob{id: id, Driver: params["driver"], DSN: params["dsn"], Statement: params["statement"]}
+	if j.dsnEnv = params["dsn_env"]; j.dsnEnv != "" {
+		j.DSN = os.Getenv(j.dsnEnv)
+	}
+	if j.Driver == "" || j.Statement == "" {
+		return nil, fmt.Errorf("Missing driver or statement parameter")
+	}
+	if t := params["timeout"]; t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse timeout %s: %s", t, err)
+		}
+		j.Timeout = timeout
+	}
+	return j, nil
+}
+
+func (j *SQLJob) ID() string { return j.id }
+
+func (j *SQLJob) JobType() string { return SQLJobType }
+
+func (j *SQLJob) Params() map[string]string {
+	params := map[string]string{"driver": j.Driver, "statement": j.Statement}
+	if j.dsnEnv != "" {
+		params["dsn_env"] = j.dsnEnv
+	} else {
+		params["dsn"] = j.DSN
+	}
+	if j.Timeout > 0 {
+		params["timeout"] = j.Timeout.String()
+	}
+	return params
+}
+
+// Run executes the statement.
+func (j *SQLJob) Run() (msg string, err error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext executes the statement like Run, cancelling it if ctx is done
+// first.
+func (j *SQLJob) RunContext(ctx context.Context) (msg string, err error) {
+	db := j.DB
+	if db == nil {
+		if db, err = sql.Open(j.Driver, j.DSN); err != nil {
+			return "", fmt.Errorf("Failed to open %s database: %s", j.Driver, err)
+		}
+		defer db.Close()
+	}
+
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	res, err := db.ExecContext(ctx, j.Statement, j.Args...)
+	if err != nil {
+		return "", err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return "Statement executed", nil
+	}
+	return fmt.Sprintf("%d rows affected", rows), nil
+}
@@ -0,0 +1,90 @@
+package cron
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeSQLDriver records executed statements and affects one row per
+// argument.
+type fakeSQLDriver struct {
+	mu    sync.Mutex
+	execs []string
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	return &fakeSQLConn{d}, nil
+}
+
+type fakeSQLConn struct{ d *fakeSQLDriver }
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{c.d, query}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeSQLStmt struct {
+	d     *fakeSQLDriver
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error { return nil }
+
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.query == "FAIL" {
+		return nil, errors.New("syntax error")
+	}
+	s.d.mu.Lock()
+	s.d.execs = append(s.d.execs, s.query)
+	s.d.mu.Unlock()
+	return driver.RowsAffected(len(args) + 2), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+var testSQLDriver = &fakeSQLDriver{}
+
+func init() {
+	sql.Register("crontest", testSQLDriver)
+}
+
+func TestSQLJob(t *testing.T) {
+	job, err := newJob(SQLJobType, "cleanup", map[string]string{
+		"driver":    "crontest",
+		"dsn":       "mem",
+		"statement": "DELETE FROM sessions",
+		"timeout":   "1s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := job.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != "2 rows affected" {
+		t.Errorf("msg = %q", msg)
+	}
+
+	db, _ := sql.Open("crontest", "mem")
+	defer db.Close()
+	if msg, err := NewSQLJob("args", db, "UPDATE t", 1).Run(); err != nil || msg != "3 rows affected" {
+		t.Errorf("Run() = %q, %v", msg, err)
+	}
+	if _, err := NewSQLJob("fail", db, "FAIL").Run(); err == nil {
+		t.Error("expected the statement error to be returned")
+	}
+	if _, err := newJob(SQLJobType, "bad", map[string]string{"driver": "crontest"}); err == nil {
+		t.Error("expected a missing statement to be rejected")
+	}
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Store persists the entries of a Cron across restarts as a Snapshot.
+type Store interface {
+	// Load returns the saved snapshot, or nil if nothing was saved yet.
+	Load() (*Snapshot, error)
+	// Save replaces the saved snapshot.
+	Save(s *Snapshot) error
+}
+
+// FileStore is a Store keeping the snapshot as a JSON file at the given path.
+type FileStore string
+
+// Load reads the snapshot file, returning nil if it does not exist.
+func (f FileStore) Load() (*Snapshot, error) {
+	data, err := ioutil.ReadFile(string(f))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot to a temporary file renamed over the previous
+// one, so a crash never leaves a partial file behind.
+func (f FileStore) Save(s *Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(string(f)), filepath.Base(string(f))+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), string(f))
+}
+
+// loadStore restores the entries saved in the store of the Cron. It is
+// called while starting, with runMu held for writing.
+func (c *Cron) loadStore() {
+	if c.store == nil {
+		return
+	}
+	s, err := c.store.Load()
+	if err != nil || s == nil {
+		if err != nil {
+			c.logf("cron: failed to load entries from store: %s", err)
+		}
+		return
+	}
+	entries, err := c.restoredEntries(s)
+	if err != nil {
+		c.logf("cron: failed to restore entries from store: %s", err)
+		return
+	}
+	for _, e := range entries {
+		e.Version = 1
+		e.restored = true
+		c.entries[e.Job.ID()] = e
+		c.changed(ChangeAdded, e.Job.ID(), e)
+		c.emit(Event{Type: EventEntryAdded, EntryID: e.Job.ID()})
+	}
+}
+
+// loadStoredEntries loads the entries of the store, if any, for ReloadConfig.
+func (c *Cron) loadStoredEntries() ([]*Entry, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	s, err := c.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load entries from store: %s", err)
+	}
+	if s == nil {
+		return nil, nil
+	}
+	entries, err := c.restoredEntries(s)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to restore entries from store: %s", err)
+	}
+	return entries, nil
+}
+
+// saveStore saves the entries of the Cron to its store.
+func (c *Cron) saveStore() {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.Save(c.Snapshot()); err != nil {
+		c.logf("cron: failed to save entries to store: %s", err)
+	}
+}
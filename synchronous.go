@@ -0,0 +1,23 @@
+package cron
+
+// WithSynchronousExecution makes the Cron run every job, and the result
+// handler, in the run loop instead of in the background, so the runs happen
+// strictly one after the other in the order they are due. It suits very
+// short, ordering-sensitive jobs: while a job runs no other entry is started,
+// and adding, removing or listing entries waits, so a long job delays
+// everything else. The jobs must not call the methods of the Cron accessing
+// its entries, such as Entries or RunNow, which would wait forever.
+func WithSynchronousExecution() Option {
+	return func(c *Cron) {
+		c.inLoop = true
+	}
+}
+
+// WithSynchronous makes the scheduled runs of the entry happen in the run
+// loop, with the tradeoffs described for WithSynchronousExecution. Its runs
+// started by RunNow or Backfill still happen in the background.
+func WithSynchronous() EntryOption {
+	return func(e *Entry) {
+		e.Synchronous = true
+	}
+}
@@ -0,0 +1,48 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSynchronous(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    []Option
+		entries []EntryOption
+	}{
+		{"entry", nil, []EntryOption{WithSynchronous()}},
+		{"cron", []Option{WithSynchronousExecution()}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(tc.opts...)
+			// While a job runs in the loop, listing the entries waits.
+			blocked := make(chan bool, 1)
+			c.AddFunc("* * * * * *", func() (string, error) {
+				listed := make(chan struct{})
+				go func() {
+					c.Entries()
+					close(listed)
+				}()
+				select {
+				case <-listed:
+					blocked <- false
+				case <-time.After(50 * time.Millisecond):
+					blocked <- true
+				}
+				return "", nil
+			}, tc.entries...)
+			c.Start()
+			defer c.Stop()
+
+			select {
+			case b := <-blocked:
+				if !b {
+					t.Error("expected the job to run in the loop")
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("expected the job to run")
+			}
+		})
+	}
+}
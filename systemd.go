@@ -0,0 +1,224 @@
+package cron
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemdUnit is the pair of systemd unit files equivalent to an entry.
+type SystemdUnit struct {
+	// Name is the unit name without suffix; the files are Name.timer and
+	// Name.service.
+	Name    string
+	Timer   string
+	Service string
+}
+
+var systemdWeekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// SystemdUnits renders every CommandJobType entry as a systemd timer and
+// service, with unit names made of the prefix and the job id. The service
+// runs the command with its shell, arguments, environment, working
+// directory, user, group, umask and standard input. Entries with other job
+// types are skipped. It returns an error if the schedule of a
+// command entry can not be expressed as a timer.
+func (c *Cron) SystemdUnits(prefix string) ([]SystemdUnit, error) {
+	var units []SystemdUnit
+	for _, e := range c.Entries() {
+		typ, params := describe(e.Job)
+		if typ != CommandJobType {
+			continue
+		}
+		loc := c.Location()
+		if e.Location != nil {
+			loc = e.Location
+		}
+		triggers, err := systemdTriggers(unlocated(e.Schedule), loc)
+		if err != nil {
+			return nil, fmt.Errorf("Entry %s: %s", e.Job.ID(), err)
+		}
+
+		name := prefix + systemdUnitName(e.Job.ID())
+		description := e.Name
+		if description == "" {
+			description = e.Job.ID()
+		}
+
+		var timer strings.Builder
+		fmt.Fprintf(&timer, "[Unit]\nDescription=Timer for %s\n\n[Timer]\n", description)
+		for _, t := range triggers {
+			fmt.Fprintln(&timer, t)
+		}
+		fmt.Fprintf(&timer, "Unit=%s.service\n\n[Install]\nWantedBy=timers.target\n", name)
+
+		var service strings.Builder
+		fmt.Fprintf(&service, "[Unit]\nDescription=%s\n\n[Service]\nType=oneshot\n", description)
+		var keys []string
+		for k := range params {
+			if strings.HasPrefix(k, envParam) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&service, "Environment=%s\n", systemdQuote(k[len(envParam):]+"="+params[k]))
+		}
+		for _, setting := range []struct{ name, param string }{
+			{"WorkingDirectory", "dir"},
+			{"User", "user"},
+			{"Group", "group"},
+			{"UMask", "umask"},
+		} {
+			if v := params[setting.param]; v != "" {
+				fmt.Fprintf(&service, "%s=%s\n", setting.name, systemdEscape(v))
+			}
+		}
+		if stdin := params["stdin"]; stdin != "" {
+			fmt.Fprintf(&service, "StandardInput=data\nStandardInputData=%s\n", base64.StdEncoding.EncodeToString([]byte(stdin)))
+		}
+		shell := params["shell"]
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		fmt.Fprintf(&service, "ExecStart=%s -c %s", systemdEscape(shell), systemdQuoteExec(params[commandParam]))
+		for i := 0; ; i++ {
+			arg, ok := params["arg."+strconv.Itoa(i)]
+			if !ok {
+				break
+			}
+			if i == 0 {
+				// The first positional parameter of "sh -c" is $0.
+				fmt.Fprintf(&service, " %s", systemdQuoteExec(shell))
+			}
+			fmt.Fprintf(&service, " %s", systemdQuoteExec(arg))
+		}
+		fmt.Fprintln(&service)
+
+		units = append(units, SystemdUnit{name, timer.String(), service.String()})
+	}
+	return units, nil
+}
+
+// WriteSystemdUnits writes the unit files to dir.
+func WriteSystemdUnits(dir string, units []SystemdUnit) error {
+	for _, u := range units {
+		if err := ioutil.WriteFile(filepath.Join(dir, u.Name+".timer"), []byte(u.Timer), 0644); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, u.Name+".service"), []byte(u.Service), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// systemdTriggers returns the [Timer] trigger settings equivalent to the
+// schedule.
+func systemdTriggers(schedule Schedule, loc *time.Location) ([]string, error) {
+	switch s := schedule.(type) {
+	case ConstantDelaySchedule:
+		secs := strconv.FormatInt(int64(s.Delay/time.Second), 10)
+		return []string{"OnActiveSec=" + secs, "OnUnitActiveSec=" + secs}, nil
+	case *SpecSchedule:
+		if s.Week != 0 {
+			return nil, fmt.Errorf("Week of the year not supported by systemd")
+		}
+		if s.LastWeekday || s.LastDow != 0 {
+			return nil, fmt.Errorf("Last day of the month tokens not supported by systemd")
+		}
+		if s.Location != nil {
+			loc = s.Location
+		}
+		zone := ""
+		if loc != nil && loc != time.Local && loc.String() != "Local" {
+			zone = " " + loc.String()
+		}
+		year := "*"
+		if len(s.Years) > 0 {
+			list := make([]string, len(s.Years))
+			for i, y := range s.Years {
+				list[i] = strconv.Itoa(y)
+			}
+			year = strings.Join(list, ",")
+		}
+		days := func(dowBits, domBits uint64) string {
+			return fmt.Sprintf("OnCalendar=%s%s-%s-%s %s:%s:%s%s",
+				systemdDow(dowBits), year,
+				systemdField(s.Month, months), systemdField(domBits, dom),
+				systemdField(s.Hour, hours), systemdField(s.Minute, minutes), systemdField(s.Second, seconds),
+				zone)
+		}
+		// Cron matches either restricted day field, while systemd requires
+		// both to match; two calendar events express the union.
+		if s.Dom&starBit == 0 && s.Dow&starBit == 0 {
+			return []string{days(all(dow), s.Dom), days(s.Dow, all(dom))}, nil
+		}
+		return []string{days(s.Dow, s.Dom)}, nil
+	}
+	return nil, fmt.Errorf("Unsupported schedule type %T", schedule)
+}
+
+// systemdField renders the bits of a field as a systemd calendar component.
+func systemdField(bits uint64, r bounds) string {
+	if bits&starBit > 0 && bits&^starBit == getBits(r.min, r.max, 1) {
+		return "*"
+	}
+	var values []string
+	for i := r.min; i <= r.max; i++ {
+		if bits&(1<<i) > 0 {
+			values = append(values, fmt.Sprintf("%02d", i))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// systemdDow renders the day of week bits, including a trailing space, or
+// nothing for every day.
+func systemdDow(bits uint64) string {
+	if bits&^starBit == getBits(dow.min, dow.max, 1) {
+		return ""
+	}
+	var days []string
+	for i := dow.min; i <= dow.max; i++ {
+		if bits&(1<<i) > 0 {
+			days = append(days, systemdWeekdays[i])
+		}
+	}
+	return strings.Join(days, ",") + " "
+}
+
+// systemdUnitName replaces characters not allowed in unit names.
+func systemdUnitName(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == ':', r == '_', r == '.', r == '-':
+			return r
+		}
+		return '-'
+	}, id)
+}
+
+// systemdEscape escapes the specifiers of an unquoted setting value.
+func systemdEscape(s string) string {
+	return strings.Replace(s, "%", "%%", -1)
+}
+
+// systemdQuote quotes a value for use in a unit file assignment, escaping
+// specifiers.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s)
+	return `"` + s + `"`
+}
+
+// systemdQuoteExec quotes a command line argument, additionally escaping
+// variable expansion so the shell, not systemd, sees it.
+func systemdQuoteExec(s string) string {
+	return systemdQuote(strings.Replace(s, "$", "$$", -1))
+}
@@ -0,0 +1,118 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystemdTriggers(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected []string
+	}{
+		{"0 30 2 * * *", []string{"OnCalendar=*-*-* 02:30:00"}},
+		{"0 */15 * * * MON-FRI", []string{"OnCalendar=Mon,Tue,Wed,Thu,Fri *-*-* *:00,15,30,45:00"}},
+		{"@monthly", []string{"OnCalendar=*-*-01 00:00:00"}},
+		{"0 0 0 1 * SUN", []string{"OnCalendar=*-*-01 00:00:00", "OnCalendar=Sun *-*-* 00:00:00"}},
+		{"@every 1h30m", []string{"OnActiveSec=5400", "OnUnitActiveSec=5400"}},
+	}
+
+	for _, c := range tests {
+		schedule, err := Parse(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := systemdTriggers(schedule, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(actual, "\n") != strings.Join(c.expected, "\n") {
+			t.Errorf("%s: (expected) %q != %q (actual)", c.spec, c.expected, actual)
+		}
+	}
+}
+
+func TestSystemdUnits(t *testing.T) {
+	c := NewWithLocation(time.UTC)
+	c.addEntry(&Entry{
+		Schedule: &SpecSchedule{Second: 1, Minute: 1, Hour: 1 << 3, Dom: all(dom), Month: all(months), Dow: all(dow)},
+		Job: &ShellCommandJob{
+			id:      "nightly report",
+			Command: `report --out "$HOME/100%"`,
+			Env:     []string{"MODE=fast"},
+		},
+		Name: "Nightly report",
+	}, true)
+	c.AddFunc("@hourly", func() (string, error) { return "", nil })
+
+	units, err := c.SystemdUnits("cron-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 1 {
+		t.Fatalf("expected 1 unit, got %d", len(units))
+	}
+	u := units[0]
+	if u.Name != "cron-nightly-report" {
+		t.Errorf("unexpected unit name %q", u.Name)
+	}
+	for _, want := range []string{"OnCalendar=*-*-* 03:00:00 UTC\n", "Unit=cron-nightly-report.service\n"} {
+		if !strings.Contains(u.Timer, want) {
+			t.Errorf("expected timer to contain %q, got:\n%s", want, u.Timer)
+		}
+	}
+	for _, want := range []string{
+		"Description=Nightly report\n",
+		"Environment=\"MODE=fast\"\n",
+		`ExecStart=/bin/sh -c "report --out \"$$HOME/100%%\""` + "\n",
+	} {
+		if !strings.Contains(u.Service, want) {
+			t.Errorf("expected service to contain %q, got:\n%s", want, u.Service)
+		}
+	}
+}
+
+func TestSystemdUnitsService(t *testing.T) {
+	c := NewWithLocation(time.UTC)
+	c.AddJob("@hourly", &ShellCommandJob{
+		id:      "backup",
+		Command: `tar -C "$1" -cf - .`,
+		Args:    []string{"/srv/data"},
+		Dir:     "/var/backups",
+		User:    "backup",
+		Group:   "disk",
+		Umask:   "077",
+		Shell:   "/bin/bash",
+		Stdin:   "yes\n",
+	})
+	units, err := c.SystemdUnits("")
+	if err != nil || len(units) != 1 {
+		t.Fatalf("expected 1 unit, got %v, %v", units, err)
+	}
+	for _, want := range []string{
+		"WorkingDirectory=/var/backups\n",
+		"User=backup\n",
+		"Group=disk\n",
+		"UMask=077\n",
+		"StandardInput=data\nStandardInputData=eWVzCg==\n",
+		`ExecStart=/bin/bash -c "tar -C \"$$1\" -cf - ." "/bin/bash" "/srv/data"` + "\n",
+	} {
+		if !strings.Contains(units[0].Service, want) {
+			t.Errorf("expected service to contain %q, got:\n%s", want, units[0].Service)
+		}
+	}
+}
+
+func TestSystemdTriggersYearAndWeek(t *testing.T) {
+	p := NewParser(Second | Minute | Hour | Dom | Month | Dow | Year | Week)
+	schedule, _ := p.Parse("0 30 2 * * * 2025-2026")
+	actual, err := systemdTriggers(schedule, time.Local)
+	if err != nil || len(actual) != 1 || actual[0] != "OnCalendar=2025,2026-*-* 02:30:00" {
+		t.Errorf("expected the years in the calendar event, got %q, %v", actual, err)
+	}
+	schedule, _ = p.Parse("0 30 2 * * * * 1")
+	if _, err := systemdTriggers(schedule, time.Local); err == nil {
+		t.Error("expected an error for a week of the year")
+	}
+}
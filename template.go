@@ -0,0 +1,194 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is what the templates in job parameters are expanded with
+// when the job runs. A parameter containing "{{" is a text/template, e.g.
+//
+//	/report?date={{.ScheduledDate}}
+//	DELETE FROM events WHERE day < '{{(.ScheduledTime.AddDate 0 0 -7).Format "2006-01-02"}}'
+//
+// The output function returns the message of the last successful run of
+// another job, and outputJSON decodes it as JSON, so that a job can process
+// what another one produced:
+//
+//	{{index (outputJSON "extract") "rows"}}
+//
+// A run referencing a job that has not succeeded yet fails. ContextJobs get
+// the same output with UpstreamOutput. Like the secrets
+// they reference, the expanded parameters are only used by the run: the
+// entries keep the templates. Only the secrets referenced in the parameters
+// themselves are resolved, not those referenced in the text the templates
+// expand to, such as the output of a run.
+type TemplateData struct {
+	// ID, Name, Tags and Namespace are those of the entry.
+	ID        string
+	Name      string
+	Tags      []string
+	Namespace string
+	// ScheduledTime is the time the run was due at, in the location of the
+	// Cron, or the time it was started for RunNow.
+	ScheduledTime time.Time
+	// ScheduledDate is ScheduledTime as "2006-01-02".
+	ScheduledDate string
+	// IdempotencyKey is the idempotency key of the run, see
+	// IdempotencyKey.
+	IdempotencyKey string
+	// Deadline is the time the run should be done by, per the SLA of the
+	// entry, or the zero time.
+	Deadline time.Time
+	// Runs is the number of scheduled runs of the entry, including this one
+	// unless it was started by RunNow.
+	Runs int
+	// LastRun, LastMsg and LastError describe the previous run of the job,
+	// if any.
+	LastRun   time.Time
+	LastMsg   string
+	LastError string
+}
+
+// prepare returns the job to run for j: j itself if its parameters hold no
+// template nor secret reference, or a job built from the expanded
+// parameters. It also returns the secret values, to be masked in the results
+// of the run.
+func (c *Cron) prepare(ctx context.Context, j Job, d *TemplateData) (Job, []string, error) {
+	typ, described := describe(j)
+	if described == nil {
+		return j, nil, nil
+	}
+	// Params may return the map the job holds.
+	params := make(map[string]string, len(described))
+	for k, v := range described {
+		params[k] = v
+	}
+	// The secrets are held while the templates are expanded, so that no
+	// reference in the text the templates expand to is resolved.
+	held, err := holdSecrets(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	templated, err := c.expandTemplates(j.ID(), params, d)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := c.resolveSecrets(ctx, j.ID(), params, held)
+	if err != nil || !templated && len(held) == 0 {
+		return j, nil, err
+	}
+
+	if cj, ok := j.(*configuredJob); ok {
+		job, err := newJob(typ, cj.cfg.Name, params)
+		if err != nil {
+			return nil, secrets, err
+		}
+		return &configuredJob{job, cj.cfg, cj.timeout}, secrets, nil
+	}
+	job, err := newJob(typ, j.ID(), params)
+	return job, secrets, err
+}
+
+// templateData returns the template data of a run of e due at t.
+func templateData(e *Entry, t time.Time) *TemplateData {
+	d := &TemplateData{
+		ID:             e.ID,
+		Name:           e.Name,
+		Tags:           e.Tags,
+		Namespace:      e.Namespace,
+		ScheduledTime:  t,
+		ScheduledDate:  t.Format("2006-01-02"),
+		Runs:           e.Runs,
+		IdempotencyKey: IdempotencyKey(e.Job.ID(), t),
+	}
+	if e.SLA > 0 {
+		d.Deadline = t.Add(e.SLA)
+	}
+	return d
+}
+
+// expandTemplates expands the templates in params with d, completed with
+// the previous run of the job. It reports whether params held any template.
+func (c *Cron) expandTemplates(id string, params map[string]string, d *TemplateData) (bool, error) {
+	var (
+		data     TemplateData
+		expanded bool
+	)
+	for k, v := range params {
+		if !strings.Contains(v, "{{") {
+			continue
+		}
+		if !expanded {
+			data, expanded = c.completeTemplateData(id, d), true
+		}
+		tmpl, err := template.New(k).Option("missingkey=error").Funcs(template.FuncMap{
+			"output":     c.lastOutput,
+			"outputJSON": c.lastOutputJSON,
+		}).Parse(v)
+		if err != nil {
+			return true, err
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, data); err != nil {
+			return true, err
+		}
+		params[k] = b.String()
+	}
+	return expanded, nil
+}
+
+// completeTemplateData returns a copy of d, or of the data of a run started
+// now if nil, with the previous run of the job with the given id.
+func (c *Cron) completeTemplateData(id string, d *TemplateData) TemplateData {
+	var data TemplateData
+	if d != nil {
+		data = *d
+	} else {
+		now := c.now()
+		data = TemplateData{ScheduledTime: now, ScheduledDate: now.Format("2006-01-02")}
+		data.IdempotencyKey = IdempotencyKey(id, now)
+	}
+	data.ID = id
+	c.history.mu.Lock()
+	if runs, ok := c.history.runs[id]; ok && len(runs.buf) > 0 {
+		last := runs.buf[(runs.next+len(runs.buf)-1)%len(runs.buf)]
+		data.LastRun, data.LastMsg, data.LastError = last.Start, last.Msg, last.Error
+	}
+	c.history.mu.Unlock()
+	return data
+}
+
+// lastOutput returns the message of the last successful run of the job with
+// the given id.
+func (c *Cron) lastOutput(id string) (string, error) {
+	c.history.mu.Lock()
+	defer c.history.mu.Unlock()
+	if runs, ok := c.history.runs[id]; ok {
+		for i := len(runs.buf) - 1; i >= 0; i-- {
+			if r := runs.buf[(runs.next+i)%len(runs.buf)]; r.Error == "" {
+				return r.Msg, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Job %s has no successful run", id)
+}
+
+// lastOutputJSON returns the message of the last successful run of the job
+// with the given id, decoded as JSON.
+func (c *Cron) lastOutputJSON(id string) (interface{}, error) {
+	msg, err := c.lastOutput(id)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(msg), &v); err != nil {
+		return nil, fmt.Errorf("Output of job %s is not JSON: %s", id, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,84 @@
+package cron
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplatedParams(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddJob("0 0 0 * * *", &testDescribedJob{"report", "{{.ID}} {{.ScheduledDate}} #{{.Runs}} after {{printf \"%q\" .LastMsg}}"})
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(36 * time.Hour)
+	runs := c.History("report")
+	want := []string{
+		`report 2020-01-02 #1 after ""`,
+		`report 2020-01-03 #2 after "report 2020-01-02 #1 after \"\""`,
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("expected %d runs, got %+v", len(want), runs)
+	}
+	for i, run := range runs {
+		if run.Msg != want[i] {
+			t.Errorf("run %d: expected %s, got %s", i, want[i], run.Msg)
+		}
+	}
+	if e, _ := c.Entry("report"); e.Job.(*testDescribedJob).name[:5] != "{{.ID" {
+		t.Errorf("expected the entry to keep the template, got %q", e.Job.(*testDescribedJob).name)
+	}
+}
+
+func TestTemplatedParamsError(t *testing.T) {
+	c := New()
+	c.AddJob("@yearly", &testDescribedJob{"bad", "{{.Missing}}"})
+	c.runWithRecovery(c.entries["bad"].Job, nil, slots{}, nil)
+	if runs := c.History("bad"); len(runs) != 1 || !strings.Contains(runs[0].Error, "Missing") {
+		t.Errorf("expected a template error, got %+v", runs)
+	}
+}
+
+func TestTemplatedSecretRefs(t *testing.T) {
+	os.Setenv("CRON_SECRET_x", "hunter2")
+	defer os.Unsetenv("CRON_SECRET_x")
+	c := New(WithSecrets(EnvSecrets{Prefix: "CRON_SECRET_"}))
+	c.AddJob("@yearly", &testDescribedJob{"echo", "{{.LastMsg}} ${secret:x}"})
+	// Only the reference of the parameter is resolved, not the one the
+	// previous run printed.
+	c.history.record("echo", time.Now(), time.Now(), 0, "${secret:x}", nil, Usage{})
+	c.runWithRecovery(c.entries["echo"].Job, nil, slots{}, nil)
+	if runs := c.History("echo"); runs[1].Msg != "${secret:x} ******" {
+		t.Errorf("expected the output to be kept as it is, got %+v", runs[1])
+	}
+
+	// Nor the one the upstream job printed.
+	c.history.record("extract", time.Now(), time.Now(), 0, `"${secret:x}"`, nil, Usage{})
+	c.AddJob("@yearly", &testDescribedJob{"process", `{{output "extract"}} {{outputJSON "extract"}}`})
+	c.runWithRecovery(c.entries["process"].Job, nil, slots{}, nil)
+	if runs := c.History("process"); runs[0].Msg != `"${secret:x}" ${secret:x}` {
+		t.Errorf("expected the output to be kept as it is, got %+v", runs[0])
+	}
+}
+
+func TestTemplatedOutput(t *testing.T) {
+	c := New()
+	c.AddJob("@yearly", &testDescribedJob{"extract", `{"rows": 3}`})
+	c.AddJob("@yearly", &testDescribedJob{"process", `{{index (outputJSON "extract") "rows"}} rows from {{output "extract"}}`})
+	run := func(id string) RunRecord {
+		c.runWithRecovery(c.entries[id].Job, nil, slots{}, nil)
+		runs := c.History(id)
+		return runs[len(runs)-1]
+	}
+
+	if r := run("process"); !strings.Contains(r.Error, "Job extract has no successful run") {
+		t.Errorf("expected the run to fail without input, got %+v", r)
+	}
+	run("extract")
+	if r := run("process"); r.Msg != `3 rows from {"rows": 3}` {
+		t.Errorf("expected the output of extract, got %+v", r)
+	}
+}
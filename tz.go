@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ZoneSource returns the tzdata of the time zone with the given name, in the
+// format of the files of the IANA time zone database.
+type ZoneSource func(name string) ([]byte, error)
+
+// ZoneDir returns a ZoneSource reading the zone files under dir, such as
+// /usr/share/zoneinfo.
+func ZoneDir(dir string) ZoneSource {
+	return func(name string) ([]byte, error) {
+		if strings.Contains(name, "..") {
+			return nil, fmt.Errorf("Invalid time zone %s", name)
+		}
+		return ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	}
+}
+
+// SystemZones reads the zone files of the system, under the directory set by
+// the ZONEINFO environment variable or /usr/share/zoneinfo.
+func SystemZones(name string) ([]byte, error) {
+	dir := os.Getenv("ZONEINFO")
+	if dir == "" {
+		dir = "/usr/share/zoneinfo"
+	}
+	return ZoneDir(dir)(name)
+}
+
+// ReloadTimeZones reloads every time zone in use by the Cron from src, or
+// from SystemZones if it is nil, and recomputes the next run of the entries,
+// so that changes to the rules of a zone, such as new DST dates, apply
+// without restarting the process. This covers the time zone of the Cron, the
+// ones given to WithEntryLocation and the CRON_TZ prefixes of the specs.
+// time.Local is reloaded from the zone named by the TZ environment variable,
+// or else by the /etc/localtime link. The spec cache of the Cron, if it uses
+// one, is emptied so that the entries added later load their zones again.
+// The zones that can not be reloaded keep their previous rules, and the
+// first error is returned.
+func (c *Cron) ReloadTimeZones(src ZoneSource) error {
+	if src == nil {
+		src = SystemZones
+	}
+	var names []string
+	seen := make(map[string]bool)
+	add := func(loc *time.Location) {
+		if loc == nil || loc == time.UTC || seen[loc.String()] {
+			return
+		}
+		seen[loc.String()] = true
+		names = append(names, loc.String())
+	}
+	c.do(func() {
+		add(c.Location())
+		for _, e := range c.entries {
+			add(e.Location)
+			if ss, ok := unlocated(e.Schedule).(*SpecSchedule); ok {
+				add(ss.Location)
+			}
+		}
+	})
+
+	var firstErr error
+	zones := make(map[string]*time.Location, len(names))
+	for _, name := range names {
+		zone := name
+		if name == "Local" {
+			if zone = localZone(); zone == "" {
+				continue
+			}
+		}
+		data, err := src(zone)
+		if err == nil {
+			zones[name], err = time.LoadLocationFromTZData(name, data)
+		}
+		if err != nil {
+			c.logf("cron: failed to reload time zone %s: %s", name, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("Failed to reload time zone %s: %w", name, err)
+			}
+		}
+	}
+
+	if sc, ok := c.parser.(*SpecCache); ok {
+		sc.purge()
+	}
+
+	reload := func(loc *time.Location) *time.Location {
+		if loc != nil && zones[loc.String()] != nil {
+			return zones[loc.String()]
+		}
+		return loc
+	}
+	c.do(func() {
+		c.locationMu.Lock()
+		c.location = reload(c.location)
+		c.locationMu.Unlock()
+		for _, e := range c.entries {
+			e.Location = reload(e.Location)
+			if ss, ok := unlocated(e.Schedule).(*SpecSchedule); ok && ss.Location != nil {
+				// The schedule may be shared through the spec cache.
+				reloaded := *ss
+				reloaded.Location = reload(ss.Location)
+				e.Schedule = &reloaded
+			}
+			e.locate()
+		}
+		if !c.running {
+			return
+		}
+		now := c.now()
+		for _, e := range c.queue {
+			e.Next = nextRun(e, now)
+		}
+		c.requeueAll()
+	})
+	return firstErr
+}
+
+// localZone returns the name of the zone of time.Local, from the TZ
+// environment variable or the /etc/localtime link, or "" if it is unknown
+// or UTC.
+func localZone() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		return strings.TrimPrefix(tz, ":")
+	}
+	target, err := os.Readlink("/etc/localtime")
+	if err != nil {
+		return ""
+	}
+	if i := strings.LastIndex(target, "zoneinfo/"); i >= 0 {
+		return target[i+len("zoneinfo/"):]
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloadTimeZones(t *testing.T) {
+	tokyo, err := SystemZones("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	ny, err := SystemZones("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "Test"), 0755)
+	zone := filepath.Join(dir, "Test", "Zone")
+	if err := ioutil.WriteFile(zone, tokyo, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := time.LoadLocationFromTZData("Test/Zone", tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := NewFakeClock(time.Date(2020, 1, 6, 1, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddJob("0 0 9 * * *", &testDescribedJob{"job", "x"}, WithEntryLocation(loc))
+	c.Start()
+	defer c.Stop()
+	if e, _ := c.Entry("job"); !e.Next.Equal(time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the next run at 9:00 in Tokyo, got %v", e.Next)
+	}
+
+	// The rules of the zone change.
+	if err := ioutil.WriteFile(zone, ny, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReloadTimeZones(ZoneDir(dir)); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := c.Entry("job"); !e.Next.Equal(time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the next run at 9:00 in New York, got %v", e.Next)
+	}
+
+	os.Remove(zone)
+	if err := c.ReloadTimeZones(ZoneDir(dir)); err == nil {
+		t.Error("expected an error for a missing zone")
+	}
+	if e, _ := c.Entry("job"); !e.Next.Equal(time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the rules kept, got %v", e.Next)
+	}
+}
+
+func TestReloadTimeZonesSpec(t *testing.T) {
+	if _, err := SystemZones("Asia/Tokyo"); err != nil {
+		t.Skip(err)
+	}
+	c := New(WithLocation(time.UTC))
+	c.AddJob("CRON_TZ=Asia/Tokyo 0 0 9 * * *", &testDescribedJob{"job", "x"})
+	before := c.entries["job"].Schedule.(*SpecSchedule)
+	if err := c.ReloadTimeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	after := c.entries["job"].Schedule.(*SpecSchedule)
+	if after == before || after.Location == before.Location || after.Location.String() != "Asia/Tokyo" {
+		t.Errorf("expected a reloaded copy of the schedule, got %+v", after)
+	}
+	if before.Location.String() != "Asia/Tokyo" {
+		t.Errorf("expected the cached schedule untouched, got %v", before.Location)
+	}
+}
+
+func TestReloadTimeZonesLocal(t *testing.T) {
+	tokyo, err := SystemZones("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "Test"), 0755)
+	if err := ioutil.WriteFile(filepath.Join(dir, "Test", "Local"), tokyo, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		defer os.Setenv("TZ", tz)
+	} else {
+		defer os.Unsetenv("TZ")
+	}
+	os.Setenv("TZ", "Test/Local")
+
+	c := New(WithLocation(time.Local))
+	c.AddJob("@hourly", &testDescribedJob{"job", "x"})
+	DefaultSpecCache.Parse("@hourly")
+	if err := c.ReloadTimeZones(ZoneDir(dir)); err != nil {
+		t.Fatal(err)
+	}
+	loc := c.Location()
+	if loc == time.Local || loc.String() != "Local" {
+		t.Fatalf("expected time.Local to be reloaded, got %v", loc)
+	}
+	if _, offset := time.Date(2020, 1, 6, 0, 0, 0, 0, loc).Zone(); offset != 9*60*60 {
+		t.Errorf("expected the rules of Tokyo, got offset %d", offset)
+	}
+	if size := DefaultSpecCache.Stats().Size; size != 0 {
+		t.Errorf("expected the spec cache to be emptied, got %d schedules", size)
+	}
+}
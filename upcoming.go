@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+)
+
+// upcomingFeed fans the next runs of the entries out to subscribers.
+type upcomingFeed struct {
+	mu   sync.Mutex
+	subs map[chan Firing]struct{}
+	// announced is the next run last sent of each entry, while there are
+	// subscribers.
+	announced map[string]time.Time
+
+	// stale holds the ids of the entries whose next run may have changed
+	// since the last announce, and all tells whether every entry's may
+	// have. They are owned by the run loop.
+	stale map[string]bool
+	all   bool
+}
+
+// touch records that the next run of the entry with the given id may have
+// changed.
+func (f *upcomingFeed) touch(id string) {
+	if f.stale == nil {
+		f.stale = make(map[string]bool)
+	}
+	f.stale[id] = true
+}
+
+// requeue moves e in the queue of the run loop once its next run changed.
+// It must be called in the run loop.
+func (c *Cron) requeue(e *Entry) {
+	heap.Fix(&c.queue, e.index)
+	c.upcoming.touch(e.Job.ID())
+}
+
+// requeueAll orders the queue of the run loop again once the next run of
+// every entry changed. It must be called in the run loop.
+func (c *Cron) requeueAll() {
+	heap.Init(&c.queue)
+	c.upcoming.all = true
+}
+
+// SubscribeUpcoming returns a channel receiving the next run of the entries
+// each time it is computed, e.g. to prepare for a heavy job right before it
+// is due, until cancel is called. The next run of every entry is received
+// first, in time order, if the scheduler is running. Paused and disabled
+// entries are left out until they may run again. Like events, firings are
+// dropped for subscribers that fall more than buffer firings behind.
+func (c *Cron) SubscribeUpcoming(buffer int) (firings <-chan Firing, cancel func()) {
+	f := &c.upcoming
+	var ch chan Firing
+	c.do(func() {
+		var next []Firing
+		if c.running {
+			c.announce()
+			next = c.upcomingFirings(nil, nil)
+		}
+		ch = make(chan Firing, buffer+len(next))
+		for _, firing := range next {
+			ch <- firing
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.subs == nil {
+			f.subs = make(map[chan Firing]struct{})
+		}
+		f.subs[ch] = struct{}{}
+		if f.announced == nil {
+			f.announced = make(map[string]time.Time, len(next))
+			for _, firing := range next {
+				f.announced[firing.Entry.ID] = firing.Time
+			}
+		}
+	})
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			delete(f.subs, ch)
+			if len(f.subs) == 0 {
+				f.announced = nil
+			}
+			f.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// upcomingFirings returns the next runs of the entries with the given ids,
+// or of all of them if ids is nil, that may run, in time order, leaving out
+// the ones already announced. It must be called in the run loop.
+func (c *Cron) upcomingFirings(announced map[string]time.Time, ids map[string]bool) []Firing {
+	var firings []Firing
+	add := func(id string, e *Entry) {
+		if e.Next.IsZero() || e.Paused || announced[id].Equal(e.Next) {
+			return
+		}
+		cp := c.copyEntry(id, e)
+		if cp.Disabled {
+			return
+		}
+		firings = append(firings, Firing{Entry: cp, Time: e.Next})
+	}
+	if ids == nil {
+		for id, e := range c.entries {
+			add(id, e)
+		}
+	}
+	for id := range ids {
+		if e, ok := c.entries[id]; ok {
+			add(id, e)
+		}
+	}
+	sort.Slice(firings, func(i, j int) bool {
+		if !firings[i].Time.Equal(firings[j].Time) {
+			return firings[i].Time.Before(firings[j].Time)
+		}
+		return firings[i].Entry.ID < firings[j].Entry.ID
+	})
+	return firings
+}
+
+// announce sends the next runs computed since it was last called to the
+// subscribers. Only the entries whose next run may have changed meanwhile
+// are looked at, unless the next run of every entry was computed again. It
+// must be called in the run loop.
+func (c *Cron) announce() {
+	f := &c.upcoming
+	stale, all := f.stale, f.all
+	f.stale, f.all = nil, false
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.subs) == 0 || !all && len(stale) == 0 {
+		return
+	}
+	if all {
+		stale = nil
+		for id := range f.announced {
+			if _, ok := c.entries[id]; !ok {
+				delete(f.announced, id)
+			}
+		}
+	}
+	for id := range stale {
+		if _, ok := c.entries[id]; !ok {
+			delete(f.announced, id)
+		}
+	}
+	for _, firing := range c.upcomingFirings(f.announced, stale) {
+		f.announced[firing.Entry.ID] = firing.Time
+		for ch := range f.subs {
+			select {
+			case ch <- firing:
+			default:
+			}
+		}
+	}
+}
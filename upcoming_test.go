@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeUpcoming(t *testing.T) {
+	start := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.AddJob("@hourly", &chainJob{id: "hourly"})
+	c.AddJob("@daily", &chainJob{id: "daily"})
+	c.AddJob("@weekly", &chainJob{id: "weekly"})
+	c.Pause("weekly")
+	c.Start()
+	defer c.Stop()
+
+	firings, cancel := c.SubscribeUpcoming(10)
+	defer cancel()
+	next := func() Firing {
+		t.Helper()
+		select {
+		case f := <-firings:
+			return f
+		case <-time.After(time.Second):
+			t.Fatal("expected a firing")
+			return Firing{}
+		}
+	}
+	expect := func(id string, at time.Time) {
+		t.Helper()
+		if f := next(); f.Entry.ID != id || !f.Time.Equal(at) {
+			t.Errorf("expected %s at %v, got %s at %v", id, at, f.Entry.ID, f.Time)
+		}
+	}
+	expect("hourly", start.Add(time.Hour))
+	expect("daily", start.Add(24*time.Hour))
+
+	clock.Advance(time.Hour)
+	expect("hourly", start.Add(2*time.Hour))
+
+	c.Resume("weekly")
+	expect("weekly", time.Date(2020, 1, 12, 0, 0, 0, 0, time.UTC))
+
+	c.RemoveJob("daily")
+	c.AddJob("@daily", &chainJob{id: "daily"})
+	expect("daily", start.Add(24*time.Hour))
+
+	select {
+	case f := <-firings:
+		t.Errorf("unexpected firing of %s at %v", f.Entry.ID, f.Time)
+	default:
+	}
+}
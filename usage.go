@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Usage is the resources used by a run of a job.
+type Usage struct {
+	// CPUTime is the user and system CPU time used by the run.
+	CPUTime time.Duration `json:"cpu_time"`
+	// Memory is the peak memory used by the run in bytes: the maximum
+	// resident set size of the processes it started, or the growth of the
+	// heap for a job running in process.
+	Memory int64 `json:"memory"`
+	// Precise is true when the usage was measured for the run alone, e.g.
+	// by a job running a subprocess, rather than for the whole process
+	// while the run lasted.
+	Precise bool `json:"precise"`
+}
+
+// WithUsageAccounting measures the resources used by the runs of jobs that
+// do not report their own usage with ReportUsage. The measure is best
+// effort: it is the CPU time of the whole process and the growth of its heap
+// during the run, which includes the work of the jobs running at the same
+// time. Reading the heap briefly stops the world, so this is off by default;
+// jobs reporting their own usage, such as ShellCommandJob, are measured
+// regardless.
+func WithUsageAccounting() Option {
+	return func(c *Cron) {
+		c.accounting = true
+	}
+}
+
+type usageKey struct{}
+
+// ReportUsage adds u to the usage of the run ctx was given to, e.g. by a job
+// starting a subprocess. The CPU times of successive reports add up, and the
+// run keeps the highest memory.
+func ReportUsage(ctx context.Context, u Usage) {
+	if m, ok := ctx.Value(usageKey{}).(*usageMeter); ok {
+		m.add(u)
+	}
+}
+
+// usageMeter collects the usage of a run.
+type usageMeter struct {
+	mu       sync.Mutex
+	usage    Usage
+	reported bool
+
+	// The CPU time and heap of the process when the run started, with
+	// WithUsageAccounting.
+	cpu  time.Duration
+	heap int64
+}
+
+// meter returns a meter for a run starting now and the context to run the
+// job with.
+func (c *Cron) meter(ctx context.Context) (*usageMeter, context.Context) {
+	m := &usageMeter{}
+	if c.accounting {
+		m.cpu, m.heap = processCPUTime(), heapInUse()
+	}
+	return m, context.WithValue(ctx, usageKey{}, m)
+}
+
+func (m *usageMeter) add(u Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.CPUTime += u.CPUTime
+	if u.Memory > m.usage.Memory {
+		m.usage.Memory = u.Memory
+	}
+	m.usage.Precise = true
+	m.reported = true
+}
+
+// stop returns the usage of the run once it has finished.
+func (m *usageMeter) stop(accounting bool) Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.reported || !accounting {
+		return m.usage
+	}
+	u := Usage{CPUTime: processCPUTime() - m.cpu}
+	if grown := heapInUse() - m.heap; grown > 0 {
+		u.Memory = grown
+	}
+	return u
+}
+
+func heapInUse() int64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return int64(ms.HeapInuse)
+}
+
+// processUsage returns the usage of a process that has exited.
+func processUsage(ps *os.ProcessState) Usage {
+	return Usage{
+		CPUTime: ps.UserTime() + ps.SystemTime(),
+		Memory:  maxRSS(ps),
+		Precise: true,
+	}
+}
@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReportUsage(t *testing.T) {
+	ReportUsage(context.Background(), Usage{CPUTime: time.Second})
+
+	c := New()
+	m, ctx := c.meter(context.Background())
+	ReportUsage(ctx, Usage{CPUTime: time.Second, Memory: 1 << 20})
+	ReportUsage(ctx, Usage{CPUTime: 2 * time.Second, Memory: 1 << 10})
+	want := Usage{CPUTime: 3 * time.Second, Memory: 1 << 20, Precise: true}
+	if u := m.stop(true); u != want {
+		t.Errorf("expected usage %+v, got %+v", want, u)
+	}
+
+	m, _ = c.meter(context.Background())
+	if u := m.stop(false); u != (Usage{}) {
+		t.Errorf("expected no usage without accounting, got %+v", u)
+	}
+}
+
+func TestWithUsageAccounting(t *testing.T) {
+	c := New(WithUsageAccounting())
+	results := make(chan *JobResult, 1)
+	c.AddResultHandler(func(r *JobResult) { results <- r })
+	var keep [][]byte
+	j := newFuncJob(func() (string, error) {
+		for i := 0; i < 64; i++ {
+			keep = append(keep, make([]byte, 64<<10))
+		}
+		deadline := time.Now().Add(20 * time.Millisecond)
+		for time.Now().Before(deadline) {
+		}
+		return "", nil
+	})
+	c.runWithRecovery(j, nil, slots{}, nil)
+	runtime.KeepAlive(keep)
+
+	s := c.Stats(j.ID())
+	if s.LastUsage.Precise {
+		t.Error("expected a best effort usage")
+	}
+	if s.LastUsage.Memory <= 0 {
+		t.Errorf("expected the heap growth, got %d", s.LastUsage.Memory)
+	}
+	if runtime.GOOS != "windows" && s.CPUTime <= 0 {
+		t.Errorf("expected the CPU time, got %s", s.CPUTime)
+	}
+	if r := <-results; r.Usage != s.LastUsage {
+		t.Errorf("expected the usage in the result, got %+v", r.Usage)
+	}
+}
+
+func TestShellCommandJobUsage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	c := New()
+	j := NewShellCommandJob("count", "i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done")
+	c.runWithRecovery(j, nil, slots{}, nil)
+	s := c.Stats("count")
+	if !s.LastUsage.Precise || s.LastUsage.Memory <= 0 || s.CPUTime <= 0 {
+		t.Errorf("expected the usage of the command, got %+v", s.LastUsage)
+	}
+}
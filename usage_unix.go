@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package cron
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the CPU time used by the process so far.
+func processCPUTime() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
+
+// maxRSS returns the maximum resident set size of an exited process in
+// bytes.
+func maxRSS(ps *os.ProcessState) int64 {
+	ru, ok := ps.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	if runtime.GOOS == "darwin" {
+		// Darwin reports bytes, the other systems kilobytes.
+		return int64(ru.Maxrss)
+	}
+	return int64(ru.Maxrss) << 10
+}
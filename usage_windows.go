@@ -0,0 +1,12 @@
+package cron
+
+import (
+	"os"
+	"time"
+)
+
+// processCPUTime is not measured on Windows.
+func processCPUTime() time.Duration { return 0 }
+
+// maxRSS is not measured on Windows.
+func maxRSS(ps *os.ProcessState) int64 { return 0 }
@@ -0,0 +1,122 @@
+package cron
+
+import "fmt"
+
+// JobDefinition is the definition of an entry swapped by ReplaceJob.
+type JobDefinition struct {
+	// Spec is parsed into the schedule if Schedule is nil.
+	Spec     string
+	Schedule Schedule
+	// Job must have the id of the entry it replaces the job of; a FuncJob
+	// is given it.
+	Job Job
+	// Wrappers decorate Job, the first one being the outermost.
+	Wrappers []JobWrapper
+	// Options configure the entry, e.g. WithTags.
+	Options []EntryOption
+	// Revision, if not zero, is the revision the entry is expected to be
+	// at: the replacement fails with ErrConflict if the entry was added,
+	// replaced or rolled back since.
+	Revision uint64
+}
+
+// ReplaceJob replaces the definition of the entry with the given id,
+// between two firings, and returns its new version. The entry keeps its
+// history and run state, and the previous definition is kept for
+// RollbackJob. The runs in progress complete with the previous job.
+func (c *Cron) ReplaceJob(id string, def JobDefinition) (version int, err error) {
+	schedule := def.Schedule
+	if schedule == nil {
+		if schedule, err = c.parser.Parse(def.Spec); err != nil {
+			return 0, err
+		}
+	}
+	job := def.Job
+	if f, ok := job.(FuncJob); ok {
+		job = &funcJob{id: id, f: f}
+	} else if job.ID() != id {
+		return 0, fmt.Errorf("Job %s can not replace job %s", job.ID(), id)
+	}
+	for i := len(def.Wrappers) - 1; i >= 0; i-- {
+		job = def.Wrappers[i](job)
+	}
+	next := &Entry{Schedule: schedule, Job: job, Spec: def.Spec}
+	for _, opt := range def.Options {
+		opt(next)
+	}
+	return c.replace(id, next, def.Revision)
+}
+
+// UpdateJob replaces the definition of the entry named by the job config
+// like ReplaceJob, and returns its new version. If revision is not zero, it
+// fails with ErrConflict unless the entry is at this revision.
+func (c *Cron) UpdateJob(jc JobConfig, revision uint64) (version int, err error) {
+	next, err := jc.entry(c.parser)
+	if err != nil {
+		return 0, err
+	}
+	return c.replace(jc.Name, next, revision)
+}
+
+// replace replaces the definition of the entry with the given id by next,
+// if it is at the given revision or revision is zero.
+func (c *Cron) replace(id string, next *Entry, revision uint64) (version int, err error) {
+	if next.ttl > 0 {
+		next.Expires = c.now().Add(next.ttl)
+	}
+
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		if revision != 0 && e.Revision != revision {
+			err = fmt.Errorf("%w: %s is at revision %d, not %d", ErrConflict, id, e.Revision, revision)
+			return
+		}
+		carry(e, next)
+		err = c.insert(id, next, true)
+		version = next.Version
+	})
+	if err == nil {
+		c.emit(Event{Type: EventEntryReplaced, EntryID: id, Msg: fmt.Sprintf("Version %d", version)})
+	}
+	return version, err
+}
+
+// RollbackJob restores the previous definition of the entry with the given
+// id, and returns its version. The definition rolled back from becomes the
+// previous one in turn. ErrNoPreviousVersion is returned if the entry was
+// never replaced.
+func (c *Cron) RollbackJob(id string) (version int, err error) {
+	c.do(func() {
+		e, ok := c.entries[id]
+		if !ok {
+			err = jobNotFound(id)
+			return
+		}
+		prev := e.previous
+		if prev == nil {
+			err = fmt.Errorf("%w: %s", ErrNoPreviousVersion, id)
+			return
+		}
+		carry(e, prev)
+		e.previous = nil
+		prev.previous = e
+		err = c.insert(id, prev, true)
+		version = prev.Version
+	})
+	if err == nil {
+		c.emit(Event{Type: EventEntryReplaced, EntryID: id, Msg: fmt.Sprintf("Version %d", version)})
+	}
+	return version, err
+}
+
+// carry moves the run state of an entry to the definition replacing it.
+func carry(from, to *Entry) {
+	to.Prev, to.Runs, to.Paused = from.Prev, from.Runs, from.Paused
+	if to.health == nil {
+		to.health = from.health
+	}
+}
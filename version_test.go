@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplaceJob(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	var ran []string
+	job := func(name string) FuncJob {
+		return func() (string, error) {
+			ran = append(ran, name)
+			return name, nil
+		}
+	}
+	id, _ := c.AddFunc("0 * * * * *", job("v1"))
+	c.Start()
+	defer c.Stop()
+
+	if _, err := c.RollbackJob(id); !errors.Is(err, ErrNoPreviousVersion) {
+		t.Errorf("expected ErrNoPreviousVersion, got %v", err)
+	}
+	clock.Advance(time.Minute)
+	v, err := c.ReplaceJob(id, JobDefinition{Spec: "0 */2 * * * *", Job: job("v2"), Options: []EntryOption{WithTags("new")}})
+	if err != nil || v != 2 {
+		t.Fatalf("expected version 2, got %d (%v)", v, err)
+	}
+	clock.Advance(4 * time.Minute)
+	e, _ := c.Entry(id)
+	if e.Version != 2 || e.Runs != 3 || e.Spec != "0 */2 * * * *" || len(e.Tags) != 1 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	if v, err := c.RollbackJob(id); err != nil || v != 1 {
+		t.Fatalf("expected version 1, got %d (%v)", v, err)
+	}
+	clock.Advance(time.Minute)
+	if v, _ := c.RollbackJob(id); v != 2 {
+		t.Errorf("expected to roll forward to version 2, got %d", v)
+	}
+	clock.Advance(2 * time.Minute)
+
+	if got, want := ran, []string{"v1", "v2", "v2", "v1", "v2"}; !equalStrings(got, want) {
+		t.Errorf("expected runs %v, got %v", want, got)
+	}
+	if runs := c.History(id); len(runs) != 5 {
+		t.Errorf("expected the history to be kept, got %d runs", len(runs))
+	}
+
+	if _, err := c.ReplaceJob(id, JobDefinition{Spec: "@daily", Job: &versionJob{id: "other"}}); err == nil {
+		t.Error("expected a job with another id to be refused")
+	}
+	if _, err := c.ReplaceJob("missing", JobDefinition{Spec: "@daily", Job: job("x")}); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestReplaceJobConflict(t *testing.T) {
+	c := New()
+	c.AddJobConfig(JobConfig{Name: "a", Spec: "@hourly", Type: "test"})
+	e, _ := c.Entry("a")
+	added := e.Revision
+	if added == 0 {
+		t.Fatal("expected the entry to have a revision")
+	}
+
+	if _, err := c.UpdateJob(JobConfig{Name: "a", Spec: "@daily", Type: "test"}, added); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateJob(JobConfig{Name: "a", Spec: "@weekly", Type: "test"}, added); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected a stale update to conflict, got %v", err)
+	}
+	if _, err := c.RollbackJob("a"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := c.Entry("a"); e.Version != 1 || e.Revision == added {
+		t.Errorf("expected version 1 at a new revision, got version %d at %d", e.Version, e.Revision)
+	}
+	def := JobDefinition{Spec: "@weekly", Job: &testDescribedJob{id: "a"}, Revision: added}
+	if _, err := c.ReplaceJob("a", def); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected an update of the rolled back entry to conflict, got %v", err)
+	}
+	def.Revision = c.Revision()
+	if v, err := c.ReplaceJob("a", def); err != nil || v != 2 {
+		t.Errorf("expected version 2, got %d (%v)", v, err)
+	}
+	if _, err := c.UpdateJob(JobConfig{Name: "a", Spec: "@weekly", Type: "test"}, 0); err != nil {
+		t.Errorf("expected an unconditional update, got %v", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultWatchHistory is the number of entry changes kept for the watchers
+// to resume from, unless set by WithWatchHistory.
+const DefaultWatchHistory = 1024
+
+// WithWatchHistory keeps the last n entry changes, so that a watcher can
+// resume from a revision up to n changes old, see Watch.
+func WithWatchHistory(n int) Option {
+	return func(c *Cron) {
+		c.changes.limit = n
+	}
+}
+
+// ChangeType is the kind of an EntryChange.
+type ChangeType string
+
+const (
+	// ChangeAdded is the change of an entry added.
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved is the change of an entry removed, or completed.
+	ChangeRemoved ChangeType = "removed"
+	// ChangeUpdated is the change of an entry replaced by a new definition,
+	// or rolled back.
+	ChangeUpdated ChangeType = "updated"
+	// ChangeStatus is the change of an entry paused, resumed, disabled or
+	// enabled. The runs of the entry are not reported as changes.
+	ChangeStatus ChangeType = "status_changed"
+)
+
+// EntryChange describes a change of the entries of a Cron, for Watch.
+type EntryChange struct {
+	// Revision numbers the change among the changes of the Cron, from 1.
+	Revision uint64     `json:"revision"`
+	Type     ChangeType `json:"type"`
+	ID       string     `json:"id"`
+	Time     time.Time  `json:"time"`
+	// Status is the status of the entry after the change, for every change
+	// but ChangeRemoved.
+	Status EntryStatus `json:"status,omitempty"`
+	// Entry is a copy of the entry after the change, for ChangeAdded and
+	// ChangeUpdated.
+	Entry *Entry `json:"entry,omitempty"`
+}
+
+// changeLog numbers the entry changes and keeps the last ones for the
+// watchers.
+type changeLog struct {
+	mu       sync.Mutex
+	limit    int
+	revision uint64
+	recent   []EntryChange
+	// changed is closed on the next change, for the watchers waiting for
+	// it.
+	changed chan struct{}
+}
+
+// record numbers the change, adds it to the log and returns its revision.
+// The entry of the change is given its revision.
+func (l *changeLog) record(change EntryChange) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit := l.limit
+	if limit <= 0 {
+		limit = DefaultWatchHistory
+	}
+	l.revision++
+	change.Revision = l.revision
+	if change.Entry != nil {
+		change.Entry.Revision = l.revision
+	}
+	if len(l.recent) >= limit {
+		l.recent = l.recent[len(l.recent)-limit+1:]
+	}
+	l.recent = append(l.recent, change)
+	if l.changed != nil {
+		close(l.changed)
+		l.changed = nil
+	}
+	return l.revision
+}
+
+// since returns the changes after the given revision, and a channel closed
+// on the next change if there is none. It fails if the changes after the
+// revision are not kept.
+func (l *changeLog) since(revision uint64) ([]EntryChange, <-chan struct{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case revision > l.revision:
+		return nil, nil, fmt.Errorf("%w: %d is ahead of %d", ErrRevisionUnavailable, revision, l.revision)
+	case revision == l.revision:
+		if l.changed == nil {
+			l.changed = make(chan struct{})
+		}
+		return nil, l.changed, nil
+	}
+	first := l.revision - uint64(len(l.recent)) + 1
+	if revision+1 < first {
+		return nil, nil, fmt.Errorf("%w: %d is older than the %d kept changes", ErrRevisionUnavailable, revision, len(l.recent))
+	}
+	changes := make([]EntryChange, l.revision-revision)
+	copy(changes, l.recent[revision+1-first:])
+	return changes, nil, nil
+}
+
+// Revision returns the revision of the last change of the entries, or 0 if
+// they never changed.
+//
+// A mirror of the entries reads the revision, lists the entries, then
+// watches the changes from the revision: the changes made while listing are
+// then received again, and applying them is harmless.
+func (c *Cron) Revision() uint64 {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	return c.changes.revision
+}
+
+// Watch returns a channel receiving the changes of the entries made after
+// the given revision, in order, until ctx is done. The changes still kept
+// are received first, see WithWatchHistory. A revision whose changes are no
+// longer kept, or that the Cron never reached, e.g. after a restart, fails
+// with ErrRevisionUnavailable: the entries have to be listed again.
+//
+// The channel is also closed if the watcher falls so far behind that the
+// changes it did not receive yet are no longer kept; watching again from
+// the revision of the last change received then tells whether it can
+// resume.
+func (c *Cron) Watch(ctx context.Context, revision uint64) (<-chan EntryChange, error) {
+	if _, _, err := c.changes.since(revision); err != nil {
+		return nil, err
+	}
+	ch := make(chan EntryChange)
+	go func() {
+		defer close(ch)
+		for {
+			changes, changed, err := c.changes.since(revision)
+			if err != nil {
+				return
+			}
+			for _, change := range changes {
+				select {
+				case ch <- change:
+					revision = change.Revision
+				case <-ctx.Done():
+					return
+				}
+			}
+			if changed == nil {
+				continue
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// changed records a change of the entry e with the given id, which is nil
+// if it was removed, and updates the revision of e if it was added or
+// replaced. It must be called through do.
+func (c *Cron) changed(typ ChangeType, id string, e *Entry) {
+	c.upcoming.touch(id)
+	change := EntryChange{Type: typ, ID: id, Time: c.now()}
+	if e != nil {
+		cp := c.copyEntry(id, e)
+		change.Status = cp.Status
+		if typ != ChangeStatus {
+			change.Entry = cp
+		}
+	}
+	if revision := c.changes.record(change); change.Entry != nil {
+		e.Revision = revision
+	}
+}
+
+// disabled records that the entry with the given id was disabled, outside
+// of the run loop.
+func (c *Cron) disabled(id string) {
+	c.changes.record(EntryChange{Type: ChangeStatus, ID: id, Time: c.now(), Status: EntryDisabled})
+}
@@ -0,0 +1,107 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	c := New()
+	c.AddJob("@every 1h", &chainJob{id: "a"})
+	rev := c.Revision()
+	if rev != 1 {
+		t.Fatalf("expected revision 1, got %d", rev)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := c.Watch(ctx, rev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AddJob("@every 1m", &chainJob{id: "b"})
+	c.ReplaceJob("b", JobDefinition{Spec: "@every 2m", Job: &chainJob{id: "b"}})
+	c.Pause("a")
+	c.Pause("a")
+	c.RemoveJob("b")
+
+	want := []struct {
+		typ    ChangeType
+		id     string
+		status EntryStatus
+	}{
+		{ChangeAdded, "b", EntryScheduled},
+		{ChangeUpdated, "b", EntryScheduled},
+		{ChangeStatus, "a", EntryPaused},
+		{ChangeRemoved, "b", ""},
+	}
+	for i, w := range want {
+		select {
+		case ch := <-changes:
+			if ch.Revision != rev+uint64(i)+1 || ch.Type != w.typ || ch.ID != w.id || ch.Status != w.status {
+				t.Errorf("change %d: expected %s %s %s, got %+v", i, w.typ, w.id, w.status, ch)
+			}
+			if (ch.Entry != nil) != (w.typ == ChangeAdded || w.typ == ChangeUpdated) {
+				t.Errorf("change %d: unexpected entry %v", i, ch.Entry)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected change %d", i)
+		}
+	}
+	if ch := <-watchOnce(t, c, 2); ch.Entry.Spec != "@every 2m" || ch.Entry.Version != 2 {
+		t.Errorf("expected version 2 of b, got %+v", ch.Entry)
+	}
+
+	cancel()
+	if _, ok := <-changes; ok {
+		t.Error("expected the changes to be closed")
+	}
+}
+
+// watchOnce returns a channel receiving the change following the given
+// revision.
+func watchOnce(t *testing.T, c *Cron, revision uint64) <-chan EntryChange {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	changes, err := c.Watch(ctx, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
+
+func TestWatchRevisionUnavailable(t *testing.T) {
+	c := New(WithWatchHistory(2))
+	for _, id := range []string{"a", "b", "c"} {
+		c.AddJob("@every 1h", &chainJob{id: id})
+	}
+	if _, err := c.Watch(context.Background(), 0); !errors.Is(err, ErrRevisionUnavailable) {
+		t.Errorf("expected the first change to be dropped, got %v", err)
+	}
+	if _, err := c.Watch(context.Background(), 4); !errors.Is(err, ErrRevisionUnavailable) {
+		t.Errorf("expected a revision ahead to be unavailable, got %v", err)
+	}
+	if ch := <-watchOnce(t, c, 1); ch.Revision != 2 || ch.ID != "b" {
+		t.Errorf("expected to resume from b, got %+v", ch)
+	}
+}
+
+func TestWatchDisabled(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock), WithDisableAfter(1))
+	c.AddJob("@every 1h", &chainJob{id: "a", err: errors.New("boom")})
+	c.Start()
+	defer c.Stop()
+	changes := watchOnce(t, c, c.Revision())
+
+	clock.Advance(time.Hour)
+	if ch := <-changes; ch.Type != ChangeStatus || ch.Status != EntryDisabled {
+		t.Errorf("expected a to be disabled, got %+v", ch)
+	}
+	c.Enable("a")
+	if ch := <-changes; ch.Type != ChangeStatus || ch.Status != EntryScheduled {
+		t.Errorf("expected a to be enabled, got %+v", ch)
+	}
+}
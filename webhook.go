@@ -0,0 +1,179 @@
+package cron
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of a webhook
+// body, as "sha256=<hex>".
+const WebhookSignatureHeader = "X-Cron-Signature"
+
+// WebhookSink delivers job results as JSON to HTTP endpoints. Failed
+// deliveries are retried with exponential backoff and finally kept in a
+// bounded dead-letter buffer.
+type WebhookSink struct {
+	// URLs receive a POST request for every result.
+	URLs []string
+	// Secret signs request bodies if not empty.
+	Secret []byte
+	// Client sends the requests. It defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+	// MaxRetries is the number of retries after a failed delivery.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for every
+	// following one.
+	Backoff time.Duration
+	// DeadLetterSize is the number of failed deliveries kept.
+	DeadLetterSize int
+	// Clock times the payloads, the dead letters and the backoff. It
+	// defaults to the system clock, or to the clock of the Cron for Attach.
+	Clock Clock
+
+	mu          sync.Mutex
+	deadLetters []DeadLetter
+}
+
+// DeadLetter is a webhook delivery that failed for good.
+type DeadLetter struct {
+	URL     string
+	Payload []byte
+	Error   string
+	Time    time.Time
+}
+
+// webhookPayload is the JSON document posted for a result: the result
+// encoded like by JobResult.MarshalJSON, whose idempotency key is the same
+// for every delivery, and the time it was posted at.
+type webhookPayload struct {
+	resultJSON
+	Time time.Time `json:"time"`
+}
+
+// NewWebhookSink returns a sink posting results to the given URLs, signed
+// with secret, retrying failed deliveries 3 times.
+func NewWebhookSink(secret string, urls ...string) *WebhookSink {
+	return &WebhookSink{
+		URLs:           urls,
+		Secret:         []byte(secret),
+		Client:         &http.Client{Timeout: 10 * time.Second},
+		MaxRetries:     3,
+		Backoff:        time.Second,
+		DeadLetterSize: 100,
+	}
+}
+
+// Attach delivers the results of c's jobs until the returned function is
+// called. Up to buffer results wait for delivery before new ones are
+// dropped.
+func (s *WebhookSink) Attach(c *Cron, buffer int) (detach func()) {
+	if s.Clock == nil {
+		s.Clock = c.clock
+	}
+	results, cancel := c.SubscribeResults(buffer)
+	go func() {
+		for r := range results {
+			s.Deliver(r)
+		}
+	}()
+	return cancel
+}
+
+// Deliver posts the result to every URL, retrying failed deliveries.
+func (s *WebhookSink) Deliver(r *JobResult) {
+	p := webhookPayload{newResultJSON(r), s.clock().Now()}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	for _, url := range s.URLs {
+		if err := s.deliver(url, body); err != nil {
+			s.deadLetter(DeadLetter{URL: url, Payload: body, Error: err.Error(), Time: s.clock().Now()})
+		}
+	}
+}
+
+// clock returns the clock of the sink.
+func (s *WebhookSink) clock() Clock {
+	if s.Clock == nil {
+		return RealClock{}
+	}
+	return s.Clock
+}
+
+func (s *WebhookSink) deliver(url string, body []byte) error {
+	backoff := s.Backoff
+	var err error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			sleep(s.clock(), backoff)
+			backoff *= 2
+		}
+		var retry bool
+		if retry, err = s.post(url, body); err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// post sends a single request and reports whether a failure may be retried.
+func (s *WebhookSink) post(url string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.Secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(s.Secret, body))
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("Webhook %s responded %s", url, resp.Status)
+	}
+	return false, fmt.Errorf("Webhook %s responded %s", url, resp.Status)
+}
+
+func (s *WebhookSink) deadLetter(d DeadLetter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = append(s.deadLetters, d)
+	if n := len(s.deadLetters) - s.DeadLetterSize; n > 0 {
+		s.deadLetters = s.deadLetters[n:]
+	}
+}
+
+// DeadLetters returns the failed deliveries, oldest first.
+func (s *WebhookSink) DeadLetters() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.deadLetters...)
+}
+
+// SignWebhook returns the signature header value of a webhook body, so
+// receivers can verify it with hmac.Equal.
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	var calls int32
+	received := make(chan webhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(WebhookSignatureHeader) != SignWebhook([]byte("secret"), body) {
+			t.Error("unexpected signature")
+		}
+		var p webhookPayload
+		json.Unmarshal(body, &p)
+		received <- p
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink("secret", srv.URL)
+	s.Backoff = time.Millisecond
+	s.Deliver(&JobResult{JobId: "job", Error: errors.New("boom"), Attempt: 1, MaxAttempts: 2})
+
+	select {
+	case p := <-received:
+		if p.JobID != "job" || p.Error != "boom" || p.Attempt != 1 || p.MaxAttempts != 2 || p.Time.IsZero() {
+			t.Errorf("unexpected payload %+v", p)
+		}
+	default:
+		t.Fatal("expected the retried delivery to succeed")
+	}
+	if len(s.DeadLetters()) != 0 {
+		t.Error("expected no dead letters")
+	}
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink("", srv.URL)
+	s.Backoff = time.Millisecond
+	s.DeadLetterSize = 1
+	s.Deliver(&JobResult{JobId: "a"})
+	s.Deliver(&JobResult{JobId: "b"})
+
+	if calls != 2 {
+		t.Errorf("expected client errors not to be retried, got %d calls", calls)
+	}
+	dl := s.DeadLetters()
+	if len(dl) != 1 || dl[0].URL != srv.URL {
+		t.Fatalf("expected 1 dead letter, got %+v", dl)
+	}
+	var p webhookPayload
+	json.Unmarshal(dl[0].Payload, &p)
+	if p.JobID != "b" {
+		t.Errorf("expected the latest dead letter to be kept, got %s", p.JobID)
+	}
+}
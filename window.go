@@ -0,0 +1,117 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// WindowPolicy tells what happens to the runs due during a maintenance
+// window.
+type WindowPolicy string
+
+const (
+	// WindowSkip skips the runs, emitting an EventJobSkipped for each. It is
+	// the policy of windows without one.
+	WindowSkip WindowPolicy = "skip"
+	// WindowDefer runs the job once at the end of the window, if it was due
+	// during the window.
+	WindowDefer WindowPolicy = "defer"
+)
+
+// Window is a maintenance window during which jobs are not run.
+type Window struct {
+	// Name identifies the window, to remove it.
+	Name string
+	// Spec tells when the window opens, e.g. "0 0 2 * * SUN" for a window
+	// on Sundays at 2am.
+	Spec string
+	// Duration is how long the window lasts.
+	Duration time.Duration
+	// Tags restricts the window to the entries with one of these tags. The
+	// window applies to every entry if it is empty.
+	Tags []string
+	// Policy tells what happens to the runs due during the window.
+	Policy WindowPolicy
+}
+
+// window is a Window with its parsed spec.
+type window struct {
+	Window
+	schedule Schedule
+}
+
+// WithTags tags the entry, so it can be selected by maintenance windows.
+func WithTags(tags ...string) EntryOption {
+	return func(e *Entry) {
+		e.Tags = tags
+	}
+}
+
+// AddWindow adds a maintenance window, replacing the one with the same name.
+func (c *Cron) AddWindow(w Window) error {
+	schedule, err := c.parser.Parse(w.Spec)
+	if err != nil {
+		return err
+	}
+	if w.Duration <= 0 {
+		return fmt.Errorf("Window %s has no duration", w.Name)
+	}
+	switch w.Policy {
+	case "", WindowSkip, WindowDefer:
+	default:
+		return fmt.Errorf("Unknown window policy %q", w.Policy)
+	}
+	c.do(func() {
+		c.removeWindow(w.Name)
+		c.windows = append(c.windows, &window{w, schedule})
+	})
+	return nil
+}
+
+// RemoveWindow removes the maintenance window with the given name.
+func (c *Cron) RemoveWindow(name string) {
+	c.do(func() { c.removeWindow(name) })
+}
+
+func (c *Cron) removeWindow(name string) {
+	for i, w := range c.windows {
+		if w.Name == name {
+			c.windows = append(c.windows[:i], c.windows[i+1:]...)
+			return
+		}
+	}
+}
+
+// blackout returns the window in which the run of e due at t falls, and
+// when it ends, or nil. It must be called in the run loop.
+func (c *Cron) blackout(e *Entry, t time.Time) (*window, time.Time) {
+	return blackout(c.windows, e, t)
+}
+
+// blackout returns the window among windows in which the run of e due at t
+// falls, and when it ends, or nil.
+func blackout(windows []*window, e *Entry, t time.Time) (*window, time.Time) {
+	for _, w := range windows {
+		if !w.applies(e) {
+			continue
+		}
+		// The window contains t if it opens in (t-Duration, t].
+		start := w.schedule.Next(t.Add(-w.Duration))
+		if !start.IsZero() && !start.After(t) {
+			return w, start.Add(w.Duration)
+		}
+	}
+	return nil, time.Time{}
+}
+
+func (w *window) applies(e *Entry) bool {
+	if len(w.Tags) == 0 {
+		return true
+	}
+	for _, tag := range w.Tags {
+		if hasTag(e.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"io/ioutil"
+	"log"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWindows(t *testing.T) {
+	// A Sunday.
+	start := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := New(WithClock(clock), WithLocation(time.UTC))
+	c.ErrorLog = log.New(ioutil.Discard, "", 0)
+	runs := make(map[string][]int)
+	record := func(name string) func() (string, error) {
+		return func() (string, error) {
+			now := clock.Now()
+			runs[name] = append(runs[name], now.Hour()*100+now.Minute())
+			return "", nil
+		}
+	}
+	c.AddFunc("0 0 * * * *", record("hourly"))
+	c.AddFunc("0 */30 * * * *", record("db"), WithTags("db"))
+	c.AddFunc("0 */30 * * * *", record("other"), WithTags("other"))
+	windows := []Window{
+		{Name: "all", Spec: "0 0 2 * * SUN", Duration: 2 * time.Hour},
+		{Name: "db", Spec: "0 0 5 * * SUN", Duration: 90 * time.Minute, Tags: []string{"db"}, Policy: WindowDefer},
+	}
+	for _, w := range windows {
+		if err := c.AddWindow(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Start()
+	defer c.Stop()
+
+	clock.Advance(7 * time.Hour)
+	want := map[string][]int{
+		"hourly": {100, 400, 500, 600, 700},
+		"db":     {30, 100, 130, 400, 430, 630, 700},
+		"other":  {30, 100, 130, 400, 430, 500, 530, 600, 630, 700},
+	}
+	for name, w := range want {
+		if got := runs[name]; !equalInts(got, w) {
+			t.Errorf("expected %s to run at %v, got %v", name, w, got)
+		}
+	}
+
+	c.RemoveWindow("all")
+	runs = make(map[string][]int)
+	clock.Advance(7 * 24 * time.Hour)
+	if n := len(runs["hourly"]); n != 7*24 {
+		t.Errorf("expected %d runs without the window, got %d", 7*24, n)
+	}
+}
+
+func TestAddWindowInvalid(t *testing.T) {
+	c := New()
+	for _, w := range []Window{
+		{Name: "spec", Spec: "bad", Duration: time.Hour},
+		{Name: "duration", Spec: "@daily"},
+		{Name: "policy", Spec: "@daily", Duration: time.Hour, Policy: "later"},
+	} {
+		if err := c.AddWindow(w); err == nil {
+			t.Errorf("expected window %s to be rejected", w.Name)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEntryTagsCopied(t *testing.T) {
+	c := New()
+	id, _ := c.AddFunc("@yearly", func() (string, error) { return "", nil }, WithTags("b", "a"))
+	e, _ := c.Entry(id)
+	sort.Strings(e.Tags)
+	if e, _ := c.Entry(id); e.Tags[0] != "b" {
+		t.Errorf("expected the tags of the entry to be left alone, got %v", e.Tags)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/ringtail/go-cron/winsvc
+
+go 1.21
+
+replace github.com/ringtail/go-cron => ../
+
+require (
+	github.com/ringtail/go-cron v0.0.0-00010101000000-000000000000
+	golang.org/x/sys v0.18.0
+)
+
+require (
+	github.com/satori/go.uuid v1.2.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
@@ -0,0 +1,93 @@
+// Package winsvc runs a Cron as a Windows service, mapping the commands of
+// the service control manager to the lifecycle of the scheduler:
+//
+//	Start            starts the Cron
+//	Pause            stops scheduling runs, keeping the entries
+//	Continue         schedules runs again
+//	Stop, Shutdown   stops the Cron, saving its store
+//
+// A typical main function runs the service when started by the service
+// control manager, and the Cron in the foreground otherwise:
+//
+//	if ok, _ := winsvc.IsService(); ok {
+//		if err := winsvc.Run("jobs", c); err != nil {
+//			log.Fatal(err)
+//		}
+//		return
+//	}
+//	c.Run()
+package winsvc
+
+import (
+	"sync"
+
+	cron "github.com/ringtail/go-cron"
+)
+
+// State is the state of the service.
+type State int
+
+const (
+	Stopped State = iota
+	Running
+	Paused
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Paused:
+		return "paused"
+	}
+	return "stopped"
+}
+
+// lifecycle applies the commands of the service control manager to a Cron.
+type lifecycle struct {
+	c *cron.Cron
+
+	mu    sync.Mutex
+	state State
+}
+
+func (l *lifecycle) start() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state == Stopped {
+		l.c.Start()
+		l.state = Running
+	}
+	return l.state
+}
+
+func (l *lifecycle) pause() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state == Running {
+		l.c.Stop()
+		l.state = Paused
+	}
+	return l.state
+}
+
+func (l *lifecycle) resume() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state == Paused {
+		l.c.Start()
+		l.state = Running
+	}
+	return l.state
+}
+
+func (l *lifecycle) stop() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// A paused Cron is already stopped.
+	if l.state == Running {
+		l.c.Stop()
+	}
+	l.state = Stopped
+	return l.state
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package winsvc
+
+import (
+	"errors"
+
+	cron "github.com/ringtail/go-cron"
+)
+
+// ErrNotWindows is returned by Run and RunDebug on other systems.
+var ErrNotWindows = errors.New("Windows services are only supported on Windows")
+
+// IsService reports whether the process was started by the service control
+// manager, which is never the case on other systems.
+func IsService() (bool, error) {
+	return false, nil
+}
+
+// Run returns ErrNotWindows.
+func Run(name string, c *cron.Cron) error {
+	return ErrNotWindows
+}
+
+// RunDebug returns ErrNotWindows.
+func RunDebug(name string, c *cron.Cron) error {
+	return ErrNotWindows
+}
@@ -0,0 +1,52 @@
+package winsvc
+
+import (
+	"testing"
+	"time"
+
+	cron "github.com/ringtail/go-cron"
+)
+
+func TestLifecycle(t *testing.T) {
+	c := cron.New()
+	events, cancel := c.SubscribeEvents(10)
+	defer cancel()
+	expect := func(typ cron.EventType) {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e.Type != typ {
+				t.Errorf("expected %s, got %s", typ, e.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s", typ)
+		}
+	}
+
+	l := &lifecycle{c: c}
+	if s := l.start(); s != Running {
+		t.Errorf("expected running, got %s", s)
+	}
+	expect(cron.EventSchedulerStarted)
+	if s := l.pause(); s != Paused {
+		t.Errorf("expected paused, got %s", s)
+	}
+	expect(cron.EventSchedulerStopped)
+	// Pausing twice or starting a paused service does nothing.
+	if l.pause() != Paused || l.start() != Paused {
+		t.Error("expected the service to stay paused")
+	}
+	if s := l.resume(); s != Running {
+		t.Errorf("expected running, got %s", s)
+	}
+	expect(cron.EventSchedulerStarted)
+	if s := l.stop(); s != Stopped {
+		t.Errorf("expected stopped, got %s", s)
+	}
+	expect(cron.EventSchedulerStopped)
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %s", e.Type)
+	default:
+	}
+}
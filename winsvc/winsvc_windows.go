@@ -0,0 +1,59 @@
+package winsvc
+
+import (
+	cron "github.com/ringtail/go-cron"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/debug"
+)
+
+// accepted are the commands the service accepts while it runs or is paused.
+const accepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+
+// IsService reports whether the process was started by the service control
+// manager.
+func IsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// Run runs c as the service with the given name until the service control
+// manager stops it.
+func Run(name string, c *cron.Cron) error {
+	return svc.Run(name, &handler{lifecycle{c: c}})
+}
+
+// RunDebug runs c as the service with the given name from a console, where
+// Ctrl+C stops it, to debug the service.
+func RunDebug(name string, c *cron.Cron) error {
+	return debug.Run(name, &handler{lifecycle{c: c}})
+}
+
+// handler is the svc.Handler of a Cron.
+type handler struct {
+	lifecycle
+}
+
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	h.start()
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+	for req := range requests {
+		switch req.Cmd {
+		case svc.Interrogate:
+			changes <- req.CurrentStatus
+		case svc.Pause:
+			changes <- svc.Status{State: svc.PausePending, Accepts: accepted}
+			h.pause()
+			changes <- svc.Status{State: svc.Paused, Accepts: accepted}
+		case svc.Continue:
+			changes <- svc.Status{State: svc.ContinuePending, Accepts: accepted}
+			h.resume()
+			changes <- svc.Status{State: svc.Running, Accepts: accepted}
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			h.stop()
+			return false, 0
+		}
+	}
+	h.stop()
+	return false, 0
+}